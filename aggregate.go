@@ -0,0 +1,188 @@
+package dbr
+
+// AggregateBuilder builds an aggregate function call, e.g. `COUNT(*)`
+type AggregateBuilder interface {
+	Builder
+	Filter(cond Builder) AggregateBuilder
+	As(alias string) Builder
+}
+
+type aggregate struct {
+	function string
+	expr     interface{}
+	filter   Builder
+}
+
+func createAggregate(function string, expr interface{}) *aggregate {
+	return &aggregate{
+		function: function,
+		expr:     expr,
+	}
+}
+
+// Count builds `COUNT(expr)`, e.g. Count("*")
+func Count(expr interface{}) AggregateBuilder {
+	return createAggregate("COUNT", expr)
+}
+
+// Sum builds `SUM(expr)`
+func Sum(expr interface{}) AggregateBuilder {
+	return createAggregate("SUM", expr)
+}
+
+// Avg builds `AVG(expr)`
+func Avg(expr interface{}) AggregateBuilder {
+	return createAggregate("AVG", expr)
+}
+
+// Min builds `MIN(expr)`
+func Min(expr interface{}) AggregateBuilder {
+	return createAggregate("MIN", expr)
+}
+
+// Max builds `MAX(expr)`
+func Max(expr interface{}) AggregateBuilder {
+	return createAggregate("MAX", expr)
+}
+
+// distinct is `DISTINCT expr` argument of aggregate
+type distinct struct {
+	expr interface{}
+}
+
+// Distinct makes aggregate use only distinct values of expr, e.g. Count(Distinct("user_id"))
+func Distinct(expr interface{}) Builder {
+	return &distinct{expr: expr}
+}
+
+// Build builds `DISTINCT expr` in dialect
+func (b *distinct) Build(d Dialect, buf Buffer) error {
+	buf.WriteString("DISTINCT ")
+	writeAggregateExpr(b.expr, buf)
+	return nil
+}
+
+// Filter restricts the rows which are aggregated.
+// PostgreSQL uses `FILTER (WHERE cond)`,
+// other dialects emulate it via `CASE WHEN cond THEN expr END`
+func (b *aggregate) Filter(cond Builder) AggregateBuilder {
+	b.filter = cond
+	return b
+}
+
+// As creates alias for aggregate
+func (b *aggregate) As(alias string) Builder {
+	return as(b, alias)
+}
+
+// Build builds aggregate function call in dialect
+func (b *aggregate) Build(d Dialect, buf Buffer) error {
+	keyword := d.AggregateFilter()
+
+	expr := b.expr
+	buf.WriteString(b.function)
+	buf.WriteString("(")
+	if dist, ok := expr.(*distinct); ok {
+		// DISTINCT applies to CASE expression in emulated filter,
+		// e.g. COUNT(DISTINCT CASE WHEN cond THEN user_id END), NULLs are not counted
+		buf.WriteString("DISTINCT ")
+		expr = dist.expr
+	}
+	writeAggregateArg(expr, b.filter, len(keyword) == 0, buf)
+	buf.WriteString(")")
+	writeAggregateFilter(keyword, b.filter, buf)
+	return nil
+}
+
+// writeAggregateArg writes argument of aggregate, filter is emulated via CASE expression if needed
+func writeAggregateArg(expr interface{}, filter Builder, emulateFilter bool, buf Buffer) {
+	if filter != nil && emulateFilter {
+		buf.WriteString("CASE WHEN ")
+		buf.WriteString(placeholder)
+		buf.WriteValue(filter)
+		buf.WriteString(" THEN ")
+		if s, ok := expr.(string); ok && s == "*" {
+			// COUNT(*) counts rows, so any non-null value will do
+			buf.WriteString("1")
+		} else {
+			writeAggregateExpr(expr, buf)
+		}
+		buf.WriteString(" END")
+	} else {
+		writeAggregateExpr(expr, buf)
+	}
+}
+
+// writeAggregateFilter writes `FILTER (WHERE cond)` after aggregate if dialect supports it
+func writeAggregateFilter(keyword string, filter Builder, buf Buffer) {
+	if filter != nil && len(keyword) > 0 {
+		buf.WriteString(" ")
+		buf.WriteString(keyword)
+		buf.WriteString(" (WHERE ")
+		buf.WriteString(placeholder)
+		buf.WriteValue(filter)
+		buf.WriteString(")")
+	}
+}
+
+func writeAggregateExpr(expr interface{}, buf Buffer) {
+	switch expr := expr.(type) {
+	case string:
+		buf.WriteString(expr)
+	default:
+		buf.WriteString(placeholder)
+		buf.WriteValue(expr)
+	}
+}
+
+type stringAgg struct {
+	expr      interface{}
+	separator string
+	filter    Builder
+}
+
+// StringAgg concatenates values of expr with separator, NULLs are skipped.
+// It is `GROUP_CONCAT(expr SEPARATOR sep)` in MySQL, `string_agg(expr, sep)` in PostgreSQL, which requires text expr,
+// `group_concat(expr, sep)` in SQLite3 and `arrayStringConcat(groupArray(expr), sep)` in ClickHouse.
+// Distinct(expr) is supported by all dialects except SQLite3, which returns ErrStringAggNotSupported
+func StringAgg(expr interface{}, separator string) AggregateBuilder {
+	return &stringAgg{
+		expr:      expr,
+		separator: separator,
+	}
+}
+
+// Filter restricts the rows which are aggregated
+func (b *stringAgg) Filter(cond Builder) AggregateBuilder {
+	b.filter = cond
+	return b
+}
+
+// As creates alias for aggregate
+func (b *stringAgg) As(alias string) Builder {
+	return as(b, alias)
+}
+
+// Build builds string aggregation in dialect
+func (b *stringAgg) Build(d Dialect, buf Buffer) error {
+	keyword := d.AggregateFilter()
+
+	expr := b.expr
+	dist, isDistinct := expr.(*distinct)
+	if isDistinct {
+		expr = dist.expr
+	}
+	template := d.StringAgg(isDistinct, b.separator)
+	if len(template) == 0 {
+		return ErrStringAggNotSupported
+	}
+
+	// template has placeholder for expr, separator is written in it as a literal
+	buf.WriteString(template)
+	buf.WriteValue(BuildFunc(func(d Dialect, buf Buffer) error {
+		writeAggregateArg(expr, b.filter, len(keyword) == 0, buf)
+		return nil
+	}))
+	writeAggregateFilter(keyword, b.filter, buf)
+	return nil
+}
@@ -0,0 +1,179 @@
+package dbr
+
+import (
+	"testing"
+
+	"github.com/mailru/dbr/dialect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregate(t *testing.T) {
+	for _, test := range []struct {
+		agg   Builder
+		d     Dialect
+		query string
+	}{
+		{
+			agg:   Count("*"),
+			d:     dialect.MySQL,
+			query: "COUNT(*)",
+		},
+		{
+			agg:   Sum("amount").As("total"),
+			d:     dialect.MySQL,
+			query: "SUM(amount) AS `total`",
+		},
+		{
+			agg:   Max(I("t.amount")),
+			d:     dialect.PostgreSQL,
+			query: `MAX("t"."amount")`,
+		},
+		{
+			agg:   Count("*").Filter(Gt("amount", 100)),
+			d:     dialect.PostgreSQL,
+			query: `COUNT(*) FILTER (WHERE "amount" > 100)`,
+		},
+		{
+			agg:   Avg("amount").Filter(Eq("state", "paid")).As("avg_paid"),
+			d:     dialect.SQLite3,
+			query: `AVG(CASE WHEN "state" = 'paid' THEN amount END) AS "avg_paid"`,
+		},
+		{
+			agg:   Count("*").Filter(Gt("amount", 100)),
+			d:     dialect.MySQL,
+			query: "COUNT(CASE WHEN `amount` > 100 THEN 1 END)",
+		},
+		{
+			agg:   Count(Distinct("user_id")),
+			d:     dialect.MySQL,
+			query: "COUNT(DISTINCT user_id)",
+		},
+		{
+			agg:   Count(Distinct(I("user_id"))).Filter(Gt("amount", 100)),
+			d:     dialect.PostgreSQL,
+			query: `COUNT(DISTINCT "user_id") FILTER (WHERE "amount" > 100)`,
+		},
+		{
+			agg:   Count(Distinct("user_id")).Filter(Gt("amount", 100)).As("buyers"),
+			d:     dialect.MySQL,
+			query: "COUNT(DISTINCT CASE WHEN `amount` > 100 THEN user_id END) AS `buyers`",
+		},
+		{
+			agg:   Sum(Distinct("amount")).Filter(Eq("state", "paid")),
+			d:     dialect.ClickHouse,
+			query: "SUM(DISTINCT CASE WHEN `state` = 'paid' THEN amount END)",
+		},
+		{
+			agg:   Min("amount").Filter(And(Eq("state", "paid"), Lt("amount", 5))),
+			d:     dialect.ClickHouse,
+			query: "MIN(CASE WHEN (`state` = 'paid') AND (`amount` < 5) THEN amount END)",
+		},
+	} {
+		s, err := InterpolateForDialect("?", []interface{}{test.agg}, test.d)
+		assert.NoError(t, err)
+		assert.Equal(t, test.query, s)
+	}
+}
+
+func TestAggregateFilterSQLite3(t *testing.T) {
+	// the emulation runs on the bundled SQLite, which has no FILTER
+	buf := NewBuffer()
+	err := Select(Count("*").Filter(Gt("n", 1)).As("big"), Count(Distinct("n")).Filter(Lt("n", 3)).As("small")).
+		From(Expr("(SELECT 1 AS n UNION ALL SELECT 2 UNION ALL SELECT 3)")).
+		Build(dialect.SQLite3, buf)
+	assert.NoError(t, err)
+	var count struct {
+		Big   int64
+		Small int64
+	}
+	err = sqlite3Session.SelectBySql(buf.String(), buf.Value()...).LoadStruct(&count)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count.Big)
+	assert.Equal(t, int64(2), count.Small)
+}
+
+func TestAggregateFilterArgs(t *testing.T) {
+	stmt := Select("a", Count("*").Filter(Gt("b", 1)).As("c")).From("table").Where(Eq("d", 2))
+	for _, d := range []Dialect{dialect.PostgreSQL, dialect.MySQL} {
+		buf := NewBuffer()
+		err := stmt.Build(d, buf)
+		assert.NoError(t, err)
+
+		// filter condition args are interpolated in place, before where condition args
+		i := interpolator{
+			Buffer:       NewBuffer(),
+			Dialect:      d,
+			IgnoreBinary: true,
+		}
+		err = i.interpolate(buf.String(), buf.Value())
+		assert.NoError(t, err)
+		if d == dialect.PostgreSQL {
+			assert.Equal(t, `SELECT a, COUNT(*) FILTER (WHERE "b" > 1) AS "c" FROM table WHERE ("d" = 2)`, i.String())
+		} else {
+			assert.Equal(t, "SELECT a, COUNT(CASE WHEN `b` > 1 THEN 1 END) AS `c` FROM table WHERE (`d` = 2)", i.String())
+		}
+	}
+}
+
+func TestStringAgg(t *testing.T) {
+	for _, test := range []struct {
+		agg   Builder
+		d     Dialect
+		query string
+	}{
+		{
+			agg:   StringAgg("name", ", ").As("names"),
+			d:     dialect.MySQL,
+			query: "GROUP_CONCAT(name SEPARATOR ', ') AS `names`",
+		},
+		{
+			agg:   StringAgg(Distinct(I("name")), "'; --"),
+			d:     dialect.MySQL,
+			query: "GROUP_CONCAT(DISTINCT `name` SEPARATOR '\\'; --')",
+		},
+		{
+			agg:   StringAgg("name", ","),
+			d:     dialect.PostgreSQL,
+			query: "string_agg(name, ',')",
+		},
+		{
+			agg:   StringAgg(Distinct("name"), "'").Filter(Eq("state", "paid")),
+			d:     dialect.PostgreSQL,
+			query: `string_agg(DISTINCT name, '''') FILTER (WHERE "state" = 'paid')`,
+		},
+		{
+			agg:   StringAgg("name", "|"),
+			d:     dialect.SQLite3,
+			query: "group_concat(name, '|')",
+		},
+		{
+			agg:   StringAgg("name", ","),
+			d:     dialect.ClickHouse,
+			query: "arrayStringConcat(groupArray(name), ',')",
+		},
+		{
+			agg:   StringAgg(Distinct("name"), ",").Filter(Eq("state", "paid")),
+			d:     dialect.ClickHouse,
+			query: "arrayStringConcat(groupUniqArray(CASE WHEN `state` = 'paid' THEN name END), ',')",
+		},
+		{
+			agg:   StringAgg("name", ",").Filter(Eq("state", "paid")),
+			d:     dialect.MySQL,
+			query: "GROUP_CONCAT(CASE WHEN `state` = 'paid' THEN name END SEPARATOR ',')",
+		},
+	} {
+		s, err := InterpolateForDialect("?", []interface{}{test.agg}, test.d)
+		assert.NoError(t, err)
+		assert.Equal(t, test.query, s)
+	}
+
+	_, err := InterpolateForDialect("?", []interface{}{StringAgg(Distinct("name"), ",")}, dialect.SQLite3)
+	assert.Equal(t, ErrStringAggNotSupported, err)
+
+	// separator is a literal, MySQL does not accept a placeholder in SEPARATOR
+	i := interpolator{Buffer: NewBuffer(), Dialect: dialect.MySQL, UsePlaceholders: true}
+	err = i.interpolate("?", []interface{}{StringAgg("name", ", ").Filter(Eq("state", "paid"))})
+	assert.NoError(t, err)
+	assert.Equal(t, "GROUP_CONCAT(CASE WHEN `state` = ? THEN name END SEPARATOR ', ')", i.String())
+	assert.Equal(t, []interface{}{"paid"}, i.Value())
+}
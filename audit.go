@@ -0,0 +1,55 @@
+package dbr
+
+// redactedValue replaces sensitive values in the audit log
+const redactedValue = "***"
+
+// sensitive is a value masked in the audit log of transaction
+type sensitive struct {
+	value interface{}
+}
+
+// Sensitive marks value to be masked in the audit log of transaction, e.g. a password hash,
+// the value is still sent to the database as is
+func Sensitive(value interface{}) interface{} {
+	return sensitive{value: value}
+}
+
+// EnableAuditLog makes the transaction record the interpolated SQL of every statement executed within it,
+// values marked by Sensitive are masked
+func (tx *Tx) EnableAuditLog() {
+	tx.auditMu.Lock()
+	tx.audit = true
+	tx.auditMu.Unlock()
+}
+
+// AuditLog returns statements executed within the transaction in order,
+// it is empty unless EnableAuditLog is called
+func (tx *Tx) AuditLog() []string {
+	tx.auditMu.Lock()
+	defer tx.auditMu.Unlock()
+	return append([]string(nil), tx.auditLog...)
+}
+
+// auditQuery records builder in the audit log if runner is a transaction with enabled audit log
+func auditQuery(runner runner, builder Builder, d Dialect) {
+	tx, ok := runner.(*Tx)
+	if !ok {
+		return
+	}
+	tx.auditMu.Lock()
+	defer tx.auditMu.Unlock()
+	if !tx.audit {
+		return
+	}
+	i := interpolator{
+		Buffer:      NewBuffer(),
+		Dialect:     d,
+		Redact:      true,
+		KeywordCase: tx.KeywordCase,
+	}
+	err := i.interpolate(placeholder, []interface{}{builder})
+	if err != nil {
+		return
+	}
+	tx.auditLog = append(tx.auditLog, tagQuery(runner, i.String()))
+}
@@ -13,3 +13,22 @@ type BuildFunc func(Dialect, Buffer) error
 func (b BuildFunc) Build(d Dialect, buf Buffer) error {
 	return b(d, buf)
 }
+
+// Stats returns the length of sql built by builder in dialect d with placeholders for all values and
+// the number of the values, e.g. to reject queries over the limit of arguments before executing them.
+// It builds the query as it is built for execution, so expandable values like slices are counted
+// by their elements, and it has no side effects. Settings of session are not applied: sqlLen is of the query
+// with placeholders of d, e.g. `$1` in PostgreSQL, without the tag of session and interpolated values,
+// KeywordCase does not change it
+func Stats(builder Builder, d Dialect) (sqlLen int, argCount int, err error) {
+	i := interpolator{
+		Buffer:          NewBuffer(),
+		Dialect:         d,
+		IgnoreBinary:    true,
+		UsePlaceholders: true,
+	}
+	if err := i.interpolate(placeholder, []interface{}{builder}); err != nil {
+		return 0, 0, err
+	}
+	return len(i.String()), len(i.Value()), nil
+}
@@ -0,0 +1,26 @@
+package dbr
+
+import (
+	"testing"
+
+	"github.com/mailru/dbr/dialect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	stmt := Select("id").From("dbr_people").Where(Eq("id", []int64{1, 2, 3})).Where("name = ?", "a")
+
+	sqlLen, argCount, err := Stats(stmt, dialect.PostgreSQL)
+	assert.NoError(t, err)
+	assert.Equal(t, len(`SELECT id FROM dbr_people WHERE ("id" IN ($1, $2, $3)) AND (name = $4)`), sqlLen)
+	assert.Equal(t, 4, argCount)
+
+	// building does not change the stmt
+	sqlLen2, argCount2, err := Stats(stmt, dialect.PostgreSQL)
+	assert.NoError(t, err)
+	assert.Equal(t, sqlLen, sqlLen2)
+	assert.Equal(t, argCount, argCount2)
+
+	_, _, err = Stats(Select("id").From("dbr_people").Where("id = ?"), dialect.MySQL)
+	assert.IsType(t, &PlaceholderCountError{}, err)
+}
@@ -34,7 +34,7 @@ func Or(cond ...Builder) Builder {
 }
 
 func buildCmp(d Dialect, buf Buffer, pred, column string, value interface{}) error {
-	buf.WriteString(d.QuoteIdent(column))
+	buf.WriteString(quoteColumn(d, column))
 	buf.WriteString(" ")
 	buf.WriteString(pred)
 	buf.WriteString(" ")
@@ -51,13 +51,10 @@ func buildCmp(d Dialect, buf Buffer, pred, column string, value interface{}) err
 func Eq(column string, value interface{}) Builder {
 	return BuildFunc(func(d Dialect, buf Buffer) error {
 		if value == nil {
-			buf.WriteString(d.QuoteIdent(column))
-			buf.WriteString(" IS NULL")
-			return nil
+			return IsNull(column).Build(d, buf)
 		}
-		v := reflect.ValueOf(value)
-		if v.Kind() == reflect.Slice || v.Kind() == reflect.Map {
-			if v.Len() == 0 {
+		if isExpandable(value) {
+			if reflect.ValueOf(value).Len() == 0 {
 				buf.WriteString(d.EncodeBool(false))
 				return nil
 			}
@@ -74,13 +71,10 @@ func Eq(column string, value interface{}) Builder {
 func Neq(column string, value interface{}) Builder {
 	return BuildFunc(func(d Dialect, buf Buffer) error {
 		if value == nil {
-			buf.WriteString(d.QuoteIdent(column))
-			buf.WriteString(" IS NOT NULL")
-			return nil
+			return IsNotNull(column).Build(d, buf)
 		}
-		v := reflect.ValueOf(value)
-		if v.Kind() == reflect.Slice || v.Kind() == reflect.Map {
-			if v.Len() == 0 {
+		if isExpandable(value) {
+			if reflect.ValueOf(value).Len() == 0 {
 				buf.WriteString(d.EncodeBool(true))
 				return nil
 			}
@@ -90,6 +84,24 @@ func Neq(column string, value interface{}) Builder {
 	})
 }
 
+// IsNull is `IS NULL`, it is the same as Eq with nil value.
+func IsNull(column string) Builder {
+	return BuildFunc(func(d Dialect, buf Buffer) error {
+		buf.WriteString(quoteColumn(d, column))
+		buf.WriteString(" IS NULL")
+		return nil
+	})
+}
+
+// IsNotNull is `IS NOT NULL`, it is the same as Neq with nil value.
+func IsNotNull(column string) Builder {
+	return BuildFunc(func(d Dialect, buf Buffer) error {
+		buf.WriteString(quoteColumn(d, column))
+		buf.WriteString(" IS NOT NULL")
+		return nil
+	})
+}
+
 // Gt is `>`.
 func Gt(column string, value interface{}) Builder {
 	return BuildFunc(func(d Dialect, buf Buffer) error {
@@ -117,3 +129,126 @@ func Lte(column string, value interface{}) Builder {
 		return buildCmp(d, buf, "<=", column, value)
 	})
 }
+
+// Conditions builds a group of conditions, see WhereGroup. Conditions are joined by AND, which
+// binds tighter than OR, Or starts the next group of conditions, e.g. `w.Eq("a", 1).Gt("b", 2).Or().IsNull("c")`
+// is `((a = 1) AND (b > 2)) OR (c IS NULL)`. The conditions are built in the order they are added
+type Conditions struct {
+	group [][]Builder
+}
+
+// add adds cond to the current group of conditions
+func (c *Conditions) add(cond Builder) *Conditions {
+	if g, ok := cond.(*Conditions); ok && g.empty() {
+		return c
+	}
+	if len(c.group) == 0 {
+		c.group = append(c.group, nil)
+	}
+	last := len(c.group) - 1
+	c.group[last] = append(c.group[last], cond)
+	return c
+}
+
+// Where adds a condition like Where of SelectStmt, query is a string with placeholders or a Builder
+func (c *Conditions) Where(query interface{}, value ...interface{}) *Conditions {
+	switch query := query.(type) {
+	case string:
+		return c.add(Expr(query, value...))
+	case Builder:
+		return c.add(query)
+	}
+	return c
+}
+
+// Eq adds Eq condition
+func (c *Conditions) Eq(column string, value interface{}) *Conditions {
+	return c.add(Eq(column, value))
+}
+
+// Neq adds Neq condition
+func (c *Conditions) Neq(column string, value interface{}) *Conditions {
+	return c.add(Neq(column, value))
+}
+
+// IsNull adds IsNull condition
+func (c *Conditions) IsNull(column string) *Conditions {
+	return c.add(IsNull(column))
+}
+
+// IsNotNull adds IsNotNull condition
+func (c *Conditions) IsNotNull(column string) *Conditions {
+	return c.add(IsNotNull(column))
+}
+
+// Gt adds Gt condition
+func (c *Conditions) Gt(column string, value interface{}) *Conditions {
+	return c.add(Gt(column, value))
+}
+
+// Gte adds Gte condition
+func (c *Conditions) Gte(column string, value interface{}) *Conditions {
+	return c.add(Gte(column, value))
+}
+
+// Lt adds Lt condition
+func (c *Conditions) Lt(column string, value interface{}) *Conditions {
+	return c.add(Lt(column, value))
+}
+
+// Lte adds Lte condition
+func (c *Conditions) Lte(column string, value interface{}) *Conditions {
+	return c.add(Lte(column, value))
+}
+
+// empty reports whether there are no conditions
+func (c *Conditions) empty() bool {
+	for _, cond := range c.group {
+		if len(cond) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Group adds a parenthesized group of conditions built by fn, an empty group is skipped, e.g. `(a = 1) AND ((b = 2) OR (c = 3))`
+// of `w.Eq("a", 1).Group(func(g *Conditions) { g.Eq("b", 2).Or().Eq("c", 3) })`
+func (c *Conditions) Group(fn func(*Conditions)) *Conditions {
+	return c.add(group(fn))
+}
+
+// Or starts the next group of conditions, which is joined to the previous ones by OR
+func (c *Conditions) Or() *Conditions {
+	if len(c.group) > 0 && len(c.group[len(c.group)-1]) > 0 {
+		c.group = append(c.group, nil)
+	}
+	return c
+}
+
+// Build builds the conditions in dialect, it returns ErrNoConditions if there are none
+func (c *Conditions) Build(d Dialect, buf Buffer) error {
+	var or []Builder
+	for _, cond := range c.group {
+		switch len(cond) {
+		case 0:
+		case 1:
+			or = append(or, cond[0])
+		default:
+			or = append(or, And(cond...))
+		}
+	}
+	switch len(or) {
+	case 0:
+		return ErrNoConditions
+	case 1:
+		return or[0].Build(d, buf)
+	}
+	return Or(or...).Build(d, buf)
+}
+
+// group returns conditions built by fn
+func group(fn func(*Conditions)) *Conditions {
+	c := new(Conditions)
+	fn(c)
+	return c
+}
@@ -88,6 +88,21 @@ func TestCondition(t *testing.T) {
 			query: "`col` <= ?",
 			value: []interface{}{1},
 		},
+		{
+			cond:  IsNull("col"),
+			query: "`col` IS NULL",
+			value: nil,
+		},
+		{
+			cond:  IsNotNull("col"),
+			query: "`col` IS NOT NULL",
+			value: nil,
+		},
+		{
+			cond:  Or(IsNull("a"), And(IsNotNull("b"), Gt("b", 2)), IsNull("t.c")),
+			query: "(`a` IS NULL) OR ((`b` IS NOT NULL) AND (`b` > ?)) OR (`t`.`c` IS NULL)",
+			value: []interface{}{2},
+		},
 		{
 			cond:  And(Lt("a", 1), Or(Gt("b", 2), Neq("c", 3))),
 			query: "(`a` < ?) AND ((`b` > ?) OR (`c` != ?))",
@@ -101,3 +116,72 @@ func TestCondition(t *testing.T) {
 		assert.Equal(t, test.value, buf.Value())
 	}
 }
+
+func TestWhereGroup(t *testing.T) {
+	for _, test := range []struct {
+		stmt  SelectStmt
+		query string
+	}{
+		{
+			stmt: Select("*").From("items").WhereGroup(func(w *Conditions) {
+				w.Eq("a", 1).Or().Gt("b", 2)
+			}),
+			query: "SELECT * FROM items WHERE ((`a` = 1) OR (`b` > 2))",
+		},
+		{
+			// AND binds tighter than OR
+			stmt: Select("*").From("items").Where(Eq("deleted", false)).WhereGroup(func(w *Conditions) {
+				w.Eq("a", 1).Lt("b", 2).Or().IsNull("c").Or().Where("d IN ?", []int{3, 4})
+			}),
+			query: "SELECT * FROM items WHERE (`deleted` = 0) AND (((`a` = 1) AND (`b` < 2)) OR (`c` IS NULL) OR (d IN (3,4)))",
+		},
+		{
+			// nested groups
+			stmt: Select("*").From("items").WhereGroup(func(w *Conditions) {
+				w.Eq("a", 1).Group(func(g *Conditions) {
+					g.Gte("b", 2).Or().Group(func(g *Conditions) {
+						g.Lte("c", 3).Neq("d", "x")
+					})
+				}).Or().IsNotNull("e")
+			}),
+			query: "SELECT * FROM items WHERE (((`a` = 1) AND ((`b` >= 2) OR ((`c` <= 3) AND (`d` != 'x')))) OR (`e` IS NOT NULL))",
+		},
+		{
+			// empty groups and leading or trailing Or are skipped
+			stmt: Select("*").From("items").WhereGroup(func(w *Conditions) {}).WhereGroup(func(w *Conditions) {
+				w.Or().Group(func(*Conditions) {}).Eq("a", 1).Or().Or()
+			}),
+			query: "SELECT * FROM items WHERE (`a` = 1)",
+		},
+	} {
+		buf := NewBuffer()
+		err := test.stmt.Build(dialect.MySQL, buf)
+		assert.NoError(t, err)
+		query, err := InterpolateForDialect(buf.String(), buf.Value(), dialect.MySQL)
+		assert.NoError(t, err)
+		assert.Equal(t, test.query, query)
+	}
+
+	// values are bound in the order of conditions
+	stmt := Select("*").From("items").Where(Eq("x", 0)).WhereGroup(func(w *Conditions) {
+		w.Eq("a", 1).Group(func(g *Conditions) {
+			g.Gt("b", 2).Or().Where("c = ?", 3)
+		}).Or().Eq("d", 4)
+	}).Where(Eq("y", 5))
+	buf := NewBuffer()
+	err := stmt.Build(dialect.PostgreSQL, buf)
+	assert.NoError(t, err)
+	i := interpolator{
+		Buffer:          NewBuffer(),
+		Dialect:         dialect.PostgreSQL,
+		IgnoreBinary:    true,
+		UsePlaceholders: true,
+	}
+	err = i.interpolate(buf.String(), buf.Value())
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM items WHERE ("x" = $1) AND ((("a" = $2) AND (("b" > $3) OR (c = $4))) OR ("d" = $5)) AND ("y" = $6)`, i.String())
+	assert.Equal(t, []interface{}{0, 1, 2, 3, 4, 5}, i.Value())
+
+	err = new(Conditions).Build(dialect.MySQL, NewBuffer())
+	assert.Equal(t, ErrNoConditions, err)
+}
@@ -0,0 +1,92 @@
+package dbr
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// CSVOptions configures WriteCSVWithOpts
+type CSVOptions struct {
+	// Null is written for NULL values, empty by default
+	Null string
+	// Comma is the field delimiter, ',' by default
+	Comma rune
+}
+
+// WriteCSV runs query and writes its rows as CSV to w, the first row is a header with column names.
+// Rows are streamed without loading, it returns the number of written rows excluding header
+func (b *selectBuilder) WriteCSV(ctx context.Context, w io.Writer) (int, error) {
+	return b.WriteCSVWithOpts(ctx, w, CSVOptions{})
+}
+
+// WriteCSVWithOpts is like WriteCSV, but with options, e.g. how NULL is written
+func (b *selectBuilder) WriteCSVWithOpts(ctx context.Context, w io.Writer, opts CSVOptions) (int, error) {
+	return b.queryRows(ctx, func(rows *sql.Rows) (int, error) {
+		return writeCSV(rows, w, opts)
+	})
+}
+
+func writeCSV(rows *sql.Rows, w io.Writer, opts CSVOptions) (int, error) {
+	column, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	cw := csv.NewWriter(w)
+	if opts.Comma != 0 {
+		cw.Comma = opts.Comma
+	}
+	if err := cw.Write(column); err != nil {
+		return 0, err
+	}
+
+	value := make([]interface{}, len(column))
+	ptr := make([]interface{}, len(column))
+	for i := range value {
+		ptr[i] = &value[i]
+	}
+	record := make([]string, len(column))
+	count := 0
+	for rows.Next() {
+		if err := rows.Scan(ptr...); err != nil {
+			return count, err
+		}
+		for i, v := range value {
+			record[i] = formatCSVValue(v, opts.Null)
+		}
+		if err := cw.Write(record); err != nil {
+			return count, err
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return count, err
+	}
+	cw.Flush()
+	return count, cw.Error()
+}
+
+func formatCSVValue(v interface{}, null string) string {
+	switch v := v.(type) {
+	case nil:
+		return null
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
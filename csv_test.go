@@ -0,0 +1,48 @@
+package dbr
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteCSV(t *testing.T) {
+	sess, dbmock, _ := newRecordingSessionMock()
+	created := time.Date(2009, 1, 3, 18, 15, 5, 0, time.UTC)
+	newRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "name", "note", "created_at"}).
+			AddRow(int64(1), []byte("Barack"), nil, created).
+			AddRow(int64(2), "Obama, Jr.", "line1\nline \"2\"", created)
+	}
+
+	dbmock.ExpectQuery("SELECT id, name, note, created_at FROM dbr_people").WillReturnRows(newRows())
+	var buf bytes.Buffer
+	n, err := sess.Select("id", "name", "note", "created_at").From("dbr_people").WriteCSV(context.Background(), &buf)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, "id,name,note,created_at\n"+
+		"1,Barack,,2009-01-03T18:15:05Z\n"+
+		"2,\"Obama, Jr.\",\"line1\nline \"\"2\"\"\",2009-01-03T18:15:05Z\n", buf.String())
+
+	dbmock.ExpectQuery("SELECT id, name, note, created_at FROM dbr_people").WillReturnRows(newRows())
+	buf.Reset()
+	n, err = sess.Select("id", "name", "note", "created_at").From("dbr_people").
+		WriteCSVWithOpts(context.Background(), &buf, CSVOptions{Null: `\N`, Comma: ';'})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, "id;name;note;created_at\n"+
+		"1;Barack;\\N;2009-01-03T18:15:05Z\n"+
+		"2;Obama, Jr.;\"line1\nline \"\"2\"\"\";2009-01-03T18:15:05Z\n", buf.String())
+
+	dbmock.ExpectQuery("SELECT id FROM dbr_people").WillReturnRows(
+		sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2).RowError(1, errors.New("boom")))
+	n, err = sess.Select("id").From("dbr_people").WriteCSV(context.Background(), &buf)
+	assert.EqualError(t, err, "boom")
+	assert.Equal(t, 0, n)
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+}
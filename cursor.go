@@ -0,0 +1,154 @@
+package dbr
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// kinds of cursor values, they keep types of values for decoding
+const (
+	cursorNull   = "n"
+	cursorInt    = "i"
+	cursorUint   = "u"
+	cursorFloat  = "f"
+	cursorString = "s"
+	cursorBool   = "b"
+	cursorTime   = "t"
+	cursorBytes  = "x"
+)
+
+// EncodeCursor encodes key values of the last row of a page into an opaque cursor, which is safe for URLs.
+// Values may be nil, integers, floats, strings, bools, time.Time, []byte, pointers to them or driver.Valuer,
+// it returns an error on other types and on errors of driver.Valuer
+func EncodeCursor(value ...interface{}) (string, error) {
+	pairs := make([][2]string, len(value))
+	for i, v := range value {
+		kind, s, err := encodeCursorValue(v)
+		if err != nil {
+			return "", err
+		}
+		pairs[i] = [2]string{kind, s}
+	}
+	b, err := json.Marshal(pairs)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func encodeCursorValue(value interface{}) (string, string, error) {
+	switch v := value.(type) {
+	case nil:
+		return cursorNull, "", nil
+	case time.Time:
+		return cursorTime, v.Format(time.RFC3339Nano), nil
+	case []byte:
+		return cursorBytes, base64.StdEncoding.EncodeToString(v), nil
+	case driver.Valuer:
+		if vv := reflect.ValueOf(v); vv.Kind() == reflect.Ptr && vv.IsNil() {
+			return cursorNull, "", nil
+		}
+		dv, err := v.Value()
+		if err != nil {
+			return "", "", err
+		}
+		return encodeCursorValue(dv)
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return cursorNull, "", nil
+		}
+		return encodeCursorValue(v.Elem().Interface())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cursorInt, strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return cursorUint, strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return cursorFloat, strconv.FormatFloat(v.Float(), 'g', -1, 64), nil
+	case reflect.String:
+		return cursorString, v.String(), nil
+	case reflect.Bool:
+		return cursorBool, strconv.FormatBool(v.Bool()), nil
+	}
+	return "", "", fmt.Errorf("dbr: unsupported cursor value type %T", value)
+}
+
+// DecodeCursor decodes values encoded by EncodeCursor, integers are decoded as int64 or uint64
+// and floats as float64. It returns ErrInvalidCursor if the cursor is malformed
+func DecodeCursor(cursor string) ([]interface{}, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var pairs [][2]string
+	if err := json.Unmarshal(b, &pairs); err != nil || len(pairs) == 0 {
+		return nil, ErrInvalidCursor
+	}
+	value := make([]interface{}, len(pairs))
+	for i, pair := range pairs {
+		v, err := decodeCursorValue(pair[0], pair[1])
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		value[i] = v
+	}
+	return value, nil
+}
+
+func decodeCursorValue(kind, s string) (interface{}, error) {
+	switch kind {
+	case cursorNull:
+		return nil, nil
+	case cursorInt:
+		return strconv.ParseInt(s, 10, 64)
+	case cursorUint:
+		return strconv.ParseUint(s, 10, 64)
+	case cursorFloat:
+		return strconv.ParseFloat(s, 64)
+	case cursorString:
+		return s, nil
+	case cursorBool:
+		return strconv.ParseBool(s)
+	case cursorTime:
+		return time.Parse(time.RFC3339Nano, s)
+	case cursorBytes:
+		return base64.StdEncoding.DecodeString(s)
+	}
+	return nil, ErrInvalidCursor
+}
+
+// afterCursor builds `(a, b) > (?, ?)` condition of keyset pagination with values of cursor
+func afterCursor(column []string, cursor string) Builder {
+	return BuildFunc(func(d Dialect, buf Buffer) error {
+		if len(column) == 0 {
+			return ErrColumnNotSpecified
+		}
+		value, err := DecodeCursor(cursor)
+		if err != nil {
+			return err
+		}
+		if len(value) != len(column) {
+			return ErrInvalidCursor
+		}
+		buf.WriteString("(")
+		buf.WriteString(quoteIdentList(d, column))
+		buf.WriteString(") > (")
+		for i := range value {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			buf.WriteString(placeholder)
+		}
+		buf.WriteString(")")
+		buf.WriteValue(value...)
+		return nil
+	})
+}
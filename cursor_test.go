@@ -0,0 +1,71 @@
+package dbr
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mailru/dbr/dialect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 6000, time.UTC)
+	name := "Barack"
+	cursor, err := EncodeCursor(int32(-7), uint64(1<<63), 0.5, "a,b\"'", true, ts, []byte{0, 1}, nil, &name, NewNullInt64(nil), NewNullString("x"))
+	assert.NoError(t, err)
+	value, err := DecodeCursor(cursor)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{int64(-7), uint64(1 << 63), 0.5, "a,b\"'", true, ts, []byte{0, 1}, nil, "Barack", nil, "x"}, value)
+}
+
+func TestDecodeInvalidCursor(t *testing.T) {
+	for _, cursor := range []string{
+		"",
+		"not base64!",
+		base64.RawURLEncoding.EncodeToString([]byte(`{"a":1}`)),
+		base64.RawURLEncoding.EncodeToString([]byte(`[]`)),
+		base64.RawURLEncoding.EncodeToString([]byte(`[["i","x"]]`)),
+		base64.RawURLEncoding.EncodeToString([]byte(`[["z","1"]]`)),
+		base64.RawURLEncoding.EncodeToString([]byte(`[["t","yesterday"]]`)),
+	} {
+		_, err := DecodeCursor(cursor)
+		assert.Equal(t, ErrInvalidCursor, err, cursor)
+	}
+}
+
+type failingValuer struct{}
+
+func (failingValuer) Value() (driver.Value, error) {
+	return nil, errors.New("value failed")
+}
+
+func TestEncodeCursorError(t *testing.T) {
+	_, err := EncodeCursor(1, struct{}{})
+	assert.EqualError(t, err, "dbr: unsupported cursor value type struct {}")
+	_, err = EncodeCursor(failingValuer{})
+	assert.EqualError(t, err, "value failed")
+}
+
+func TestSelectAfterCursor(t *testing.T) {
+	cursor, err := EncodeCursor(time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), 42)
+	assert.NoError(t, err)
+	stmt := Select("*").From("events").
+		Where(Eq("user_id", 1)).
+		AfterCursor([]string{"created_at", "id"}, cursor).
+		OrderAsc("created_at").OrderAsc("id").
+		Limit(10)
+	buf := NewBuffer()
+	err = stmt.Build(dialect.PostgreSQL, buf)
+	assert.NoError(t, err)
+	query, err := InterpolateForDialect(buf.String(), buf.Value(), dialect.PostgreSQL)
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM events WHERE ("user_id" = 1) AND (("created_at","id") > ('2020-01-02 00:00:00.000000',42)) ORDER BY created_at ASC, id ASC LIMIT 10`, query)
+
+	err = Select("*").From("events").AfterCursor([]string{"id"}, cursor).Build(dialect.PostgreSQL, NewBuffer())
+	assert.Equal(t, ErrInvalidCursor, err)
+	err = Select("*").From("events").AfterCursor([]string{"id"}, "garbage").Build(dialect.PostgreSQL, NewBuffer())
+	assert.Equal(t, ErrInvalidCursor, err)
+}
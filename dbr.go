@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/mailru/dbr/dialect"
@@ -51,7 +52,28 @@ type Connection struct {
 type Session struct {
 	*Connection
 	EventReceiver
-	ctx context.Context
+	// Metrics is optional, it collects metrics of queries made in the session
+	Metrics Metrics
+	// Retry is optional, it retries queries failed because of a broken connection
+	Retry RetryPolicy
+	// UsePlaceholders disables interpolation, values are sent to the driver
+	// with placeholders of the dialect, e.g. $1 for PostgreSQL
+	UsePlaceholders bool
+	// ParallelSelectLimit is the maximum number of queries run at once by ParallelSelect, 4 by default
+	ParallelSelectLimit int
+	// Replica is optional, selects run on it if their context is made by WithReplica
+	Replica *Connection
+	// KeywordCase changes case of SQL keywords in queries of the session and its transactions
+	KeywordCase KeywordCase
+	// NullsOrdering puts NULLs first or last in orderings of selects of the session and its transactions,
+	// it is emulated in MySQL and SQLite3, which have no NULLS FIRST and NULLS LAST
+	NullsOrdering NullsOrdering
+	// StandardLimitSyntax writes limits of selects of the session and its transactions as
+	// `OFFSET n ROWS FETCH NEXT m ROWS ONLY` in dialects supporting it, others use LIMIT
+	StandardLimitSyntax bool
+	ctx                 context.Context
+	readOnly            bool
+	tag                 string
 }
 
 // NewSession instantiates a Session for the Connection
@@ -67,12 +89,110 @@ func (conn *Connection) NewSessionContext(ctx context.Context, log EventReceiver
 	return &Session{Connection: conn, EventReceiver: log, ctx: ctx}
 }
 
+// NewReadOnlySession instantiates a Session which can only read, e.g. for a read replica.
+// Insert, update and delete builders and transactions of the session fail with ErrReadOnlySession
+func (conn *Connection) NewReadOnlySession(log EventReceiver) *Session {
+	sess := conn.NewSession(log)
+	sess.readOnly = true
+	return sess
+}
+
 // NewSession forks current session
 func (sess *Session) NewSession(log EventReceiver) *Session {
 	if log == nil {
 		log = sess.EventReceiver
 	}
-	return &Session{Connection: sess.Connection, EventReceiver: log, ctx: sess.ctx}
+	return &Session{
+		Connection:          sess.Connection,
+		EventReceiver:       log,
+		Metrics:             sess.Metrics,
+		Retry:               sess.Retry,
+		UsePlaceholders:     sess.UsePlaceholders,
+		ParallelSelectLimit: sess.ParallelSelectLimit,
+		Replica:             sess.Replica,
+		KeywordCase:         sess.KeywordCase,
+		NullsOrdering:       sess.NullsOrdering,
+		StandardLimitSyntax: sess.StandardLimitSyntax,
+		ctx:                 sess.ctx,
+		readOnly:            sess.readOnly,
+		tag:                 sess.tag,
+	}
+}
+
+// ReadOnly reports whether the session is read-only
+func (sess *Session) ReadOnly() bool {
+	return sess.readOnly
+}
+
+// SetTag sets a tag prepended to all queries of the session and its transactions
+// as a comment, e.g. "app:billing" gives `/* app:billing */ SELECT ...`.
+// It is useful to group queries in pg_stat_statements, "*" is removed from the tag
+func (sess *Session) SetTag(tag string) {
+	sess.tag = strings.Replace(tag, "*", "", -1)
+}
+
+// Tag returns the tag of the session
+func (sess *Session) Tag() string {
+	return sess.tag
+}
+
+// tagQuery prepends tag of session or transaction to query
+func tagQuery(runner runner, query string) string {
+	var tag string
+	switch r := runner.(type) {
+	case *Session:
+		tag = r.tag
+	case *Tx:
+		tag = r.tag
+	}
+	if tag == "" {
+		return query
+	}
+	// plan hints must stay at the head of the query
+	if strings.HasPrefix(query, "/*+") {
+		if end := strings.Index(query, "*/"); end != -1 {
+			return query[:end+2] + " /* " + tag + " */" + query[end+2:]
+		}
+	}
+	return "/* " + tag + " */ " + query
+}
+
+// settingsQuery appends settings of transaction to query, e.g. the statement timeout of ClickHouse
+func settingsQuery(runner runner, query string) string {
+	if tx, ok := runner.(*Tx); ok && tx.timeoutSettings != "" {
+		return query + " " + tx.timeoutSettings
+	}
+	return query
+}
+
+// checkWritable returns ErrReadOnlySession if runner is a read-only session
+func checkWritable(runner runner, log EventReceiver) error {
+	if sess, ok := runner.(*Session); ok && sess.readOnly {
+		return log.EventErr("dbr.exec.read_only", ErrReadOnlySession)
+	}
+	return nil
+}
+
+// usePlaceholders reports whether values are sent to the driver instead of interpolation
+func usePlaceholders(runner runner) bool {
+	switch r := runner.(type) {
+	case *Session:
+		return r.UsePlaceholders
+	case *Tx:
+		return r.UsePlaceholders
+	}
+	return false
+}
+
+// standardLimitSyntax reports whether session or transaction writes limits in standard syntax
+func standardLimitSyntax(runner runner) bool {
+	switch r := runner.(type) {
+	case *Session:
+		return r.StandardLimitSyntax
+	case *Tx:
+		return r.StandardLimitSyntax
+	}
+	return false
 }
 
 // beginTx starts a transaction with context.
@@ -82,7 +202,7 @@ func (conn *Connection) beginTx() (*sql.Tx, error) {
 
 // SessionRunner can do anything that a Session can except start a transaction.
 type SessionRunner interface {
-	Select(column ...string) SelectBuilder
+	Select(column ...interface{}) SelectBuilder
 	SelectBySql(query string, value ...interface{}) SelectBuilder
 
 	InsertInto(table string) InsertBuilder
@@ -122,26 +242,38 @@ type loader interface {
 	LoadValuesContext(ctx context.Context, value interface{}) (int, error)
 }
 
-func exec(ctx context.Context, runner runner, log EventReceiver, builder Builder, d Dialect) (sql.Result, error) {
+func exec(ctx context.Context, runner runner, log EventReceiver, builder Builder, d Dialect, eventKvs kvs) (sql.Result, error) {
+	if err := checkWritable(runner, log); err != nil {
+		return nil, err
+	}
+
+	metrics := newQueryMetrics(runner, "exec", d)
+
 	i := interpolator{
-		Buffer:       NewBuffer(),
-		Dialect:      d,
-		IgnoreBinary: true,
+		Buffer:          NewBuffer(),
+		Dialect:         d,
+		IgnoreBinary:    true,
+		UsePlaceholders: usePlaceholders(runner),
+		KeywordCase:     keywordCase(runner),
 	}
 	err := i.interpolate(placeholder, []interface{}{builder})
-	query, value := i.String(), i.Value()
+	query, value := tagQuery(runner, i.String()), i.Value()
 	if err != nil {
-		return nil, log.EventErrKv("dbr.exec.interpolate", err, kvs{
+		metrics.incError()
+		return nil, eventErr(log, "dbr.exec.interpolate", err, builder, d, kvs{
 			"sql":  query,
 			"args": fmt.Sprint(value),
-		})
+		}.merge(eventKvs))
 	}
+	auditQuery(runner, builder, d)
 
 	startTime := time.Now()
 	defer func() {
-		log.TimingKv("dbr.exec", time.Since(startTime).Nanoseconds(), kvs{
+		elapsed := time.Since(startTime)
+		metrics.observeDuration(elapsed)
+		log.TimingKv("dbr.exec", elapsed.Nanoseconds(), kvs{
 			"sql": query,
-		})
+		}.merge(eventKvs))
 	}()
 
 	traceImpl, hasTracingImpl := log.(TracingEventReceiver)
@@ -150,39 +282,59 @@ func exec(ctx context.Context, runner runner, log EventReceiver, builder Builder
 		defer traceImpl.SpanFinish(ctx)
 	}
 
-	result, err := runner.Exec(query, value...)
+	var result sql.Result
+	err = withRetry(ctx, runner, log, "dbr.exec.retry", query, true, func() (err error) {
+		result, err = runner.Exec(query, value...)
+		return err
+	})
 	if err != nil {
 		if hasTracingImpl {
 			traceImpl.SpanError(ctx, err)
 		}
+		metrics.incError()
 
-		return result, log.EventErrKv("dbr.exec.exec", err, kvs{
+		return result, eventErr(log, "dbr.exec.exec", wrapDriverError(err), builder, d, kvs{
 			"sql": query,
-		})
+		}.merge(eventKvs))
 	}
 	return result, nil
 }
 
-func query(ctx context.Context, runner runner, log EventReceiver, builder Builder, d Dialect, dest interface{}) (int, error) {
+func query(ctx context.Context, runner runner, log EventReceiver, builder Builder, d Dialect, eventKvs kvs, dest interface{}) (int, error) {
+	return queryRows(ctx, runner, log, builder, d, eventKvs, func(rows *sql.Rows) (int, error) {
+		return load(rows, dest, false, false)
+	})
+}
+
+// queryRows runs query and passes its rows to scan, rows are closed after it
+func queryRows(ctx context.Context, runner runner, log EventReceiver, builder Builder, d Dialect, eventKvs kvs, scan func(rows *sql.Rows) (int, error)) (int, error) {
+	metrics := newQueryMetrics(runner, "select", d)
+
 	i := interpolator{
-		Buffer:       NewBuffer(),
-		Dialect:      d,
-		IgnoreBinary: true,
+		Buffer:          NewBuffer(),
+		Dialect:         d,
+		IgnoreBinary:    true,
+		UsePlaceholders: usePlaceholders(runner),
+		KeywordCase:     keywordCase(runner),
 	}
 	err := i.interpolate(placeholder, []interface{}{builder})
-	query, value := i.String(), i.Value()
+	query, value := tagQuery(runner, settingsQuery(runner, i.String())), i.Value()
 	if err != nil {
-		return 0, log.EventErrKv("dbr.select.interpolate", err, kvs{
+		metrics.incError()
+		return 0, eventErr(log, "dbr.select.interpolate", err, builder, d, kvs{
 			"sql":  query,
 			"args": fmt.Sprint(value),
-		})
+		}.merge(eventKvs))
 	}
+	auditQuery(runner, builder, d)
 
 	startTime := time.Now()
 	defer func() {
-		log.TimingKv("dbr.select", time.Since(startTime).Nanoseconds(), kvs{
+		elapsed := time.Since(startTime)
+		metrics.observeDuration(elapsed)
+		log.TimingKv("dbr.select", elapsed.Nanoseconds(), kvs{
 			"sql": query,
-		})
+		}.merge(eventKvs))
 	}()
 
 	traceImpl, hasTracingImpl := log.(TracingEventReceiver)
@@ -191,21 +343,39 @@ func query(ctx context.Context, runner runner, log EventReceiver, builder Builde
 		defer traceImpl.SpanFinish(ctx)
 	}
 
-	rows, err := runner.QueryContext(ctx, query, value...)
+	// a scan error cancels the query, so the driver stops fetching rows which would be skipped by Close
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// insert with RETURNING is loaded via query as well
+	_, isSelect := builder.(*selectBuilder)
+	target := runner
+	if isSelect {
+		target = readRunner(ctx, runner)
+	}
+	var rows *sql.Rows
+	err = withRetry(ctx, runner, log, "dbr.select.retry", query, !isSelect, func() (err error) {
+		rows, err = target.QueryContext(ctx, query, value...)
+		return err
+	})
 	if err != nil {
 		if hasTracingImpl {
 			traceImpl.SpanError(ctx, err)
 		}
+		metrics.incError()
 
-		return 0, log.EventErrKv("dbr.select.load.query", err, kvs{
+		return 0, eventErr(log, "dbr.select.load.query", wrapDriverError(err), builder, d, kvs{
 			"sql": query,
-		})
+		}.merge(eventKvs))
 	}
-	count, err := Load(rows, dest)
+	defer rows.Close()
+	count, err := scan(rows)
 	if err != nil {
-		return 0, log.EventErrKv("dbr.select.load.scan", err, kvs{
+		cancel()
+		metrics.incError()
+		return 0, eventErr(log, "dbr.select.load.scan", err, builder, d, kvs{
 			"sql": query,
-		})
+		}.merge(eventKvs))
 	}
 	return count, nil
 }
@@ -3,6 +3,7 @@
 package dbr
 
 import (
+	"context"
 	"database/sql"
 )
 
@@ -31,6 +32,6 @@ func (tx *Tx) Query(query string, args ...interface{}) (*sql.Rows, error) {
 }
 
 // beginTx starts a transaction with context.
-func (sess *Session) beginTx(opts *sql.TxOptions) (*sql.Tx, error) {
-	return sess.BeginTx(sess.ctx, opts)
+func (sess *Session) beginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return sess.BeginTx(ctx, opts)
 }
@@ -2,10 +2,15 @@ package dbr
 
 import (
 	"bytes"
+	"context"
+	"fmt"
 	"log"
 	"os"
+	"regexp"
 	"testing"
+	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/mailru/dbr/dialect"
 	"github.com/stretchr/testify/assert"
 
@@ -106,6 +111,10 @@ func reset(sess *Session) {
 			)`,
 			`DROP TABLE IF EXISTS dbr_keys`,
 			`CREATE TABLE dbr_keys (key_value varchar(255) PRIMARY KEY, val_value varchar(255))`,
+			`DROP TABLE IF EXISTS dbr_bools`,
+			`CREATE TABLE dbr_bools (id integer PRIMARY KEY, val bool NOT NULL)`,
+			`DROP TABLE IF EXISTS dbr_decimals`,
+			`CREATE TABLE dbr_decimals (id integer PRIMARY KEY, val decimal(20,4) NULL)`,
 		}
 	case dialect.PostgreSQL:
 		stmts = []string{
@@ -122,6 +131,10 @@ func reset(sess *Session) {
 			)`,
 			`DROP TABLE IF EXISTS dbr_keys`,
 			`CREATE TABLE dbr_keys (key_value varchar(255) PRIMARY KEY, val_value varchar(255))`,
+			`DROP TABLE IF EXISTS dbr_bools`,
+			`CREATE TABLE dbr_bools (id integer PRIMARY KEY, val bool NOT NULL)`,
+			`DROP TABLE IF EXISTS dbr_decimals`,
+			`CREATE TABLE dbr_decimals (id integer PRIMARY KEY, val decimal(20,4) NULL)`,
 		}
 	case dialect.SQLite3:
 		stmts = []string{
@@ -138,6 +151,10 @@ func reset(sess *Session) {
 			)`,
 			`DROP TABLE IF EXISTS dbr_keys`,
 			`CREATE TABLE dbr_keys (key_value varchar(255) PRIMARY KEY, val_value varchar(255))`,
+			`DROP TABLE IF EXISTS dbr_bools`,
+			`CREATE TABLE dbr_bools (id INTEGER PRIMARY KEY, val INTEGER NOT NULL)`,
+			`DROP TABLE IF EXISTS dbr_decimals`,
+			`CREATE TABLE dbr_decimals (id INTEGER PRIMARY KEY, val NUMERIC NULL)`,
 		}
 	case dialect.ClickHouse:
 		stmts = []string{
@@ -145,6 +162,10 @@ func reset(sess *Session) {
 			"CREATE TABLE dbr_people(id Int32, name String, email String) Engine=Memory",
 			`DROP TABLE IF EXISTS dbr_keys`,
 			`CREATE TABLE dbr_keys (key_value String, val_value String) Engine=Memory`,
+			`DROP TABLE IF EXISTS dbr_bools`,
+			`CREATE TABLE dbr_bools (id Int32, val UInt8) Engine=Memory`,
+			`DROP TABLE IF EXISTS dbr_decimals`,
+			`CREATE TABLE dbr_decimals (id Int32, val Nullable(Decimal(20,4))) Engine=Memory`,
 		}
 	}
 	for _, v := range stmts {
@@ -272,6 +293,422 @@ func TestOnConflict(t *testing.T) {
 	}
 }
 
+func TestUpsertInserted(t *testing.T) {
+	for _, sess := range []*Session{mysqlSession, postgresSession} {
+		key := fmt.Sprintf("upsert%d", nextID())
+		for _, expected := range []bool{true, false} {
+			var inserted bool
+			_, err := sess.InsertInto("dbr_keys").Columns("key_value", "val_value").Values(key, "value").
+				OnConflictMap("dbr_keys_pkey", map[string]interface{}{"val_value": Expr("CONCAT(?, 2)", Proposed("val_value"))}).
+				ReturningInserted(&inserted).
+				Exec()
+			assert.NoError(t, err)
+			assert.Equal(t, expected, inserted)
+		}
+	}
+}
+
+func TestInsertReturning(t *testing.T) {
+	jonathan := person{
+		Name:  "jonathan",
+		Email: "jonathan@uservoice.com",
+	}
+	err := postgresSession.InsertInto("dbr_people").
+		Columns("name", "email").
+		Record(&jonathan).
+		Returning("id").
+		LoadStruct(&jonathan)
+	assert.NoError(t, err)
+	assert.True(t, jonathan.ID > 0)
+	assert.Equal(t, "jonathan", jonathan.Name)
+}
+
+func TestBoolRoundTrip(t *testing.T) {
+	for _, sess := range testSession {
+		for _, val := range []bool{true, false} {
+			id := nextID()
+			_, err := sess.InsertInto("dbr_bools").Columns("id", "val").Values(id, val).Exec()
+			assert.NoError(t, err)
+
+			var got bool
+			err = sess.Select("val").From("dbr_bools").Where(Eq("id", id)).LoadValue(&got)
+			assert.NoError(t, err)
+			assert.Equal(t, val, got)
+
+			var count int64
+			err = sess.Select("count(*)").From("dbr_bools").Where(And(Eq("id", id), Eq("val", val))).LoadValue(&count)
+			assert.NoError(t, err)
+			assert.EqualValues(t, 1, count)
+		}
+	}
+}
+
+func TestDecimalRoundTrip(t *testing.T) {
+	val, err := NewDecimal("12345.6789")
+	assert.NoError(t, err)
+	for _, sess := range testSession {
+		for _, in := range []Decimal{val, {}} {
+			id := nextID()
+			_, err := sess.InsertInto("dbr_decimals").Columns("id", "val").Values(id, in).Exec()
+			assert.NoError(t, err)
+
+			var got Decimal
+			err = sess.Select("val").From("dbr_decimals").Where(Eq("id", id)).LoadValue(&got)
+			assert.NoError(t, err)
+			assert.Equal(t, in.String(), got.String())
+		}
+	}
+}
+
+func TestDecimalLoadString(t *testing.T) {
+	type row struct {
+		Val  string
+		Null *string `db:"null_val"`
+	}
+	// the drivers return the text of numerics as []byte, so it is loaded exactly,
+	// it would be rounded by float64
+	for _, sess := range []*Session{mysqlSession, postgresSession} {
+		for _, in := range []string{"12345678901234.5678", "1.5000", "-0.0001"} {
+			id := nextID()
+			_, err := sess.InsertInto("dbr_decimals").Columns("id", "val").Values(id, Expr(in)).Exec()
+			assert.NoError(t, err)
+
+			var got string
+			err = sess.Select("val").From("dbr_decimals").Where(Eq("id", id)).LoadValue(&got)
+			assert.NoError(t, err)
+			assert.Equal(t, in, got)
+
+			var r row
+			err = sess.Select("val", "CAST(NULL AS DECIMAL(20,4)) AS null_val").From("dbr_decimals").Where(Eq("id", id)).LoadStruct(&r)
+			assert.NoError(t, err)
+			assert.Equal(t, row{Val: in}, r)
+		}
+	}
+}
+
+func TestInsertSet(t *testing.T) {
+	for _, sess := range testSession {
+		id := nextID()
+		_, err := sess.InsertInto("dbr_people").Set("id", id).Set("name", "Barack").Set("email", "obama@whitehouse.gov").Exec()
+		assert.NoError(t, err)
+
+		var p person
+		err = sess.Select("*").From("dbr_people").Where(Eq("id", id)).LoadStruct(&p)
+		assert.NoError(t, err)
+		assert.Equal(t, "Barack", p.Name)
+		assert.Equal(t, "obama@whitehouse.gov", p.Email)
+	}
+}
+
+func TestCreateTable(t *testing.T) {
+	for _, sess := range testSession {
+		_, err := sess.DropTableIfExists("dbr_fixtures").Exec()
+		assert.NoError(t, err)
+		for i := 0; i < 2; i++ {
+			_, err = sess.CreateTable("dbr_fixtures").
+				Column("id", "BIGINT").
+				Column("name", "TEXT").
+				Column("created_at", "TIMESTAMP").
+				PrimaryKey("id").
+				IfNotExists().
+				Exec()
+			assert.NoError(t, err)
+		}
+
+		_, err = sess.InsertInto("dbr_fixtures").Pair("id", 1).Pair("name", "Barack").Pair("created_at", time.Now()).Exec()
+		assert.NoError(t, err)
+		var name string
+		err = sess.Select("name").From("dbr_fixtures").Where(Eq("id", 1)).LoadValue(&name)
+		assert.NoError(t, err)
+		assert.Equal(t, "Barack", name)
+
+		_, err = sess.DropTable("dbr_fixtures").Exec()
+		assert.NoError(t, err)
+		_, err = sess.DropTable("dbr_fixtures").Exec()
+		assert.Error(t, err)
+		_, err = sess.DropTableIfExists("dbr_fixtures").Exec()
+		assert.NoError(t, err)
+	}
+}
+
+func TestSessionTag(t *testing.T) {
+	sess, dbmock, recv := newRecordingSessionMock()
+	sess.SetTag("app:billing */ DROP TABLE t; /*")
+	assert.Equal(t, "app:billing / DROP TABLE t; /", sess.Tag())
+	sess.SetTag("app:billing")
+
+	dbmock.ExpectQuery(regexp.QuoteMeta("/* app:billing */ WITH t AS (SELECT 1 AS a) SELECT a FROM t")).
+		WillReturnRows(sqlmock.NewRows([]string{"a"}).AddRow(1))
+	dbmock.ExpectBegin()
+	dbmock.ExpectExec(regexp.QuoteMeta("/* app:billing */ DELETE FROM `t` WHERE (`a` = 1)")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	dbmock.ExpectCommit()
+
+	var a int
+	err := sess.SelectBySql("WITH t AS (SELECT 1 AS a) SELECT a FROM t").LoadValue(&a)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, a)
+
+	tx, err := sess.NewSession(nil).Begin()
+	assert.NoError(t, err)
+	_, err = tx.DeleteFrom("t").Where(Eq("a", a)).Exec()
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+
+	assert.Equal(t, "/* app:billing */ WITH t AS (SELECT 1 AS a) SELECT a FROM t", recv.events[0].kvs["sql"])
+
+	// plan hints are kept at the head of the query
+	assert.Equal(t, "/*+ SeqScan(t) */ /* app:billing */ SELECT a FROM t", tagQuery(sess, "/*+ SeqScan(t) */ SELECT a FROM t"))
+}
+
+func TestStringAggregation(t *testing.T) {
+	for _, sess := range testSession {
+		email := fmt.Sprintf("agg%d@example.com", nextID())
+		for _, name := range []string{"Barack", "Michelle", "Barack"} {
+			_, err := sess.InsertInto("dbr_people").Pair("id", nextID()).Pair("name", name).Pair("email", email).Exec()
+			assert.NoError(t, err)
+		}
+
+		var names string
+		err := sess.SelectBySql("SELECT ? FROM dbr_people WHERE email = ?", StringAgg("name", "; "), email).LoadValue(&names)
+		assert.NoError(t, err)
+		assert.Len(t, names, len("Barack; Michelle; Barack"))
+		if sess.Dialect != dialect.SQLite3 {
+			err = sess.SelectBySql("SELECT ? FROM dbr_people WHERE email = ?", StringAgg(Distinct("name"), "; "), email).LoadValue(&names)
+			assert.NoError(t, err)
+			assert.Len(t, names, len("Barack; Michelle"))
+		}
+	}
+}
+
+func TestCursorPagination(t *testing.T) {
+	for _, sess := range testSession {
+		email := fmt.Sprintf("page%d@example.com", nextID())
+		for _, name := range []string{"c", "a", "b", "a"} {
+			_, err := sess.InsertInto("dbr_people").Pair("id", nextID()).Pair("name", name).Pair("email", email).Exec()
+			assert.NoError(t, err)
+		}
+
+		var names []string
+		cursor := ""
+		for page := 0; page < 3; page++ {
+			stmt := sess.Select("id", "name").From("dbr_people").Where(Eq("email", email)).
+				OrderAsc("name").OrderAsc("id").
+				Limit(3)
+			if cursor != "" {
+				stmt.AfterCursor([]string{"name", "id"}, cursor)
+			}
+			var people []person
+			_, err := stmt.LoadStructs(&people)
+			assert.NoError(t, err)
+			if len(people) == 0 {
+				break
+			}
+			for _, p := range people {
+				names = append(names, p.Name)
+			}
+			last := people[len(people)-1]
+			cursor, err = EncodeCursor(last.Name, last.ID)
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, []string{"a", "a", "b", "c"}, names)
+	}
+}
+
+func TestValuesTableJoin(t *testing.T) {
+	for _, sess := range testSession {
+		if sess.Dialect == dialect.ClickHouse {
+			continue
+		}
+		id1, id2 := nextID(), nextID()
+		for _, id := range []int64{id1, id2} {
+			_, err := sess.InsertInto("dbr_people").Pair("id", id).Pair("name", "values").Exec()
+			assert.NoError(t, err)
+		}
+
+		var emails []string
+		_, err := sess.Select("t.email").From(As("dbr_people", "p")).
+			Join(ValuesTable([][]interface{}{{id2, "second@example.com"}, {id1, "first@example.com"}}, "t", "id", "email"), "p.id = t.id").
+			OrderAsc("p.id").
+			Load(&emails)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"first@example.com", "second@example.com"}, emails)
+	}
+}
+
+func TestOrderByAlias(t *testing.T) {
+	for _, sess := range testSession {
+		email := fmt.Sprintf("alias%d@example.com", nextID())
+		for _, name := range []string{"Barack", "Michelle", "Michelle"} {
+			_, err := sess.InsertInto("dbr_people").Pair("id", nextID()).Pair("name", name).Pair("email", email).Exec()
+			assert.NoError(t, err)
+		}
+
+		var rows []struct {
+			Name  string
+			Total int `db:"Total"`
+		}
+		// the alias is quoted as in As, so it keeps its case in PostgreSQL
+		_, err := sess.Select("name", "count(*) AS "+sess.Dialect.QuoteIdent("Total")).From("dbr_people").
+			Where(Eq("email", email)).
+			GroupBy("name").
+			OrderByAlias("Total", false).
+			Load(&rows)
+		assert.NoError(t, err)
+		if assert.Len(t, rows, 2) {
+			assert.Equal(t, "Michelle", rows[0].Name)
+			assert.Equal(t, 2, rows[0].Total)
+		}
+	}
+}
+
+func TestNullsOrdering(t *testing.T) {
+	for _, sess := range testSession {
+		if sess.Dialect == dialect.ClickHouse {
+			// email is not nullable in clickhouse
+			continue
+		}
+		name := fmt.Sprintf("nulls%d", nextID())
+		ids := []int64{nextID(), nextID()}
+		_, err := sess.InsertInto("dbr_people").Pair("id", ids[0]).Pair("name", name).Pair("email", "a@example.com").Exec()
+		assert.NoError(t, err)
+		_, err = sess.InsertInto("dbr_people").Pair("id", ids[1]).Pair("name", name).Pair("email", nil).Exec()
+		assert.NoError(t, err)
+
+		for _, test := range []struct {
+			nulls NullsOrdering
+			ids   []int64
+		}{
+			{nulls: NullsFirst, ids: []int64{ids[1], ids[0]}},
+			{nulls: NullsLast, ids: ids},
+		} {
+			sess := sess.NewSession(nil)
+			sess.NullsOrdering = test.nulls
+			var loaded []int64
+			_, err := sess.Select("id").From("dbr_people").Where(Eq("name", name)).OrderAsc("email").Load(&loaded)
+			assert.NoError(t, err)
+			assert.Equal(t, test.ids, loaded)
+		}
+	}
+}
+
+func TestStandardLimitSyntax(t *testing.T) {
+	sess := postgresSession.NewSession(nil)
+	sess.StandardLimitSyntax = true
+	name := fmt.Sprintf("fetch%d", nextID())
+	ids := []int64{nextID(), nextID(), nextID()}
+	for _, id := range ids {
+		_, err := sess.InsertInto("dbr_people").Pair("id", id).Pair("name", name).Exec()
+		assert.NoError(t, err)
+	}
+
+	var loaded []int64
+	_, err := sess.Select("id").From("dbr_people").Where(Eq("name", name)).OrderAsc("id").Limit(2).Offset(1).Load(&loaded)
+	assert.NoError(t, err)
+	assert.Equal(t, ids[1:], loaded)
+}
+
+func TestJSONOperators(t *testing.T) {
+	for _, usePlaceholders := range []bool{false, true} {
+		sess := postgresSession.NewSession(nil)
+		sess.UsePlaceholders = usePlaceholders
+		var count int
+		err := sess.Select("count(*)").
+			From(Expr(`(SELECT '{"a": 1, "b": 2}'::jsonb AS data) AS t`)).
+			Where("data ?? ?", "a").
+			Where("data ??| array[?, ?]", "b", "c").
+			Where("data ??& array[?, ?]", "a", "b").
+			LoadValue(&count)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+	}
+}
+
+func TestInsertDoNothing(t *testing.T) {
+	for _, sess := range []*Session{mysqlSession, postgresSession, sqlite3Session} {
+		id := nextID()
+		_, err := sess.InsertInto("dbr_people").Pair("id", id).Pair("name", "first").Exec()
+		assert.NoError(t, err)
+
+		n, err := sess.InsertInto("dbr_people").Columns("id", "name").
+			Values(id, "second").
+			Values(nextID(), "third").
+			OnConflictDoNothing().
+			ExecInsertedCount(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+
+		name, err := sess.Select("name").From("dbr_people").Where(Eq("id", id)).ReturnString()
+		assert.NoError(t, err)
+		assert.Equal(t, "first", name)
+	}
+}
+
+func TestCountDistinctColumns(t *testing.T) {
+	for _, sess := range []*Session{mysqlSession, postgresSession, sqlite3Session} {
+		name := fmt.Sprintf("distinct%d", nextID())
+		for _, email := range []string{"a@example.com", "a@example.com", "b@example.com"} {
+			_, err := sess.InsertInto("dbr_people").Pair("id", nextID()).Pair("name", name).Pair("email", email).Exec()
+			assert.NoError(t, err)
+		}
+		n, err := sess.Select("*").From("dbr_people").Where(Eq("name", name)).
+			CountDistinct(context.Background(), "name", "email")
+		assert.NoError(t, err, sess.Dialect)
+		assert.Equal(t, int64(2), n)
+	}
+}
+
+func TestKeywordCase(t *testing.T) {
+	for _, sess := range testSession {
+		for _, c := range []KeywordCase{KeywordLower, KeywordUpper} {
+			sess := sess.NewSession(nil)
+			sess.KeywordCase = c
+			id := nextID()
+			_, err := sess.InsertInto("dbr_people").Pair("id", id).Pair("name", "select").Pair("email", "from@example.com").Exec()
+			assert.NoError(t, err)
+
+			var person person
+			err = sess.Select("id", "name", "email").From("dbr_people").
+				Where(And(Eq("id", id), Neq("email", nil))).
+				OrderDesc("id").Limit(1).
+				LoadStruct(&person)
+			assert.NoError(t, err)
+			assert.Equal(t, "select", person.Name)
+			assert.Equal(t, "from@example.com", person.Email)
+		}
+	}
+}
+
+func TestUsePlaceholders(t *testing.T) {
+	db, dbmock, err := sqlmock.New()
+	assert.NoError(t, err)
+	conn := &Connection{DB: db, Dialect: dialect.PostgreSQL, EventReceiver: nullReceiver}
+	sess := conn.NewSession(nil)
+	sess.UsePlaceholders = true
+
+	dbmock.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM dbr_people WHERE ("name" = $1)`)).
+		WithArgs("Barack").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	dbmock.ExpectBegin()
+	dbmock.ExpectExec(regexp.QuoteMeta(`UPDATE "dbr_people" SET "name" = $1 WHERE ("id" = $2)`)).
+		WithArgs("Obama", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	dbmock.ExpectCommit()
+
+	var id int
+	err = sess.Select("id").From("dbr_people").Where(Eq("name", "Barack")).LoadValue(&id)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, id)
+
+	tx, err := sess.Begin()
+	assert.NoError(t, err)
+	_, err = tx.Update("dbr_people").Set("name", "Obama").Where(Eq("id", id)).Exec()
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+}
+
 func TestForkSession(t *testing.T) {
 	sess := testSession[0]
 	sess2 := sess.NewSession(nil)
@@ -281,3 +718,33 @@ func TestForkSession(t *testing.T) {
 	sess3 := sess.NewSession(recv)
 	assert.True(t, sess3.EventReceiver != sess.EventReceiver)
 }
+
+func TestReadOnlySession(t *testing.T) {
+	db, dbmock, err := sqlmock.New()
+	assert.NoError(t, err)
+	conn := &Connection{DB: db, Dialect: dialect.MySQL, EventReceiver: nullReceiver}
+	assert.False(t, conn.NewSession(nil).ReadOnly())
+
+	sess := conn.NewReadOnlySession(nil)
+	assert.True(t, sess.ReadOnly())
+	assert.True(t, sess.NewSession(nil).ReadOnly())
+
+	_, err = sess.InsertInto("table").Pair("a", 1).Exec()
+	assert.Equal(t, ErrReadOnlySession, err)
+	_, err = sess.InsertBySql("INSERT INTO table VALUES (1)").Exec()
+	assert.Equal(t, ErrReadOnlySession, err)
+	_, err = sess.Update("table").Set("a", 1).Exec()
+	assert.Equal(t, ErrReadOnlySession, err)
+	_, err = sess.DeleteFrom("table").Exec()
+	assert.Equal(t, ErrReadOnlySession, err)
+
+	dbmock.ExpectQuery("SELECT a FROM table").WillReturnRows(sqlmock.NewRows([]string{"a"}).AddRow(1))
+	dbmock.ExpectQuery("SELECT 2").WillReturnRows(sqlmock.NewRows([]string{"a"}).AddRow(2))
+	a, err := sess.Select("a").From("table").ReturnInt64()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, a)
+	a, err = sess.SelectBySql("SELECT 2").ReturnInt64()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, a)
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+}
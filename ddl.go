@@ -0,0 +1,245 @@
+package dbr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CreateTableStmt builds `CREATE TABLE ...`, it is minimal and intended for test fixtures,
+// not for migrations
+type CreateTableStmt interface {
+	Builder
+	Column(name, typ string) CreateTableStmt
+	PrimaryKey(column ...string) CreateTableStmt
+	ForeignKey(column, refTable, refColumn string) CreateTableStmt
+	OnDelete(action string) CreateTableStmt
+	OnUpdate(action string) CreateTableStmt
+	IfNotExists() CreateTableStmt
+}
+
+type tableColumn struct {
+	Name string
+	Type string
+}
+
+type foreignKey struct {
+	Column    string
+	RefTable  string
+	RefColumn string
+	OnDelete  string
+	OnUpdate  string
+}
+
+type createTableStmt struct {
+	Table         string
+	TableColumn   []tableColumn
+	PrimaryColumn []string
+	ForeignKeys   []*foreignKey
+	IsIfNotExists bool
+	// foreignKeyErr is the error of OnDelete or OnUpdate without foreign key, it is returned by Build
+	foreignKeyErr error
+}
+
+// foreignKeyActions are referential actions of ON DELETE and ON UPDATE
+var foreignKeyActions = map[string]bool{
+	"CASCADE":     true,
+	"RESTRICT":    true,
+	"SET NULL":    true,
+	"SET DEFAULT": true,
+	"NO ACTION":   true,
+}
+
+// CreateTable creates a CreateTableStmt
+func CreateTable(table string) CreateTableStmt {
+	return createCreateTableStmt(table)
+}
+
+func createCreateTableStmt(table string) *createTableStmt {
+	return &createTableStmt{
+		Table: table,
+	}
+}
+
+// Build builds `CREATE TABLE ...` in dialect
+func (b *createTableStmt) Build(d Dialect, buf Buffer) error {
+	if b.Table == "" {
+		return ErrTableNotSpecified
+	}
+
+	if len(b.TableColumn) == 0 {
+		return ErrColumnNotSpecified
+	}
+
+	if b.foreignKeyErr != nil {
+		return b.foreignKeyErr
+	}
+	for _, fk := range b.ForeignKeys {
+		for _, action := range []string{fk.OnDelete, fk.OnUpdate} {
+			if len(action) > 0 && !foreignKeyActions[action] {
+				return fmt.Errorf("dbr: invalid foreign key action %q", action)
+			}
+		}
+	}
+
+	buf.WriteString("CREATE TABLE ")
+	if b.IsIfNotExists {
+		buf.WriteString("IF NOT EXISTS ")
+	}
+	buf.WriteString(d.QuoteIdent(b.Table))
+	buf.WriteString(" (")
+
+	for i, col := range b.TableColumn {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(d.QuoteIdent(col.Name))
+		buf.WriteString(" ")
+		buf.WriteString(d.ColumnType(col.Type))
+	}
+
+	primaryKey := quoteIdentList(d, b.PrimaryColumn)
+	// engine of the table is ordered by primary key instead
+	engine := d.TableEngine(primaryKey)
+
+	if len(primaryKey) > 0 && len(engine) == 0 {
+		buf.WriteString(", PRIMARY KEY (")
+		buf.WriteString(primaryKey)
+		buf.WriteString(")")
+	}
+
+	if d.SupportsForeignKey() {
+		for _, fk := range b.ForeignKeys {
+			buf.WriteString(", FOREIGN KEY (")
+			buf.WriteString(d.QuoteIdent(fk.Column))
+			buf.WriteString(") REFERENCES ")
+			buf.WriteString(d.QuoteIdent(fk.RefTable))
+			buf.WriteString(" (")
+			buf.WriteString(d.QuoteIdent(fk.RefColumn))
+			buf.WriteString(")")
+			if len(fk.OnDelete) > 0 {
+				buf.WriteString(" ON DELETE ")
+				buf.WriteString(fk.OnDelete)
+			}
+			if len(fk.OnUpdate) > 0 {
+				buf.WriteString(" ON UPDATE ")
+				buf.WriteString(fk.OnUpdate)
+			}
+		}
+	}
+	buf.WriteString(")")
+
+	if len(engine) > 0 {
+		buf.WriteString(" ")
+		buf.WriteString(engine)
+	}
+	return nil
+}
+
+func quoteIdentList(d Dialect, column []string) string {
+	quoted := make([]string, len(column))
+	for i, col := range column {
+		quoted[i] = d.QuoteIdent(col)
+	}
+	return strings.Join(quoted, ",")
+}
+
+// Column adds a column, portable types INT, BIGINT, TEXT, BOOL, DOUBLE, TIMESTAMP and BYTES
+// are mapped to the types of dialect, other types are used as is
+func (b *createTableStmt) Column(name, typ string) CreateTableStmt {
+	b.TableColumn = append(b.TableColumn, tableColumn{Name: name, Type: typ})
+	return b
+}
+
+// PrimaryKey specifies columns of primary key, ClickHouse tables are ordered by them
+func (b *createTableStmt) PrimaryKey(column ...string) CreateTableStmt {
+	b.PrimaryColumn = append(b.PrimaryColumn, column...)
+	return b
+}
+
+// ForeignKey adds a foreign key referencing refColumn of refTable,
+// it is ignored by dialects without foreign keys, e.g. ClickHouse
+func (b *createTableStmt) ForeignKey(column, refTable, refColumn string) CreateTableStmt {
+	b.ForeignKeys = append(b.ForeignKeys, &foreignKey{
+		Column:    column,
+		RefTable:  refTable,
+		RefColumn: refColumn,
+	})
+	return b
+}
+
+// OnDelete sets action of the last foreign key on delete of referenced row:
+// CASCADE, RESTRICT, SET NULL, SET DEFAULT or NO ACTION. Build fails on other actions or without foreign key
+func (b *createTableStmt) OnDelete(action string) CreateTableStmt {
+	if len(b.ForeignKeys) == 0 {
+		if b.foreignKeyErr == nil {
+			b.foreignKeyErr = fmt.Errorf("dbr: OnDelete requires a foreign key")
+		}
+		return b
+	}
+	b.ForeignKeys[len(b.ForeignKeys)-1].OnDelete = action
+	return b
+}
+
+// OnUpdate sets action of the last foreign key on update of referenced row, like OnDelete
+func (b *createTableStmt) OnUpdate(action string) CreateTableStmt {
+	if len(b.ForeignKeys) == 0 {
+		if b.foreignKeyErr == nil {
+			b.foreignKeyErr = fmt.Errorf("dbr: OnUpdate requires a foreign key")
+		}
+		return b
+	}
+	b.ForeignKeys[len(b.ForeignKeys)-1].OnUpdate = action
+	return b
+}
+
+// IfNotExists makes the statement do nothing if the table exists already
+func (b *createTableStmt) IfNotExists() CreateTableStmt {
+	b.IsIfNotExists = true
+	return b
+}
+
+// DropTableStmt builds `DROP TABLE ...`, table may be qualified by database or schema, e.g. "test.t"
+type DropTableStmt interface {
+	Builder
+}
+
+type dropTableStmt struct {
+	Table      string
+	IsIfExists bool
+}
+
+// DropTable creates a DropTableStmt
+func DropTable(table string) DropTableStmt {
+	return createDropTableStmt(table, false)
+}
+
+// DropTableIfExists creates a DropTableStmt which does nothing if the table does not exist
+func DropTableIfExists(table string) DropTableStmt {
+	return createDropTableStmt(table, true)
+}
+
+func createDropTableStmt(table string, ifExists bool) *dropTableStmt {
+	return &dropTableStmt{
+		Table:      table,
+		IsIfExists: ifExists,
+	}
+}
+
+// Build builds `DROP TABLE ...` in dialect
+func (b *dropTableStmt) Build(d Dialect, buf Buffer) error {
+	if b.Table == "" {
+		return ErrTableNotSpecified
+	}
+
+	buf.WriteString("DROP TABLE ")
+	if b.IsIfExists {
+		buf.WriteString("IF EXISTS ")
+	}
+	buf.WriteString(d.QuoteIdent(b.Table))
+
+	if keyword := d.DropTableSync(); len(keyword) > 0 {
+		buf.WriteString(" ")
+		buf.WriteString(keyword)
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package dbr
+
+import (
+	"context"
+	"database/sql"
+)
+
+// CreateTableBuilder builds "CREATE TABLE ..." stmt
+type CreateTableBuilder interface {
+	Builder
+	EventReceiver
+	Executer
+
+	Column(name, typ string) CreateTableBuilder
+	PrimaryKey(column ...string) CreateTableBuilder
+	ForeignKey(column, refTable, refColumn string) CreateTableBuilder
+	OnDelete(action string) CreateTableBuilder
+	OnUpdate(action string) CreateTableBuilder
+	IfNotExists() CreateTableBuilder
+	WithEventKv(key, value string) CreateTableBuilder
+}
+
+type createTableBuilder struct {
+	runner
+	EventReceiver
+
+	Dialect         Dialect
+	createTableStmt *createTableStmt
+	eventKvs        kvs
+}
+
+// CreateTable creates a CreateTableBuilder, it is intended for test fixtures
+func (sess *Session) CreateTable(table string) CreateTableBuilder {
+	return &createTableBuilder{
+		runner:          sess,
+		EventReceiver:   sess.EventReceiver,
+		Dialect:         sess.Dialect,
+		createTableStmt: createCreateTableStmt(table),
+	}
+}
+
+// CreateTable creates a CreateTableBuilder, it is intended for test fixtures
+func (tx *Tx) CreateTable(table string) CreateTableBuilder {
+	return &createTableBuilder{
+		runner:          tx,
+		EventReceiver:   tx.EventReceiver,
+		Dialect:         tx.Dialect,
+		createTableStmt: createCreateTableStmt(table),
+	}
+}
+
+// Exec executes the stmt with background context
+func (b *createTableBuilder) Exec() (sql.Result, error) {
+	return b.ExecContext(context.Background())
+}
+
+// ExecContext executes the stmt
+func (b *createTableBuilder) ExecContext(ctx context.Context) (sql.Result, error) {
+	return exec(ctx, b.runner, b.EventReceiver, b, b.Dialect, b.eventKvs)
+}
+
+// Build builds `CREATE TABLE ...` in dialect
+func (b *createTableBuilder) Build(d Dialect, buf Buffer) error {
+	return b.createTableStmt.Build(b.Dialect, buf)
+}
+
+// Column adds a column, portable types are mapped to the types of dialect
+func (b *createTableBuilder) Column(name, typ string) CreateTableBuilder {
+	b.createTableStmt.Column(name, typ)
+	return b
+}
+
+// PrimaryKey specifies columns of primary key
+func (b *createTableBuilder) PrimaryKey(column ...string) CreateTableBuilder {
+	b.createTableStmt.PrimaryKey(column...)
+	return b
+}
+
+// ForeignKey adds a foreign key referencing refColumn of refTable
+func (b *createTableBuilder) ForeignKey(column, refTable, refColumn string) CreateTableBuilder {
+	b.createTableStmt.ForeignKey(column, refTable, refColumn)
+	return b
+}
+
+// OnDelete sets action of the last foreign key on delete of referenced row
+func (b *createTableBuilder) OnDelete(action string) CreateTableBuilder {
+	b.createTableStmt.OnDelete(action)
+	return b
+}
+
+// OnUpdate sets action of the last foreign key on update of referenced row
+func (b *createTableBuilder) OnUpdate(action string) CreateTableBuilder {
+	b.createTableStmt.OnUpdate(action)
+	return b
+}
+
+// IfNotExists makes the statement do nothing if the table exists already
+func (b *createTableBuilder) IfNotExists() CreateTableBuilder {
+	b.createTableStmt.IfNotExists()
+	return b
+}
+
+// WithEventKv adds a key/value pair to the events of this query,
+// it never overwrites the keys set by dbr itself, e.g. "sql"
+func (b *createTableBuilder) WithEventKv(key, value string) CreateTableBuilder {
+	if b.eventKvs == nil {
+		b.eventKvs = make(kvs)
+	}
+	b.eventKvs[key] = value
+	return b
+}
+
+// DropTableBuilder builds "DROP TABLE ..." stmt
+type DropTableBuilder interface {
+	Builder
+	EventReceiver
+	Executer
+
+	WithEventKv(key, value string) DropTableBuilder
+}
+
+type dropTableBuilder struct {
+	runner
+	EventReceiver
+
+	Dialect       Dialect
+	dropTableStmt *dropTableStmt
+	eventKvs      kvs
+}
+
+// DropTable creates a DropTableBuilder, table may be qualified by database or schema
+func (sess *Session) DropTable(table string) DropTableBuilder {
+	return sess.dropTable(table, false)
+}
+
+// DropTableIfExists creates a DropTableBuilder which does nothing if the table does not exist
+func (sess *Session) DropTableIfExists(table string) DropTableBuilder {
+	return sess.dropTable(table, true)
+}
+
+func (sess *Session) dropTable(table string, ifExists bool) DropTableBuilder {
+	return &dropTableBuilder{
+		runner:        sess,
+		EventReceiver: sess.EventReceiver,
+		Dialect:       sess.Dialect,
+		dropTableStmt: createDropTableStmt(table, ifExists),
+	}
+}
+
+// DropTable creates a DropTableBuilder, table may be qualified by database or schema
+func (tx *Tx) DropTable(table string) DropTableBuilder {
+	return tx.dropTable(table, false)
+}
+
+// DropTableIfExists creates a DropTableBuilder which does nothing if the table does not exist
+func (tx *Tx) DropTableIfExists(table string) DropTableBuilder {
+	return tx.dropTable(table, true)
+}
+
+func (tx *Tx) dropTable(table string, ifExists bool) DropTableBuilder {
+	return &dropTableBuilder{
+		runner:        tx,
+		EventReceiver: tx.EventReceiver,
+		Dialect:       tx.Dialect,
+		dropTableStmt: createDropTableStmt(table, ifExists),
+	}
+}
+
+// Exec executes the stmt with background context
+func (b *dropTableBuilder) Exec() (sql.Result, error) {
+	return b.ExecContext(context.Background())
+}
+
+// ExecContext executes the stmt
+func (b *dropTableBuilder) ExecContext(ctx context.Context) (sql.Result, error) {
+	return exec(ctx, b.runner, b.EventReceiver, b, b.Dialect, b.eventKvs)
+}
+
+// Build builds `DROP TABLE ...` in dialect
+func (b *dropTableBuilder) Build(d Dialect, buf Buffer) error {
+	return b.dropTableStmt.Build(b.Dialect, buf)
+}
+
+// WithEventKv adds a key/value pair to the events of this query,
+// it never overwrites the keys set by dbr itself, e.g. "sql"
+func (b *dropTableBuilder) WithEventKv(key, value string) DropTableBuilder {
+	if b.eventKvs == nil {
+		b.eventKvs = make(kvs)
+	}
+	b.eventKvs[key] = value
+	return b
+}
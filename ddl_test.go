@@ -0,0 +1,134 @@
+package dbr
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mailru/dbr/dialect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateTableStmt(t *testing.T) {
+	for _, test := range []struct {
+		stmt  CreateTableStmt
+		d     Dialect
+		query string
+	}{
+		{
+			stmt:  CreateTable("t").Column("id", "INT").PrimaryKey("id").IfNotExists(),
+			d:     dialect.PostgreSQL,
+			query: `CREATE TABLE IF NOT EXISTS "t" ("id" INTEGER, PRIMARY KEY ("id"))`,
+		},
+		{
+			stmt:  CreateTable("t").Column("id", "INT").Column("at", "TIMESTAMP").PrimaryKey("id"),
+			d:     dialect.MySQL,
+			query: "CREATE TABLE `t` (`id` INT, `at` DATETIME(6), PRIMARY KEY (`id`))",
+		},
+		{
+			stmt:  CreateTable("t").Column("id", "INT").Column("name", "TEXT").PrimaryKey("id", "name"),
+			d:     dialect.ClickHouse,
+			query: "CREATE TABLE `t` (`id` Int32, `name` String) ENGINE = MergeTree ORDER BY (`id`,`name`)",
+		},
+		{
+			stmt:  CreateTable("t").Column("id", "INT"),
+			d:     dialect.ClickHouse,
+			query: "CREATE TABLE `t` (`id` Int32) ENGINE = Memory",
+		},
+		{
+			stmt: CreateTable("comment").
+				Column("id", "INT").
+				Column("post_id", "BIGINT").
+				ForeignKey("post_id", "post", "id").OnDelete("CASCADE").OnUpdate("SET NULL"),
+			d:     dialect.SQLite3,
+			query: `CREATE TABLE "comment" ("id" INTEGER, "post_id" INTEGER, FOREIGN KEY ("post_id") REFERENCES "post" ("id") ON DELETE CASCADE ON UPDATE SET NULL)`,
+		},
+		{
+			stmt:  CreateTable("comment").Column("post_id", "BIGINT").ForeignKey("post_id", "post", "id").OnDelete("CASCADE"),
+			d:     dialect.ClickHouse,
+			query: "CREATE TABLE `comment` (`post_id` Int64) ENGINE = Memory",
+		},
+	} {
+		buf := NewBuffer()
+		err := test.stmt.Build(test.d, buf)
+		assert.NoError(t, err)
+		assert.Equal(t, test.query, buf.String())
+	}
+
+	err := CreateTable("t").Build(dialect.MySQL, NewBuffer())
+	assert.Equal(t, ErrColumnNotSpecified, err)
+	err = CreateTable("").Column("id", "INT").Build(dialect.MySQL, NewBuffer())
+	assert.Equal(t, ErrTableNotSpecified, err)
+	err = CreateTable("t").Column("id", "INT").OnDelete("CASCADE").Build(dialect.MySQL, NewBuffer())
+	assert.EqualError(t, err, "dbr: OnDelete requires a foreign key")
+	err = CreateTable("t").Column("id", "INT").OnUpdate("CASCADE").ForeignKey("id", "p", "id").Build(dialect.MySQL, NewBuffer())
+	assert.EqualError(t, err, "dbr: OnUpdate requires a foreign key")
+	err = CreateTable("t").Column("id", "INT").ForeignKey("id", "p", "id").OnDelete("CASCADE; DROP TABLE p").
+		Build(dialect.ClickHouse, NewBuffer())
+	assert.EqualError(t, err, `dbr: invalid foreign key action "CASCADE; DROP TABLE p"`)
+}
+
+func TestCreateTableBuilder(t *testing.T) {
+	db, dbmock, err := sqlmock.New()
+	assert.NoError(t, err)
+	conn := &Connection{DB: db, Dialect: dialect.PostgreSQL, EventReceiver: nullReceiver}
+	dbmock.ExpectExec(`CREATE TABLE IF NOT EXISTS "t" \("id" BIGINT, "data" BYTEA, PRIMARY KEY \("id"\)\)`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	_, err = conn.NewSession(nil).CreateTable("t").
+		Column("id", "BIGINT").
+		Column("data", "BYTES").
+		PrimaryKey("id").
+		IfNotExists().
+		Exec()
+	assert.NoError(t, err)
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+}
+
+func TestDropTableStmt(t *testing.T) {
+	for _, test := range []struct {
+		stmt  DropTableStmt
+		d     Dialect
+		query string
+	}{
+		{
+			stmt:  DropTable("t"),
+			d:     dialect.MySQL,
+			query: "DROP TABLE `t`",
+		},
+		{
+			stmt:  DropTableIfExists("public.t"),
+			d:     dialect.PostgreSQL,
+			query: `DROP TABLE IF EXISTS "public"."t"`,
+		},
+		{
+			stmt:  DropTableIfExists("t"),
+			d:     dialect.SQLite3,
+			query: `DROP TABLE IF EXISTS "t"`,
+		},
+		{
+			stmt:  DropTableIfExists("test.t"),
+			d:     dialect.ClickHouse,
+			query: "DROP TABLE IF EXISTS `test`.`t` SYNC",
+		},
+	} {
+		buf := NewBuffer()
+		err := test.stmt.Build(test.d, buf)
+		assert.NoError(t, err)
+		assert.Equal(t, test.query, buf.String())
+	}
+
+	err := DropTable("").Build(dialect.MySQL, NewBuffer())
+	assert.Equal(t, ErrTableNotSpecified, err)
+}
+
+func TestDropTableBuilder(t *testing.T) {
+	sess, dbmock, recv := newRecordingSessionMock()
+	dbmock.ExpectExec("DROP TABLE IF EXISTS `test`.`t`").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	_, err := sess.DropTableIfExists("test.t").WithEventKv("fixture", "t").Exec()
+	assert.NoError(t, err)
+	assert.Equal(t, []testEvent{
+		{name: "dbr.exec", kvs: map[string]string{"sql": "DROP TABLE IF EXISTS `test`.`t`", "fixture": "t"}},
+	}, recv.events)
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+}
@@ -0,0 +1,111 @@
+package dbr
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+// maxDecimalScale is the number of digits after the point for numbers without finite decimal representation
+const maxDecimalScale = 30
+
+// Decimal is an exact number for NUMERIC/DECIMAL columns, which lose precision through float64.
+// Decimal with nil Rat is NULL
+type Decimal struct {
+	*big.Rat
+}
+
+// NewDecimal parses decimal number from s, e.g. "12345.6789"
+func NewDecimal(s string) (Decimal, error) {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Decimal{}, fmt.Errorf("dbr: invalid decimal %q", s)
+	}
+	return Decimal{Rat: r}, nil
+}
+
+// Valid is true if Decimal is not NULL
+func (d Decimal) Valid() bool {
+	return d.Rat != nil
+}
+
+// String formats decimal number without loss of precision,
+// numbers without finite decimal representation (e.g. 1/3) are rounded to 30 digits after the point
+func (d Decimal) String() string {
+	if d.Rat == nil {
+		return "NULL"
+	}
+	return formatRat(d.Rat)
+}
+
+func formatRat(r *big.Rat) string {
+	// finite decimal number becomes integer when multiplied by 10^scale
+	x := new(big.Rat).Set(r)
+	ten := big.NewRat(10, 1)
+	scale := 0
+	for ; !x.IsInt() && scale < maxDecimalScale; scale++ {
+		x.Mul(x, ten)
+	}
+	return r.FloatString(scale)
+}
+
+// Value implements the driver Valuer interface.
+func (d Decimal) Value() (driver.Value, error) {
+	if d.Rat == nil {
+		return nil, nil
+	}
+	return formatRat(d.Rat), nil
+}
+
+// Scan implements the Scanner interface.
+// The value type must be string / []byte (formatted number), int64 or float64
+func (d *Decimal) Scan(value interface{}) error {
+	var s string
+	switch v := value.(type) {
+	case nil:
+		d.Rat = nil
+		return nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case float64:
+		// shortest representation, so 12345.6789 is not turned into 12345.678900000000794
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Errorf("dbr: cannot scan %T into Decimal", value)
+	}
+	dec, err := NewDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = dec
+	return nil
+}
+
+// MarshalJSON serializes a Decimal to JSON number
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	if d.Rat == nil {
+		return nullString, nil
+	}
+	return []byte(formatRat(d.Rat)), nil
+}
+
+// UnmarshalJSON deserializes a Decimal from JSON number or string
+func (d *Decimal) UnmarshalJSON(b []byte) error {
+	var s interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&s); err != nil {
+		return err
+	}
+	if n, ok := s.(json.Number); ok {
+		s = string(n)
+	}
+	return d.Scan(s)
+}
@@ -0,0 +1,106 @@
+package dbr
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/mailru/dbr/dialect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecimalString(t *testing.T) {
+	for _, test := range []struct {
+		in   *big.Rat
+		want string
+	}{
+		{in: big.NewRat(123456789, 10000), want: "12345.6789"},
+		{in: big.NewRat(-1, 8), want: "-0.125"},
+		{in: big.NewRat(42, 1), want: "42"},
+		{in: big.NewRat(1, 3), want: "0.333333333333333333333333333333"},
+		{want: "NULL"},
+	} {
+		assert.Equal(t, test.want, Decimal{Rat: test.in}.String())
+	}
+}
+
+func TestDecimalScan(t *testing.T) {
+	want := big.NewRat(123456789, 10000)
+	for _, v := range []interface{}{"12345.6789", []byte("12345.6789"), 12345.6789} {
+		var d Decimal
+		assert.NoError(t, d.Scan(v))
+		assert.Equal(t, 0, want.Cmp(d.Rat), "%v", v)
+	}
+
+	var d Decimal
+	assert.NoError(t, d.Scan(int64(7)))
+	assert.Equal(t, "7", d.String())
+	assert.NoError(t, d.Scan(nil))
+	assert.False(t, d.Valid())
+	assert.Error(t, d.Scan("abc"))
+	assert.Error(t, d.Scan(true))
+}
+
+func TestDecimalInterpolate(t *testing.T) {
+	d, err := NewDecimal("12345.6789")
+	assert.NoError(t, err)
+	for _, test := range []struct {
+		value interface{}
+		want  string
+	}{
+		{value: d, want: "12345.6789"},
+		{value: &d, want: "12345.6789"},
+		{value: big.NewRat(-5, 4), want: "-1.25"},
+		{value: Decimal{}, want: "NULL"},
+		{value: (*big.Rat)(nil), want: "NULL"},
+	} {
+		for _, dia := range []Dialect{dialect.MySQL, dialect.PostgreSQL, dialect.SQLite3, dialect.ClickHouse} {
+			s, err := InterpolateForDialect("?", []interface{}{test.value}, dia)
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, s)
+		}
+	}
+}
+
+func TestDecimalPlaceholder(t *testing.T) {
+	d, err := NewDecimal("12345.6789")
+	assert.NoError(t, err)
+	for _, test := range []struct {
+		value interface{}
+		want  driver.Value
+	}{
+		{value: d, want: "12345.6789"},
+		{value: big.NewRat(-5, 4), want: "-1.25"},
+		{value: (*big.Rat)(nil), want: nil},
+	} {
+		i := interpolator{Buffer: NewBuffer(), Dialect: dialect.PostgreSQL, UsePlaceholders: true}
+		assert.NoError(t, i.interpolate("?", []interface{}{test.value}))
+		assert.Equal(t, "$1", i.String())
+		assert.Len(t, i.Value(), 1)
+		// bound values are accepted by drivers
+		v, err := driver.DefaultParameterConverter.ConvertValue(i.Value()[0])
+		assert.NoError(t, err)
+		assert.Equal(t, test.want, v)
+	}
+}
+
+func TestDecimalJSON(t *testing.T) {
+	d, err := NewDecimal("12345.6789")
+	assert.NoError(t, err)
+	b, err := json.Marshal(d)
+	assert.NoError(t, err)
+	assert.Equal(t, "12345.6789", string(b))
+
+	var got Decimal
+	assert.NoError(t, json.Unmarshal(b, &got))
+	assert.Equal(t, 0, d.Cmp(got.Rat))
+	assert.NoError(t, json.Unmarshal([]byte(`"0.1"`), &got))
+	assert.Equal(t, "0.1", got.String())
+	assert.NoError(t, json.Unmarshal([]byte(`null`), &got))
+	assert.False(t, got.Valid())
+
+	b, err = json.Marshal(Decimal{})
+	assert.NoError(t, err)
+	assert.Equal(t, "null", string(b))
+}
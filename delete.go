@@ -4,12 +4,14 @@ package dbr
 type DeleteStmt interface {
 	Builder
 	Where(query interface{}, value ...interface{}) DeleteStmt
+	Only() DeleteStmt
 }
 
 type deleteStmt struct {
 	raw
 
 	Table     string
+	IsOnly    bool
 	WhereCond []Builder
 }
 
@@ -24,6 +26,12 @@ func (b *deleteStmt) Build(d Dialect, buf Buffer) error {
 	}
 
 	buf.WriteString("DELETE FROM ")
+	if b.IsOnly {
+		if !d.SupportsOnly() {
+			return ErrOnlyNotSupported
+		}
+		buf.WriteString("ONLY ")
+	}
 	buf.WriteString(d.QuoteIdent(b.Table))
 
 	if len(b.WhereCond) > 0 {
@@ -71,3 +79,10 @@ func (b *deleteStmt) Where(query interface{}, value ...interface{}) DeleteStmt {
 	}
 	return b
 }
+
+// Only adds `ONLY` before the table, so rows of tables inheriting it are not deleted.
+// It is supported by PostgreSQL only, other dialects return ErrOnlyNotSupported
+func (b *deleteStmt) Only() DeleteStmt {
+	b.IsOnly = true
+	return b
+}
@@ -3,7 +3,6 @@ package dbr
 import (
 	"context"
 	"database/sql"
-	"fmt"
 )
 
 // DeleteBuilder builds "DELETE ..." stmt
@@ -13,7 +12,10 @@ type DeleteBuilder interface {
 	Executer
 
 	Where(query interface{}, value ...interface{}) DeleteBuilder
+	OrderBy(col string) DeleteBuilder
 	Limit(n uint64) DeleteBuilder
+	Only() DeleteBuilder
+	WithEventKv(key, value string) DeleteBuilder
 }
 
 type deleteBuilder struct {
@@ -22,7 +24,9 @@ type deleteBuilder struct {
 
 	Dialect    Dialect
 	deleteStmt *deleteStmt
+	Order      []Builder
 	LimitCount int64
+	eventKvs   kvs
 }
 
 // DeleteFrom creates a DeleteBuilder
@@ -76,7 +80,7 @@ func (b *deleteBuilder) Exec() (sql.Result, error) {
 
 // ExecContext executes the stmt
 func (b *deleteBuilder) ExecContext(ctx context.Context) (sql.Result, error) {
-	return exec(ctx, b.runner, b.EventReceiver, b, b.Dialect)
+	return exec(ctx, b.runner, b.EventReceiver, b, b.Dialect, b.eventKvs)
 }
 
 // Where adds condition to the stmt
@@ -85,7 +89,21 @@ func (b *deleteBuilder) Where(query interface{}, value ...interface{}) DeleteBui
 	return b
 }
 
-// Limit adds LIMIT
+// Only adds `ONLY` before the table, so rows of tables inheriting it are not deleted, PostgreSQL only
+func (b *deleteBuilder) Only() DeleteBuilder {
+	b.deleteStmt.Only()
+	return b
+}
+
+// OrderBy specifies column for ordering, rows are deleted in this order,
+// supported by MySQL and SQLite3 only
+func (b *deleteBuilder) OrderBy(col string) DeleteBuilder {
+	b.Order = append(b.Order, Expr(col))
+	return b
+}
+
+// Limit adds LIMIT, supported by MySQL and SQLite3 only
+// (SQLite3 must be compiled with SQLITE_ENABLE_UPDATE_DELETE_LIMIT)
 func (b *deleteBuilder) Limit(n uint64) DeleteBuilder {
 	b.LimitCount = int64(n)
 	return b
@@ -97,9 +115,38 @@ func (b *deleteBuilder) Build(d Dialect, buf Buffer) error {
 	if err != nil {
 		return err
 	}
+	if len(b.Order) > 0 {
+		if len(b.Dialect.DeleteLimit(0)) == 0 {
+			return ErrDeleteLimitNotSupported
+		}
+		buf.WriteString(" ORDER BY ")
+		for i, order := range b.Order {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			err := order.Build(b.Dialect, buf)
+			if err != nil {
+				return err
+			}
+		}
+	}
 	if b.LimitCount >= 0 {
-		buf.WriteString(" LIMIT ")
-		buf.WriteString(fmt.Sprint(b.LimitCount))
+		keyword := b.Dialect.DeleteLimit(b.LimitCount)
+		if len(keyword) == 0 {
+			return ErrDeleteLimitNotSupported
+		}
+		buf.WriteString(" ")
+		buf.WriteString(keyword)
 	}
 	return nil
 }
+
+// WithEventKv adds a key/value pair to the events of this query,
+// it never overwrites the keys set by dbr itself, e.g. "sql"
+func (b *deleteBuilder) WithEventKv(key, value string) DeleteBuilder {
+	if b.eventKvs == nil {
+		b.eventKvs = make(kvs)
+	}
+	b.eventKvs[key] = value
+	return b
+}
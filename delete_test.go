@@ -16,6 +16,58 @@ func TestDeleteStmt(t *testing.T) {
 	assert.Equal(t, []interface{}{1}, buf.Value())
 }
 
+func TestDeleteStmtOnly(t *testing.T) {
+	buf := NewBuffer()
+	err := DeleteFrom("events").Only().Where(Eq("a", 1)).Build(dialect.PostgreSQL, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, `DELETE FROM ONLY "events" WHERE ("a" = ?)`, buf.String())
+
+	err = DeleteFrom("events").Only().Build(dialect.MySQL, NewBuffer())
+	assert.Equal(t, ErrOnlyNotSupported, err)
+}
+
+func TestDeleteBuilderOrderLimit(t *testing.T) {
+	for _, test := range []struct {
+		d     Dialect
+		query string
+		err   error
+	}{
+		{
+			d:     dialect.MySQL,
+			query: "DELETE FROM `table` WHERE (`a` = ?) ORDER BY id LIMIT 10",
+		},
+		{
+			d:     dialect.SQLite3,
+			query: `DELETE FROM "table" WHERE ("a" = ?) ORDER BY id LIMIT 10`,
+		},
+		{
+			d:   dialect.PostgreSQL,
+			err: ErrDeleteLimitNotSupported,
+		},
+		{
+			d:   dialect.ClickHouse,
+			err: ErrDeleteLimitNotSupported,
+		},
+	} {
+		sess := (&Connection{Dialect: test.d}).NewSession(nil)
+		buf := NewBuffer()
+		err := sess.DeleteFrom("table").Where(Eq("a", 1)).OrderBy("id").Limit(10).Build(test.d, buf)
+		if test.err != nil {
+			assert.Equal(t, test.err, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, test.query, buf.String())
+		assert.Equal(t, []interface{}{1}, buf.Value())
+	}
+
+	// ORDER BY alone is rejected as well
+	buf := NewBuffer()
+	sess := (&Connection{Dialect: dialect.PostgreSQL}).NewSession(nil)
+	err := sess.DeleteFrom("table").OrderBy("id").Build(dialect.PostgreSQL, buf)
+	assert.Equal(t, ErrDeleteLimitNotSupported, err)
+}
+
 func BenchmarkDeleteSQL(b *testing.B) {
 	buf := NewBuffer()
 	for i := 0; i < b.N; i++ {
@@ -15,4 +15,44 @@ type Dialect interface {
 	Proposed(column string) string
 	Limit(offset, limit int64) string
 	Prewhere() string
+	AggregateFilter() string
+	DeleteLimit(limit int64) string
+	Returning() string
+	SupportsTableFunction() bool
+	Explain(analyze bool) string
+	SupportsInsertSet() bool
+	AsyncInsert() string
+	SupportsMerge() bool
+	ColumnType(typ string) string
+	SupportsForeignKey() bool
+	TableEngine(primaryKey string) string
+	DropTableSync() string
+	UpsertInserted() string
+	UpsertAffectedRows() bool
+	SupportsIndexHint() bool
+	SupportsKeyLock() bool
+	SupportsWithTotals() bool
+	SupportsSavepoint() bool
+	TableSample(method string, percent float64) string
+	SupportsPlanHint() bool
+	DefaultValue() string
+	SupportsNullsOrdering() bool
+	SupportsLateralJoin() bool
+	SupportsDistinctOn() bool
+	SupportsCompositeType() bool
+	SupportsOnly() bool
+	DropTempTable() string
+	SupportsBool() bool
+	StandardLimit(offset, limit int64) string
+	InsertIgnore() string
+	OnConflictDoNothing() string
+	Collate(collation string) string
+	StringAgg(distinct bool, separator string) string
+	DateTrunc(unit string) string
+	ValuesTable(row []string, alias string, column []string) string
+	CountDistinct(column []string) string
+	StatementTimeout(timeout time.Duration) string
+	ResetStatementTimeout() string
+	StatementTimeoutSettings(timeout time.Duration) string
+	BackslashEscapes() bool
 }
@@ -3,6 +3,8 @@ package dialect
 import (
 	"bytes"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -90,3 +92,201 @@ func (d clickhouse) String() string {
 func (d clickhouse) Prewhere() string {
 	return "PREWHERE"
 }
+
+func (d clickhouse) AggregateFilter() string {
+	return ""
+}
+
+func (d clickhouse) DeleteLimit(_ int64) string {
+	return ""
+}
+
+func (d clickhouse) Returning() string {
+	return ""
+}
+
+func (d clickhouse) SupportsTableFunction() bool {
+	return true
+}
+
+func (d clickhouse) Explain(analyze bool) string {
+	if analyze {
+		return ""
+	}
+	return "EXPLAIN"
+}
+
+func (d clickhouse) SupportsInsertSet() bool {
+	return false
+}
+
+func (d clickhouse) AsyncInsert() string {
+	// https://clickhouse.com/docs/en/optimize/asynchronous-inserts
+	return "SETTINGS async_insert=1"
+}
+
+func (d clickhouse) SupportsMerge() bool {
+	return false
+}
+
+var clickhouseColumnTypes = map[string]string{
+	"INT":       "Int32",
+	"BIGINT":    "Int64",
+	"TEXT":      "String",
+	"BOOL":      "UInt8",
+	"DOUBLE":    "Float64",
+	"TIMESTAMP": "DateTime",
+	"BYTES":     "String",
+}
+
+func (d clickhouse) ColumnType(typ string) string {
+	return columnType(clickhouseColumnTypes, typ)
+}
+
+func (d clickhouse) SupportsForeignKey() bool {
+	return false
+}
+
+func (d clickhouse) TableEngine(primaryKey string) string {
+	if primaryKey == "" {
+		return "ENGINE = Memory"
+	}
+	return fmt.Sprintf("ENGINE = MergeTree ORDER BY (%s)", primaryKey)
+}
+
+func (d clickhouse) DropTableSync() string {
+	// waits for the data of Atomic database to be removed
+	return "SYNC"
+}
+
+func (d clickhouse) UpsertInserted() string {
+	return ""
+}
+
+func (d clickhouse) UpsertAffectedRows() bool {
+	return false
+}
+
+func (d clickhouse) SupportsIndexHint() bool {
+	return false
+}
+
+func (d clickhouse) SupportsKeyLock() bool {
+	return false
+}
+
+func (d clickhouse) SupportsWithTotals() bool {
+	return true
+}
+
+func (d clickhouse) SupportsSavepoint() bool {
+	return false
+}
+
+func (d clickhouse) TableSample(method string, percent float64) string {
+	// SAMPLE takes a ratio and reads a subset of granules like SYSTEM does
+	return "SAMPLE " + strconv.FormatFloat(percent/100, 'g', -1, 64)
+}
+
+func (d clickhouse) SupportsPlanHint() bool {
+	return false
+}
+
+func (d clickhouse) DefaultValue() string {
+	return ""
+}
+
+func (d clickhouse) SupportsNullsOrdering() bool {
+	return true
+}
+
+func (d clickhouse) SupportsLateralJoin() bool {
+	return false
+}
+
+func (d clickhouse) SupportsDistinctOn() bool {
+	return true
+}
+
+func (d clickhouse) SupportsCompositeType() bool {
+	return false
+}
+
+func (d clickhouse) SupportsOnly() bool {
+	return false
+}
+
+func (d clickhouse) DropTempTable() string {
+	return ""
+}
+
+func (d clickhouse) SupportsBool() bool {
+	// conditions are UInt8, Bool type is not supported by old servers
+	return false
+}
+
+func (d clickhouse) StandardLimit(offset, limit int64) string {
+	return ""
+}
+
+func (d clickhouse) InsertIgnore() string {
+	return ""
+}
+
+func (d clickhouse) OnConflictDoNothing() string {
+	return ""
+}
+
+func (d clickhouse) Collate(collation string) string {
+	// only in ORDER BY, collation is a locale, e.g. 'en'
+	return "COLLATE " + d.EncodeString(collation)
+}
+
+func (d clickhouse) StringAgg(distinct bool, separator string) string {
+	if distinct {
+		return "arrayStringConcat(groupUniqArray(?), " + d.EncodeString(separator) + ")"
+	}
+	return "arrayStringConcat(groupArray(?), " + d.EncodeString(separator) + ")"
+}
+
+func (d clickhouse) DateTrunc(unit string) string {
+	switch strings.ToLower(unit) {
+	case "hour":
+		return "toStartOfHour(?)"
+	case "day":
+		return "toStartOfDay(?)"
+	case "month":
+		return "toStartOfMonth(?)"
+	}
+	return ""
+}
+
+func (d clickhouse) ValuesTable(row []string, alias string, column []string) string {
+	return ""
+}
+
+func (d clickhouse) CountDistinct(column []string) string {
+	return "COUNT(DISTINCT " + strings.Join(column, ", ") + ")"
+}
+
+func (d clickhouse) StatementTimeout(timeout time.Duration) string {
+	// SET has no effect over HTTP, every query is a separate request, so it is set by SETTINGS of queries
+	return ""
+}
+
+func (d clickhouse) ResetStatementTimeout() string {
+	return ""
+}
+
+func (d clickhouse) StatementTimeoutSettings(timeout time.Duration) string {
+	if timeout < 0 {
+		timeout = 0
+	}
+	// max_execution_time is in seconds, a positive timeout is at least 1s
+	seconds := int64((timeout + time.Second - 1) / time.Second)
+	return "SETTINGS max_execution_time=" + strconv.FormatInt(seconds, 10)
+}
+
+func (d clickhouse) BackslashEscapes() bool {
+	return true
+}
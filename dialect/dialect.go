@@ -1,6 +1,9 @@
 package dialect
 
-import "strings"
+import (
+	"strings"
+	"time"
+)
 
 var (
 	//ClickHouse dialect
@@ -24,3 +27,35 @@ func quoteIdent(s, quote string) string {
 	}
 	return quote + s + quote
 }
+
+// columnType maps portable column type of test fixtures to type of dialect,
+// unknown types are used as is
+func columnType(types map[string]string, typ string) string {
+	if t, ok := types[strings.ToUpper(typ)]; ok {
+		return t
+	}
+	return typ
+}
+
+// quoteName quotes s as a single identifier, e.g. a collation `en_US.utf8`, which is not split by dots
+func quoteName(s, quote string) string {
+	return quote + strings.Replace(s, quote, quote+quote, -1) + quote
+}
+
+// quoteIdents quotes identifiers of column and joins them by comma
+func quoteIdents(d interface{ QuoteIdent(string) string }, column []string) string {
+	quoted := make([]string, len(column))
+	for i, c := range column {
+		quoted[i] = d.QuoteIdent(c)
+	}
+	return strings.Join(quoted, ",")
+}
+
+// timeoutMilliseconds returns timeout in whole milliseconds, rounded up, so a positive timeout is never 0,
+// which disables the timeout
+func timeoutMilliseconds(timeout time.Duration) int64 {
+	if timeout <= 0 {
+		return 0
+	}
+	return int64((timeout + time.Millisecond - 1) / time.Millisecond)
+}
@@ -59,3 +59,92 @@ func TestSQLite3(t *testing.T) {
 		assert.Equal(t, test.want, SQLite3.QuoteIdent(test.in))
 	}
 }
+
+func TestString(t *testing.T) {
+	assert.Equal(t, "mysql", MySQL.String())
+	assert.Equal(t, "postgresql", PostgreSQL.String())
+	assert.Equal(t, "sqlite3", SQLite3.String())
+	assert.Equal(t, "clickhouse", ClickHouse.String())
+}
+
+func TestExplain(t *testing.T) {
+	assert.Equal(t, "EXPLAIN", MySQL.Explain(false))
+	assert.Equal(t, "EXPLAIN (FORMAT JSON)", PostgreSQL.Explain(false))
+	assert.Equal(t, "EXPLAIN (ANALYZE, FORMAT JSON)", PostgreSQL.Explain(true))
+	assert.Equal(t, "EXPLAIN QUERY PLAN", SQLite3.Explain(false))
+	assert.Equal(t, "EXPLAIN", ClickHouse.Explain(false))
+	for _, d := range []interface{ Explain(bool) string }{MySQL, SQLite3, ClickHouse} {
+		assert.Equal(t, "", d.Explain(true))
+	}
+}
+
+func TestAsyncInsert(t *testing.T) {
+	assert.Equal(t, "SETTINGS async_insert=1", ClickHouse.AsyncInsert())
+	assert.Equal(t, "", MySQL.AsyncInsert())
+}
+
+func TestSupportsMerge(t *testing.T) {
+	assert.True(t, PostgreSQL.SupportsMerge())
+	assert.False(t, MySQL.SupportsMerge())
+}
+
+func TestColumnType(t *testing.T) {
+	assert.Equal(t, "DATETIME(6)", MySQL.ColumnType("timestamp"))
+	assert.Equal(t, "BYTEA", PostgreSQL.ColumnType("BYTES"))
+	assert.Equal(t, "INTEGER", SQLite3.ColumnType("BOOL"))
+	assert.Equal(t, "Int64", ClickHouse.ColumnType("BIGINT"))
+	assert.Equal(t, "VARCHAR(255)", MySQL.ColumnType("VARCHAR(255)"))
+}
+
+func TestTableEngine(t *testing.T) {
+	assert.Equal(t, "ENGINE = MergeTree ORDER BY (`id`)", ClickHouse.TableEngine("`id`"))
+	assert.Equal(t, "ENGINE = Memory", ClickHouse.TableEngine(""))
+	assert.Equal(t, "", PostgreSQL.TableEngine(`"id"`))
+	assert.False(t, ClickHouse.SupportsForeignKey())
+	assert.True(t, SQLite3.SupportsForeignKey())
+}
+
+func TestDropTableSync(t *testing.T) {
+	assert.Equal(t, "SYNC", ClickHouse.DropTableSync())
+	assert.Equal(t, "", MySQL.DropTableSync())
+}
+
+func TestStringAgg(t *testing.T) {
+	assert.Equal(t, "GROUP_CONCAT(DISTINCT ? SEPARATOR ', ')", MySQL.StringAgg(true, ", "))
+	assert.Equal(t, "string_agg(?, '''')", PostgreSQL.StringAgg(false, "'"))
+	assert.Equal(t, "group_concat(?, '|')", SQLite3.StringAgg(false, "|"))
+	assert.Equal(t, "", SQLite3.StringAgg(true, ","))
+}
+
+func TestDateTrunc(t *testing.T) {
+	assert.Equal(t, "date_trunc('day', ?)", PostgreSQL.DateTrunc("DAY"))
+	assert.Equal(t, "toStartOfMonth(?)", ClickHouse.DateTrunc("month"))
+	assert.Equal(t, "", MySQL.DateTrunc("week"))
+}
+
+func TestValuesTable(t *testing.T) {
+	row := []string{"(?,?)", "(?,?)"}
+	column := []string{"id", "name"}
+	assert.Equal(t, `(VALUES (?,?),(?,?)) AS "t"("id","name")`, PostgreSQL.ValuesTable(row, "t", column))
+	assert.Equal(t, "(VALUES ROW(?,?),ROW(?,?)) AS `t`(`id`,`name`)", MySQL.ValuesTable(row, "t", column))
+	assert.Equal(t, `(SELECT column1 AS "id",column2 AS "name" FROM (VALUES (?,?),(?,?))) AS "t"`, SQLite3.ValuesTable(row, "t", column))
+	assert.Equal(t, "", ClickHouse.ValuesTable(row, "t", column))
+}
+
+func TestTableSample(t *testing.T) {
+	assert.Equal(t, "TABLESAMPLE SYSTEM (10)", PostgreSQL.TableSample("SYSTEM", 10))
+	assert.Equal(t, "TABLESAMPLE BERNOULLI (0.5)", PostgreSQL.TableSample("BERNOULLI", 0.5))
+	assert.Equal(t, "SAMPLE 0.1", ClickHouse.TableSample("SYSTEM", 10))
+	assert.Equal(t, "", MySQL.TableSample("SYSTEM", 10))
+	assert.Equal(t, "", SQLite3.TableSample("SYSTEM", 10))
+}
+
+func TestCountDistinct(t *testing.T) {
+	column := []string{"a", "b"}
+	assert.Equal(t, "COUNT(DISTINCT a, b)", MySQL.CountDistinct(column))
+	assert.Equal(t, "COUNT(DISTINCT (a, b))", PostgreSQL.CountDistinct(column))
+	assert.Equal(t, "COUNT(DISTINCT a)", PostgreSQL.CountDistinct(column[:1]))
+	assert.Equal(t, "", SQLite3.CountDistinct(column))
+	assert.Equal(t, "COUNT(DISTINCT a)", SQLite3.CountDistinct(column[:1]))
+	assert.Equal(t, "COUNT(DISTINCT a, b)", ClickHouse.CountDistinct(column))
+}
@@ -3,6 +3,7 @@ package dialect
 import (
 	"bytes"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -80,6 +81,205 @@ func (d mysql) Limit(offset, limit int64) string {
 	return fmt.Sprintf("LIMIT %d,%d", offset, limit)
 }
 
+func (d mysql) String() string {
+	return "mysql"
+}
+
 func (d mysql) Prewhere() string {
 	return ""
 }
+
+func (d mysql) AggregateFilter() string {
+	return ""
+}
+
+func (d mysql) DeleteLimit(limit int64) string {
+	return fmt.Sprintf("LIMIT %d", limit)
+}
+
+func (d mysql) Returning() string {
+	return ""
+}
+
+func (d mysql) SupportsTableFunction() bool {
+	return false
+}
+
+func (d mysql) Explain(analyze bool) string {
+	if analyze {
+		return ""
+	}
+	return "EXPLAIN"
+}
+
+func (d mysql) SupportsInsertSet() bool {
+	return true
+}
+
+func (d mysql) AsyncInsert() string {
+	return ""
+}
+
+func (d mysql) SupportsMerge() bool {
+	return false
+}
+
+var mysqlColumnTypes = map[string]string{
+	"INT":       "INT",
+	"BIGINT":    "BIGINT",
+	"TEXT":      "TEXT",
+	"BOOL":      "BOOL",
+	"DOUBLE":    "DOUBLE",
+	"TIMESTAMP": "DATETIME(6)",
+	"BYTES":     "BLOB",
+}
+
+func (d mysql) ColumnType(typ string) string {
+	return columnType(mysqlColumnTypes, typ)
+}
+
+func (d mysql) SupportsForeignKey() bool {
+	return true
+}
+
+func (d mysql) TableEngine(_ string) string {
+	return ""
+}
+
+func (d mysql) DropTableSync() string {
+	return ""
+}
+
+func (d mysql) UpsertInserted() string {
+	return ""
+}
+
+func (d mysql) UpsertAffectedRows() bool {
+	// affected rows are 1 for inserted row and 2 for updated one
+	return true
+}
+
+func (d mysql) SupportsIndexHint() bool {
+	return true
+}
+
+func (d mysql) SupportsKeyLock() bool {
+	return false
+}
+
+func (d mysql) SupportsWithTotals() bool {
+	return false
+}
+
+func (d mysql) SupportsSavepoint() bool {
+	return true
+}
+
+func (d mysql) TableSample(method string, percent float64) string {
+	return ""
+}
+
+func (d mysql) SupportsPlanHint() bool {
+	return false
+}
+
+func (d mysql) DefaultValue() string {
+	return "DEFAULT"
+}
+
+func (d mysql) SupportsNullsOrdering() bool {
+	return false
+}
+
+func (d mysql) SupportsLateralJoin() bool {
+	// LATERAL appeared in 8.0.14, it is not supported by MariaDB
+	return false
+}
+
+func (d mysql) SupportsDistinctOn() bool {
+	return false
+}
+
+func (d mysql) SupportsCompositeType() bool {
+	return false
+}
+
+func (d mysql) SupportsOnly() bool {
+	return false
+}
+
+func (d mysql) DropTempTable() string {
+	// TEMPORARY does not commit the transaction implicitly
+	return "DROP TEMPORARY TABLE"
+}
+
+func (d mysql) SupportsBool() bool {
+	return true
+}
+
+func (d mysql) StandardLimit(offset, limit int64) string {
+	return ""
+}
+
+func (d mysql) InsertIgnore() string {
+	return "IGNORE"
+}
+
+func (d mysql) OnConflictDoNothing() string {
+	return ""
+}
+
+func (d mysql) Collate(collation string) string {
+	return "COLLATE " + quoteName(collation, "`")
+}
+
+func (d mysql) StringAgg(distinct bool, separator string) string {
+	// SEPARATOR must be a literal, it can not be a placeholder of prepared statement
+	if distinct {
+		return "GROUP_CONCAT(DISTINCT ? SEPARATOR " + d.EncodeString(separator) + ")"
+	}
+	return "GROUP_CONCAT(? SEPARATOR " + d.EncodeString(separator) + ")"
+}
+
+func (d mysql) DateTrunc(unit string) string {
+	switch strings.ToLower(unit) {
+	case "hour":
+		return "DATE_FORMAT(?, '%Y-%m-%d %H:00:00')"
+	case "day":
+		return "DATE_FORMAT(?, '%Y-%m-%d')"
+	case "month":
+		return "DATE_FORMAT(?, '%Y-%m-01')"
+	}
+	return ""
+}
+
+func (d mysql) ValuesTable(row []string, alias string, column []string) string {
+	// MySQL 8.0.19+ requires ROW constructors in VALUES statement
+	rows := make([]string, len(row))
+	for i, r := range row {
+		rows[i] = "ROW" + r
+	}
+	return "(VALUES " + strings.Join(rows, ",") + ") AS " + d.QuoteIdent(alias) + "(" + quoteIdents(d, column) + ")"
+}
+
+func (d mysql) CountDistinct(column []string) string {
+	return "COUNT(DISTINCT " + strings.Join(column, ", ") + ")"
+}
+
+func (d mysql) StatementTimeout(timeout time.Duration) string {
+	// of SELECT only, the variable is kept by the connection after the transaction
+	return fmt.Sprintf("SET SESSION max_execution_time = %d", timeoutMilliseconds(timeout))
+}
+
+func (d mysql) ResetStatementTimeout() string {
+	return "SET SESSION max_execution_time = DEFAULT"
+}
+
+func (d mysql) StatementTimeoutSettings(timeout time.Duration) string {
+	return ""
+}
+
+func (d mysql) BackslashEscapes() bool {
+	// backslash escapes quotes in strings, unless NO_BACKSLASH_ESCAPES is set
+	return true
+}
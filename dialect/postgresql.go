@@ -2,6 +2,7 @@ package dialect
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -51,6 +52,201 @@ func (d postgreSQL) Limit(offset, limit int64) string {
 	return fmt.Sprintf("LIMIT %d OFFSET %d", limit, offset)
 }
 
+func (d postgreSQL) String() string {
+	return "postgresql"
+}
+
 func (d postgreSQL) Prewhere() string {
 	return ""
 }
+
+func (d postgreSQL) AggregateFilter() string {
+	return "FILTER"
+}
+
+func (d postgreSQL) DeleteLimit(_ int64) string {
+	return ""
+}
+
+func (d postgreSQL) Returning() string {
+	return "RETURNING"
+}
+
+func (d postgreSQL) SupportsTableFunction() bool {
+	return true
+}
+
+func (d postgreSQL) Explain(analyze bool) string {
+	if analyze {
+		return "EXPLAIN (ANALYZE, FORMAT JSON)"
+	}
+	return "EXPLAIN (FORMAT JSON)"
+}
+
+func (d postgreSQL) SupportsInsertSet() bool {
+	return false
+}
+
+func (d postgreSQL) AsyncInsert() string {
+	return ""
+}
+
+func (d postgreSQL) SupportsMerge() bool {
+	// since PostgreSQL 15
+	return true
+}
+
+var postgreSQLColumnTypes = map[string]string{
+	"INT":       "INTEGER",
+	"BIGINT":    "BIGINT",
+	"TEXT":      "TEXT",
+	"BOOL":      "BOOLEAN",
+	"DOUBLE":    "DOUBLE PRECISION",
+	"TIMESTAMP": "TIMESTAMP",
+	"BYTES":     "BYTEA",
+}
+
+func (d postgreSQL) ColumnType(typ string) string {
+	return columnType(postgreSQLColumnTypes, typ)
+}
+
+func (d postgreSQL) SupportsForeignKey() bool {
+	return true
+}
+
+func (d postgreSQL) TableEngine(_ string) string {
+	return ""
+}
+
+func (d postgreSQL) DropTableSync() string {
+	return ""
+}
+
+func (d postgreSQL) UpsertInserted() string {
+	// xmax is zero for the row version which is inserted, not updated
+	return "(xmax = 0)"
+}
+
+func (d postgreSQL) UpsertAffectedRows() bool {
+	return false
+}
+
+func (d postgreSQL) SupportsIndexHint() bool {
+	return false
+}
+
+func (d postgreSQL) SupportsKeyLock() bool {
+	return true
+}
+
+func (d postgreSQL) SupportsWithTotals() bool {
+	return false
+}
+
+func (d postgreSQL) SupportsSavepoint() bool {
+	return true
+}
+
+func (d postgreSQL) TableSample(method string, percent float64) string {
+	return "TABLESAMPLE " + method + " (" + strconv.FormatFloat(percent, 'g', -1, 64) + ")"
+}
+
+func (d postgreSQL) SupportsPlanHint() bool {
+	return true
+}
+
+func (d postgreSQL) DefaultValue() string {
+	return "DEFAULT"
+}
+
+func (d postgreSQL) SupportsNullsOrdering() bool {
+	return true
+}
+
+func (d postgreSQL) SupportsLateralJoin() bool {
+	return true
+}
+
+func (d postgreSQL) SupportsDistinctOn() bool {
+	return true
+}
+
+func (d postgreSQL) SupportsCompositeType() bool {
+	return true
+}
+
+func (d postgreSQL) SupportsOnly() bool {
+	return true
+}
+
+func (d postgreSQL) DropTempTable() string {
+	return "DROP TABLE"
+}
+
+func (d postgreSQL) SupportsBool() bool {
+	return true
+}
+
+func (d postgreSQL) StandardLimit(offset, limit int64) string {
+	if offset < 0 {
+		return fmt.Sprintf("FETCH NEXT %d ROWS ONLY", limit)
+	}
+	return fmt.Sprintf("OFFSET %d ROWS FETCH NEXT %d ROWS ONLY", offset, limit)
+}
+
+func (d postgreSQL) InsertIgnore() string {
+	return ""
+}
+
+func (d postgreSQL) OnConflictDoNothing() string {
+	return "ON CONFLICT DO NOTHING"
+}
+
+func (d postgreSQL) Collate(collation string) string {
+	return "COLLATE " + quoteName(collation, `"`)
+}
+
+func (d postgreSQL) StringAgg(distinct bool, separator string) string {
+	if distinct {
+		return "string_agg(DISTINCT ?, " + d.EncodeString(separator) + ")"
+	}
+	return "string_agg(?, " + d.EncodeString(separator) + ")"
+}
+
+func (d postgreSQL) DateTrunc(unit string) string {
+	switch strings.ToLower(unit) {
+	case "hour", "day", "month":
+		return "date_trunc('" + strings.ToLower(unit) + "', ?)"
+	}
+	return ""
+}
+
+func (d postgreSQL) ValuesTable(row []string, alias string, column []string) string {
+	return "(VALUES " + strings.Join(row, ",") + ") AS " + d.QuoteIdent(alias) + "(" + quoteIdents(d, column) + ")"
+}
+
+func (d postgreSQL) CountDistinct(column []string) string {
+	if len(column) == 1 {
+		return "COUNT(DISTINCT " + column[0] + ")"
+	}
+	// a row value, rows with NULL columns are counted unlike in MySQL
+	return "COUNT(DISTINCT (" + strings.Join(column, ", ") + "))"
+}
+
+func (d postgreSQL) StatementTimeout(timeout time.Duration) string {
+	return fmt.Sprintf("SET LOCAL statement_timeout = %d", timeoutMilliseconds(timeout))
+}
+
+func (d postgreSQL) ResetStatementTimeout() string {
+	// SET LOCAL ends with the transaction
+	return ""
+}
+
+func (d postgreSQL) StatementTimeoutSettings(timeout time.Duration) string {
+	return ""
+}
+
+func (d postgreSQL) BackslashEscapes() bool {
+	// backslash escapes only in E'' strings, if standard_conforming_strings is on, the default
+	return false
+}
@@ -2,6 +2,7 @@ package dialect
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -54,6 +55,208 @@ func (d sqlite3) Limit(offset, limit int64) string {
 	return fmt.Sprintf("LIMIT %d OFFSET %d", limit, offset)
 }
 
+func (d sqlite3) String() string {
+	return "sqlite3"
+}
+
 func (d sqlite3) Prewhere() string {
 	return ""
 }
+
+func (d sqlite3) AggregateFilter() string {
+	// FILTER is supported since 3.30, go-sqlite3 v1.11.0 bundles 3.29
+	return ""
+}
+
+func (d sqlite3) DeleteLimit(limit int64) string {
+	// https://www.sqlite.org/compile.html#enable_update_delete_limit
+	return fmt.Sprintf("LIMIT %d", limit)
+}
+
+func (d sqlite3) Returning() string {
+	// https://www.sqlite.org/lang_returning.html, since 3.35, go-sqlite3 v1.11.0 bundles 3.29
+	return ""
+}
+
+func (d sqlite3) SupportsTableFunction() bool {
+	return false
+}
+
+func (d sqlite3) Explain(analyze bool) string {
+	if analyze {
+		return ""
+	}
+	return "EXPLAIN QUERY PLAN"
+}
+
+func (d sqlite3) SupportsInsertSet() bool {
+	return false
+}
+
+func (d sqlite3) AsyncInsert() string {
+	return ""
+}
+
+func (d sqlite3) SupportsMerge() bool {
+	return false
+}
+
+var sqlite3ColumnTypes = map[string]string{
+	"INT":       "INTEGER",
+	"BIGINT":    "INTEGER",
+	"TEXT":      "TEXT",
+	"BOOL":      "INTEGER",
+	"DOUBLE":    "REAL",
+	"TIMESTAMP": "TIMESTAMP",
+	"BYTES":     "BLOB",
+}
+
+func (d sqlite3) ColumnType(typ string) string {
+	return columnType(sqlite3ColumnTypes, typ)
+}
+
+func (d sqlite3) SupportsForeignKey() bool {
+	return true
+}
+
+func (d sqlite3) TableEngine(_ string) string {
+	return ""
+}
+
+func (d sqlite3) DropTableSync() string {
+	return ""
+}
+
+func (d sqlite3) UpsertInserted() string {
+	return ""
+}
+
+func (d sqlite3) UpsertAffectedRows() bool {
+	return false
+}
+
+func (d sqlite3) SupportsIndexHint() bool {
+	return false
+}
+
+func (d sqlite3) SupportsKeyLock() bool {
+	return false
+}
+
+func (d sqlite3) SupportsWithTotals() bool {
+	return false
+}
+
+func (d sqlite3) SupportsSavepoint() bool {
+	return true
+}
+
+func (d sqlite3) TableSample(method string, percent float64) string {
+	return ""
+}
+
+func (d sqlite3) SupportsPlanHint() bool {
+	return false
+}
+
+func (d sqlite3) DefaultValue() string {
+	return ""
+}
+
+func (d sqlite3) SupportsNullsOrdering() bool {
+	// NULLS FIRST and NULLS LAST appeared in 3.30
+	return false
+}
+
+func (d sqlite3) SupportsLateralJoin() bool {
+	return false
+}
+
+func (d sqlite3) SupportsDistinctOn() bool {
+	return false
+}
+
+func (d sqlite3) SupportsCompositeType() bool {
+	return false
+}
+
+func (d sqlite3) SupportsOnly() bool {
+	return false
+}
+
+func (d sqlite3) DropTempTable() string {
+	return ""
+}
+
+func (d sqlite3) SupportsBool() bool {
+	return true
+}
+
+func (d sqlite3) StandardLimit(offset, limit int64) string {
+	return ""
+}
+
+func (d sqlite3) InsertIgnore() string {
+	return ""
+}
+
+func (d sqlite3) OnConflictDoNothing() string {
+	return "ON CONFLICT DO NOTHING"
+}
+
+func (d sqlite3) Collate(collation string) string {
+	return "COLLATE " + quoteName(collation, `"`)
+}
+
+func (d sqlite3) StringAgg(distinct bool, separator string) string {
+	if distinct {
+		// DISTINCT aggregates must have exactly one argument
+		return ""
+	}
+	return "group_concat(?, " + d.EncodeString(separator) + ")"
+}
+
+func (d sqlite3) DateTrunc(unit string) string {
+	switch strings.ToLower(unit) {
+	case "hour":
+		return "strftime('%Y-%m-%d %H:00:00', ?)"
+	case "day":
+		return "strftime('%Y-%m-%d', ?)"
+	case "month":
+		return "strftime('%Y-%m-01', ?)"
+	}
+	return ""
+}
+
+func (d sqlite3) ValuesTable(row []string, alias string, column []string) string {
+	// columns of VALUES are named column1, column2, ... and can not be aliased in FROM
+	names := make([]string, len(column))
+	for i, c := range column {
+		names[i] = "column" + strconv.Itoa(i+1) + " AS " + d.QuoteIdent(c)
+	}
+	return "(SELECT " + strings.Join(names, ",") + " FROM (VALUES " + strings.Join(row, ",") + ")) AS " + d.QuoteIdent(alias)
+}
+
+func (d sqlite3) CountDistinct(column []string) string {
+	if len(column) == 1 {
+		return "COUNT(DISTINCT " + column[0] + ")"
+	}
+	// several columns are not supported, a subquery of distinct rows is counted
+	return ""
+}
+
+func (d sqlite3) StatementTimeout(timeout time.Duration) string {
+	return ""
+}
+
+func (d sqlite3) ResetStatementTimeout() string {
+	return ""
+}
+
+func (d sqlite3) StatementTimeoutSettings(timeout time.Duration) string {
+	return ""
+}
+
+func (d sqlite3) BackslashEscapes() bool {
+	return false
+}
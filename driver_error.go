@@ -0,0 +1,95 @@
+package dbr
+
+import "reflect"
+
+// DriverError wraps an error of driver recognized as one of ErrDuplicateKey, ErrForeignKeyViolation,
+// ErrNotNullViolation or ErrRowLocked, so errors.Is(err, dbr.ErrDuplicateKey) is true for it
+type DriverError struct {
+	// Kind is the package error the driver error is recognized as
+	Kind error
+	// Err is the original error of driver
+	Err error
+}
+
+func (e *DriverError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the original error of driver
+func (e *DriverError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the kind of the error
+func (e *DriverError) Is(target error) bool {
+	return target == e.Kind
+}
+
+// mysqlErrors maps MySQL error numbers to package errors
+var mysqlErrors = map[uint16]error{
+	1062: ErrDuplicateKey,        // ER_DUP_ENTRY
+	1586: ErrDuplicateKey,        // ER_DUP_ENTRY_WITH_KEY_NAME
+	1216: ErrForeignKeyViolation, // ER_NO_REFERENCED_ROW
+	1217: ErrForeignKeyViolation, // ER_ROW_IS_REFERENCED
+	1451: ErrForeignKeyViolation, // ER_ROW_IS_REFERENCED_2
+	1452: ErrForeignKeyViolation, // ER_NO_REFERENCED_ROW_2
+	1048: ErrNotNullViolation,    // ER_BAD_NULL_ERROR
+	3572: ErrRowLocked,           // ER_LOCK_NOWAIT
+	1205: ErrRowLocked,           // ER_LOCK_WAIT_TIMEOUT
+}
+
+// postgresErrors maps PostgreSQL SQLSTATE codes to package errors
+var postgresErrors = map[string]error{
+	"23505": ErrDuplicateKey,        // unique_violation
+	"23503": ErrForeignKeyViolation, // foreign_key_violation
+	"23502": ErrNotNullViolation,    // not_null_violation
+	"55P03": ErrRowLocked,           // lock_not_available, of NOWAIT and lock_timeout
+}
+
+// numberError is an error of driver with a MySQL error number
+type numberError interface {
+	Number() uint16
+}
+
+// sqlStateError is an error of driver with a PostgreSQL SQLSTATE code
+type sqlStateError interface {
+	SQLState() string
+}
+
+// wrapDriverError wraps err in DriverError if it is a known error of MySQL or PostgreSQL driver.
+// The drivers are not imported, so they are not registered by dbr, their errors are recognized
+// by numberError and sqlStateError or by fields of go-sql-driver/mysql and lib/pq error types
+func wrapDriverError(err error) error {
+	var kind error
+	switch e := err.(type) {
+	case numberError:
+		kind = mysqlErrors[e.Number()]
+	case sqlStateError:
+		kind = postgresErrors[e.SQLState()]
+	default:
+		kind = driverErrorKind(reflect.ValueOf(err))
+	}
+	if kind == nil {
+		return err
+	}
+	return &DriverError{Kind: kind, Err: err}
+}
+
+// driverErrorKind returns the package error for *mysql.MySQLError by its Number and for *pq.Error by its Code
+func driverErrorKind(v reflect.Value) error {
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	v = v.Elem()
+	switch t := v.Type(); t.PkgPath() + "." + t.Name() {
+	case "github.com/go-sql-driver/mysql.MySQLError":
+		if f := v.FieldByName("Number"); f.Kind() == reflect.Uint16 {
+			return mysqlErrors[uint16(f.Uint())]
+		}
+	case "github.com/lib/pq.Error":
+		if f := v.FieldByName("Code"); f.Kind() == reflect.String {
+			return postgresErrors[f.String()]
+		}
+	}
+	return nil
+}
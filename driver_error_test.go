@@ -0,0 +1,102 @@
+package dbr
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/mailru/dbr/dialect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapDriverError(t *testing.T) {
+	for _, test := range []struct {
+		err  error
+		kind error
+	}{
+		{err: &mysql.MySQLError{Number: 1062, Message: "Duplicate entry '1' for key 'PRIMARY'"}, kind: ErrDuplicateKey},
+		{err: &mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row"}, kind: ErrForeignKeyViolation},
+		{err: &mysql.MySQLError{Number: 1451, Message: "Cannot delete or update a parent row"}, kind: ErrForeignKeyViolation},
+		{err: &mysql.MySQLError{Number: 1048, Message: "Column 'name' cannot be null"}, kind: ErrNotNullViolation},
+		{err: &mysql.MySQLError{Number: 3572, Message: "Statement aborted because lock(s) could not be acquired immediately and NOWAIT is set."}, kind: ErrRowLocked},
+		{err: &mysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded; try restarting transaction"}, kind: ErrRowLocked},
+		{err: &pq.Error{Code: "23505", Message: "duplicate key value violates unique constraint"}, kind: ErrDuplicateKey},
+		{err: &pq.Error{Code: "23503", Message: "insert or update violates foreign key constraint"}, kind: ErrForeignKeyViolation},
+		{err: &pq.Error{Code: "23502", Message: "null value in column violates not-null constraint"}, kind: ErrNotNullViolation},
+		{err: &pq.Error{Code: "55P03", Message: "could not obtain lock on row in relation \"jobs\""}, kind: ErrRowLocked},
+	} {
+		err := wrapDriverError(test.err)
+		driverErr, ok := err.(*DriverError)
+		if assert.True(t, ok, test.err.Error()) {
+			assert.True(t, driverErr.Is(test.kind))
+			assert.Equal(t, test.err, driverErr.Unwrap())
+			assert.Equal(t, test.err.Error(), err.Error())
+		}
+	}
+
+	for _, err := range []error{
+		&mysql.MySQLError{Number: 1146, Message: "Table doesn't exist"},
+		&pq.Error{Code: "42P01", Message: "relation does not exist"},
+		errors.New("unknown"),
+		(*mysql.MySQLError)(nil),
+		testNumberError(1146),
+		testSQLStateError("42P01"),
+	} {
+		assert.Equal(t, err, wrapDriverError(err))
+	}
+
+	// errors of other drivers are recognized by methods
+	for _, test := range []struct {
+		err  error
+		kind error
+	}{
+		{err: testNumberError(1062), kind: ErrDuplicateKey},
+		{err: testSQLStateError("23505"), kind: ErrDuplicateKey},
+		{err: testSQLStateError("55P03"), kind: ErrRowLocked},
+	} {
+		if err, ok := wrapDriverError(test.err).(*DriverError); assert.True(t, ok, test.err.Error()) {
+			assert.Equal(t, test.kind, err.Kind)
+		}
+	}
+}
+
+type testNumberError uint16
+
+func (e testNumberError) Error() string  { return "number error" }
+func (e testNumberError) Number() uint16 { return uint16(e) }
+
+type testSQLStateError string
+
+func (e testSQLStateError) Error() string    { return "sqlstate error" }
+func (e testSQLStateError) SQLState() string { return string(e) }
+
+func TestExecDriverError(t *testing.T) {
+	sess, m, _ := newRecordingSessionMock()
+	driverErr := &mysql.MySQLError{Number: 1062, Message: "Duplicate entry '1' for key 'PRIMARY'"}
+	m.ExpectExec("INSERT INTO `users`").WillReturnError(driverErr)
+
+	_, err := sess.InsertInto("users").Columns("id").Values(1).Exec()
+	if assert.IsType(t, &DriverError{}, err) {
+		assert.Equal(t, ErrDuplicateKey, err.(*DriverError).Kind)
+		assert.Equal(t, driverErr, err.(*DriverError).Err)
+	}
+	assert.NoError(t, m.ExpectationsWereMet())
+}
+
+func TestLoadRowLocked(t *testing.T) {
+	db, m, err := sqlmock.New()
+	assert.NoError(t, err)
+	sess := (&Connection{DB: db, Dialect: dialect.PostgreSQL, EventReceiver: nullReceiver}).NewSession(nil)
+	driverErr := &pq.Error{Code: "55P03", Message: "could not obtain lock on row in relation \"jobs\""}
+	m.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM jobs WHERE ("state" = 'new') FOR UPDATE NOWAIT`)).WillReturnError(driverErr)
+
+	var id []int64
+	_, err = sess.Select("id").From("jobs").Where(Eq("state", "new")).ForUpdate().NoWait().Load(&id)
+	if assert.IsType(t, &DriverError{}, err) {
+		assert.Equal(t, ErrRowLocked, err.(*DriverError).Kind)
+	}
+	assert.NoError(t, m.ExpectationsWereMet())
+}
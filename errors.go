@@ -4,14 +4,52 @@ import "errors"
 
 // package errors
 var (
-	ErrNotFound             = errors.New("dbr: not found")
-	ErrNotSupported         = errors.New("dbr: not supported")
-	ErrTableNotSpecified    = errors.New("dbr: table not specified")
-	ErrColumnNotSpecified   = errors.New("dbr: column not specified")
-	ErrInvalidPointer       = errors.New("dbr: attempt to load into an invalid pointer")
-	ErrPlaceholderCount     = errors.New("dbr: wrong placeholder count")
-	ErrInvalidSliceLength   = errors.New("dbr: length of slice is 0. length must be >= 1")
-	ErrCantConvertToTime    = errors.New("dbr: can't convert to time.Time")
-	ErrInvalidTimestring    = errors.New("dbr: invalid time string")
-	ErrPrewhereNotSupported = errors.New("dbr: PREWHERE statement is not supported")
+	ErrNotFound                  = errors.New("dbr: not found")
+	ErrNotSupported              = errors.New("dbr: not supported")
+	ErrTableNotSpecified         = errors.New("dbr: table not specified")
+	ErrColumnNotSpecified        = errors.New("dbr: column not specified")
+	ErrInvalidPointer            = errors.New("dbr: attempt to load into an invalid pointer")
+	ErrPlaceholderCount          = errors.New("dbr: wrong placeholder count")
+	ErrInvalidSliceLength        = errors.New("dbr: length of slice is 0. length must be >= 1")
+	ErrCantConvertToTime         = errors.New("dbr: can't convert to time.Time")
+	ErrInvalidTimestring         = errors.New("dbr: invalid time string")
+	ErrPrewhereNotSupported      = errors.New("dbr: PREWHERE statement is not supported")
+	ErrDeleteLimitNotSupported   = errors.New("dbr: ORDER BY and LIMIT are not supported in DELETE statement")
+	ErrReturningNotSupported     = errors.New("dbr: RETURNING is not supported")
+	ErrTableFunctionNotSupported = errors.New("dbr: table function is not supported")
+	ErrReadOnlySession           = errors.New("dbr: read-only session")
+	ErrRouteNotFound             = errors.New("dbr: route not found")
+	ErrExplainNotSupported       = errors.New("dbr: EXPLAIN is not supported")
+	ErrMixedPlaceholders         = errors.New("dbr: plain and indexed placeholders can not be mixed")
+	ErrAsyncInsertNotSupported   = errors.New("dbr: async insert is not supported")
+	ErrMergeNotSupported         = errors.New("dbr: MERGE statement is not supported")
+	ErrInsertedUndetermined      = errors.New("dbr: can not determine whether the row was inserted or updated")
+	ErrIndexHintNotSupported     = errors.New("dbr: index hints are not supported")
+	ErrKeyLockNotSupported       = errors.New("dbr: FOR NO KEY UPDATE and FOR KEY SHARE are not supported")
+	ErrRawBytes                  = errors.New("dbr: sql.RawBytes can be loaded only by LoadEach")
+	ErrStringAggNotSupported     = errors.New("dbr: string aggregation is not supported")
+	ErrInvalidCursor             = errors.New("dbr: invalid cursor")
+	ErrDestinationCount          = errors.New("dbr: number of queries and destinations differ")
+	ErrDateTruncUnit             = errors.New("dbr: unsupported date truncation unit")
+	ErrDuplicateKey              = errors.New("dbr: duplicate key")
+	ErrForeignKeyViolation       = errors.New("dbr: foreign key violation")
+	ErrNotNullViolation          = errors.New("dbr: not null violation")
+	ErrRowLocked                 = errors.New("dbr: row is locked")
+	ErrValuesTableNotSupported   = errors.New("dbr: VALUES table is not supported")
+	ErrWithTotalsNotSupported    = errors.New("dbr: WITH TOTALS is not supported")
+	ErrSavepointNotSupported     = errors.New("dbr: savepoints are not supported")
+	ErrTableSampleNotSupported   = errors.New("dbr: TABLESAMPLE is not supported")
+	ErrTableSampleMethod         = errors.New("dbr: TABLESAMPLE method must be SYSTEM or BERNOULLI")
+	ErrTableSamplePercent        = errors.New("dbr: TABLESAMPLE percentage must be in (0, 100]")
+	ErrPlanHintNotSupported      = errors.New("dbr: plan hints are not supported")
+	ErrDefaultNotSupported       = errors.New("dbr: DEFAULT value is not supported")
+	ErrLateralJoinNotSupported   = errors.New("dbr: LATERAL join is not supported")
+	ErrDistinctOnNotSupported    = errors.New("dbr: DISTINCT ON is not supported")
+	ErrCompositeNotSupported     = errors.New("dbr: composite types are not supported")
+	ErrOnlyNotSupported          = errors.New("dbr: ONLY is not supported")
+	ErrTempTableNotSupported     = errors.New("dbr: temporary tables are not supported")
+	ErrDoNothingNotSupported     = errors.New("dbr: ignoring conflicts of insert is not supported")
+	ErrDoNothingWithConflict     = errors.New("dbr: OnConflictDoNothing can not be combined with OnConflict")
+	ErrStmtTimeoutNotSupported   = errors.New("dbr: statement timeout is not supported")
+	ErrNoConditions              = errors.New("dbr: no conditions")
 )
@@ -20,8 +20,52 @@ type TracingEventReceiver interface {
 	SpanFinish(ctx context.Context)
 }
 
+// ArgsEventReceiver is an optional interface an EventReceiver type can implement
+// to receive the query and raw arguments of failed queries, e.g. for structured logging.
+// The query has placeholders of the dialect for all args, kvs are the same as of EventErrKv, including
+// the executed query as "sql". If implemented, it is called instead of EventErrKv for query errors.
+type ArgsEventReceiver interface {
+	EventErrArgs(eventName string, err error, query string, args []interface{}, kvs map[string]string) error
+}
+
+// eventErr sends query error either to ArgsEventReceiver, if implemented, or to EventErrKv.
+// The builder is built again with placeholders for ArgsEventReceiver, as interpolated values are not kept,
+// subqueries and expressions of values are flattened like in Stats
+func eventErr(log EventReceiver, eventName string, err error, builder Builder, d Dialect, kvs kvs) error {
+	if argsImpl, ok := log.(ArgsEventReceiver); ok {
+		query, args := kvs["sql"], []interface{}(nil)
+		i := interpolator{
+			Buffer:          NewBuffer(),
+			Dialect:         d,
+			IgnoreBinary:    true,
+			UsePlaceholders: true,
+		}
+		if i.interpolate(placeholder, []interface{}{builder}) == nil {
+			query, args = i.String(), i.Value()
+		}
+		return argsImpl.EventErrArgs(eventName, err, query, args, kvs)
+	}
+	return log.EventErrKv(eventName, err, kvs)
+}
+
 type kvs map[string]string
 
+// merge returns a new kvs with extra pairs added to k,
+// pairs already present in k are never overwritten by extra
+func (k kvs) merge(extra kvs) kvs {
+	if len(extra) == 0 {
+		return k
+	}
+	m := make(kvs, len(k)+len(extra))
+	for key, value := range extra {
+		m[key] = value
+	}
+	for key, value := range k {
+		m[key] = value
+	}
+	return m
+}
+
 var nullReceiver = &NullEventReceiver{}
 
 // NullEventReceiver is a sentinel EventReceiver; use it if the caller doesn't supply one
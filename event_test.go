@@ -0,0 +1,124 @@
+package dbr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mailru/dbr/dialect"
+	"github.com/stretchr/testify/assert"
+)
+
+type testEvent struct {
+	name string
+	err  error
+	kvs  map[string]string
+}
+
+// testEventReceiver records all events with key/value data
+type testEventReceiver struct {
+	NullEventReceiver
+	events []testEvent
+}
+
+func (r *testEventReceiver) EventKv(eventName string, kvs map[string]string) {
+	r.events = append(r.events, testEvent{name: eventName, kvs: kvs})
+}
+
+func (r *testEventReceiver) EventErrKv(eventName string, err error, kvs map[string]string) error {
+	r.events = append(r.events, testEvent{name: eventName, err: err, kvs: kvs})
+	return err
+}
+
+func (r *testEventReceiver) TimingKv(eventName string, nanoseconds int64, kvs map[string]string) {
+	r.events = append(r.events, testEvent{name: eventName, kvs: kvs})
+}
+
+func newRecordingSessionMock() (*Session, sqlmock.Sqlmock, *testEventReceiver) {
+	db, m, err := sqlmock.New()
+	if err != nil {
+		panic(err)
+	}
+	recv := &testEventReceiver{}
+	conn := Connection{DB: db, Dialect: dialect.MySQL, EventReceiver: recv}
+	return conn.NewSession(nil), m, recv
+}
+
+func TestKvsMerge(t *testing.T) {
+	base := kvs{"sql": "SELECT 1"}
+	assert.Equal(t, base, base.merge(nil))
+	assert.Equal(t, kvs{"sql": "SELECT 1", "feature": "search"}, base.merge(kvs{"feature": "search", "sql": "DROP TABLE t"}))
+	// the receiver must not be modified
+	assert.Equal(t, kvs{"sql": "SELECT 1"}, base)
+}
+
+func TestWithEventKv(t *testing.T) {
+	sess, dbmock, recv := newRecordingSessionMock()
+	dbmock.ExpectQuery("SELECT a FROM table").WillReturnRows(sqlmock.NewRows([]string{"a"}).AddRow(1))
+	dbmock.ExpectExec("DELETE FROM `table`").WillReturnError(errors.New("boom"))
+
+	var a int
+	_, err := sess.Select("a").From("table").
+		WithEventKv("feature", "search").
+		WithEventKv("sql", "overwritten").
+		Load(&a)
+	assert.NoError(t, err)
+
+	_, err = sess.DeleteFrom("table").WithEventKv("feature", "cleanup").Exec()
+	assert.EqualError(t, err, "boom")
+
+	assert.Equal(t, []testEvent{
+		{name: "dbr.select", kvs: map[string]string{"sql": "SELECT a FROM table", "feature": "search"}},
+		{name: "dbr.exec.exec", err: err, kvs: map[string]string{"sql": "DELETE FROM `table`", "feature": "cleanup"}},
+		{name: "dbr.exec", kvs: map[string]string{"sql": "DELETE FROM `table`", "feature": "cleanup"}},
+	}, recv.events)
+}
+
+type testArgsEventReceiver struct {
+	testEventReceiver
+	query string
+	args  []interface{}
+}
+
+func (r *testArgsEventReceiver) EventErrArgs(eventName string, err error, query string, args []interface{}, kvs map[string]string) error {
+	r.events = append(r.events, testEvent{name: eventName, err: err, kvs: kvs})
+	r.query = query
+	r.args = args
+	return err
+}
+
+func TestEventErrArgs(t *testing.T) {
+	db, dbmock, err := sqlmock.New()
+	assert.NoError(t, err)
+	recv := &testArgsEventReceiver{}
+	conn := Connection{DB: db, Dialect: dialect.MySQL, EventReceiver: recv}
+	sess := conn.NewSession(nil)
+
+	dbmock.ExpectExec("INSERT INTO `table`").WillReturnError(errors.New("boom"))
+	_, err = sess.InsertInto("table").Columns("a", "b").Values(1, []byte("raw")).WithEventKv("feature", "import").Exec()
+	assert.EqualError(t, err, "boom")
+	assert.Equal(t, "INSERT INTO `table` (`a`,`b`) VALUES (?,?)", recv.query)
+	assert.Equal(t, []interface{}{1, []byte("raw")}, recv.args)
+	kvs := map[string]string{"sql": "INSERT INTO `table` (`a`,`b`) VALUES (1,?)", "feature": "import"}
+	assert.Equal(t, []testEvent{
+		{name: "dbr.exec.exec", err: err, kvs: kvs},
+		{name: "dbr.exec", kvs: kvs},
+	}, recv.events)
+
+	// subqueries and expressions of values are flattened into args
+	dbmock.ExpectQuery("SELECT id FROM t").WillReturnError(errors.New("boom"))
+	var id []int64
+	_, err = sess.Select("id").From("t").
+		Where(Eq("org_id", Select("id").From("orgs").Where(Eq("name", "x")))).
+		Where("created_at > ?", Expr("NOW() - ?", 5)).
+		Load(&id)
+	assert.EqualError(t, err, "boom")
+	assert.Equal(t, "SELECT id FROM t WHERE (`org_id` = (SELECT id FROM orgs WHERE (`name` = ?))) AND (created_at > NOW() - ?)", recv.query)
+	assert.Equal(t, []interface{}{"x", 5}, recv.args)
+
+	// receivers without EventErrArgs still get formatted args
+	sess, _, plain := newRecordingSessionMock()
+	_, err = sess.InsertInto("table").Columns("a").Values(1, 2).Exec()
+	assert.Equal(t, ErrPlaceholderCount, err)
+	assert.Equal(t, "[]", plain.events[0].kvs["args"])
+}
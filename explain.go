@@ -0,0 +1,84 @@
+package dbr
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// explain builds `EXPLAIN stmt` with dialect syntax
+type explain struct {
+	keyword string
+	stmt    Builder
+}
+
+func (b *explain) Build(d Dialect, buf Buffer) error {
+	buf.WriteString(b.keyword)
+	buf.WriteString(" ")
+	return b.stmt.Build(d, buf)
+}
+
+// planRow is a row of query plan with columns scanned as strings
+type planRow []string
+
+var typePlanRow = reflect.TypeOf(planRow(nil))
+
+type planScanner struct {
+	dest *string
+}
+
+func (s planScanner) Scan(v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		*s.dest = "NULL"
+	case []byte:
+		*s.dest = string(v)
+	default:
+		*s.dest = fmt.Sprint(v)
+	}
+	return nil
+}
+
+func planRowExtractor(columns []string, value reflect.Value) []interface{} {
+	row := make(planRow, len(columns))
+	value.Set(reflect.ValueOf(row))
+	ptr := make([]interface{}, len(columns))
+	for i := range row {
+		ptr[i] = planScanner{dest: &row[i]}
+	}
+	return ptr
+}
+
+func (b *selectBuilder) explain(ctx context.Context, analyze bool) ([]string, error) {
+	keyword := b.Dialect.Explain(analyze)
+	if keyword == "" {
+		return nil, ErrExplainNotSupported
+	}
+	if len(b.inTemp) > 0 {
+		// the plan would be of the query without conditions of WhereInTemp
+		return nil, ErrTempTableNotSupported
+	}
+	var rows []planRow
+	_, err := query(ctx, b.runner, b.EventReceiver, &explain{keyword: keyword, stmt: b}, b.Dialect, b.eventKvs, &rows)
+	if err != nil {
+		return nil, err
+	}
+	plan := make([]string, len(rows))
+	for i, row := range rows {
+		plan[i] = strings.Join(row, "\t")
+	}
+	return plan, nil
+}
+
+// Explain returns query plan, one string per row of EXPLAIN with columns separated by tab.
+// PostgreSQL plan is JSON (`EXPLAIN (FORMAT JSON)`), other dialects return their EXPLAIN rows as is
+func (b *selectBuilder) Explain(ctx context.Context) ([]string, error) {
+	return b.explain(ctx, false)
+}
+
+// ExplainAnalyze executes query and returns query plan with actual timings (`EXPLAIN ANALYZE`),
+// it is supported only by PostgreSQL
+func (b *selectBuilder) ExplainAnalyze(ctx context.Context) ([]string, error) {
+	return b.explain(ctx, true)
+}
@@ -0,0 +1,50 @@
+package dbr
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mailru/dbr/dialect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplainMock(t *testing.T) {
+	sess, dbmock, _ := newRecordingSessionMock()
+	dbmock.ExpectQuery(regexp.QuoteMeta("EXPLAIN SELECT id FROM dbr_people WHERE (`id` = 1)")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "select_type", "table", "key"}).AddRow(1, "SIMPLE", "dbr_people", nil))
+
+	plan, err := sess.Select("id").From("dbr_people").Where(Eq("id", 1)).Explain(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1\tSIMPLE\tdbr_people\tNULL"}, plan)
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+
+	_, err = sess.Select("id").From("dbr_people").ExplainAnalyze(context.Background())
+	assert.Equal(t, ErrExplainNotSupported, err)
+
+	db, dbmock, err := sqlmock.New()
+	assert.NoError(t, err)
+	conn := &Connection{DB: db, Dialect: dialect.PostgreSQL, EventReceiver: nullReceiver}
+	dbmock.ExpectQuery(regexp.QuoteMeta(`EXPLAIN (ANALYZE, FORMAT JSON) SELECT id FROM dbr_people`)).
+		WillReturnRows(sqlmock.NewRows([]string{"QUERY PLAN"}).AddRow([]byte(`[{"Plan": {}}]`)))
+	plan, err = conn.NewSession(nil).Select("id").From("dbr_people").ExplainAnalyze(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`[{"Plan": {}}]`}, plan)
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+}
+
+func TestExplain(t *testing.T) {
+	for _, sess := range testSession {
+		plan, err := sess.Select("id").From("dbr_people").Where(Eq("id", 1)).Explain(context.Background())
+		assert.NoError(t, err)
+		assert.NotEmpty(t, plan)
+
+		_, err = sess.Select("id").From("dbr_people").ExplainAnalyze(context.Background())
+		if sess.Dialect == dialect.PostgreSQL {
+			assert.NoError(t, err)
+		} else {
+			assert.Equal(t, ErrExplainNotSupported, err)
+		}
+	}
+}
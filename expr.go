@@ -1,5 +1,10 @@
 package dbr
 
+import (
+	"fmt"
+	"strconv"
+)
+
 type raw struct {
 	Query string
 	Value []interface{}
@@ -10,8 +15,90 @@ func Expr(query string, value ...interface{}) Builder {
 	return &raw{Query: query, Value: value}
 }
 
-func (raw *raw) Build(_ Dialect, buf Buffer) error {
-	buf.WriteString(raw.Query)
-	buf.WriteValue(raw.Value...)
+// Raw is a trusted SQL fragment, which is written verbatim where a value is expected, e.g.
+// Where("updated_at > ?", Raw("now() - interval 1 day")).
+// It is not quoted or escaped, so it must never contain user input: that is SQL injection
+type Raw string
+
+// PlaceholderCountError is returned by Expr and raw queries if the number of placeholders
+// differs from the number of values. It is not equal to ErrPlaceholderCount, but errors.Is matches it since Go 1.13
+type PlaceholderCountError struct {
+	Query        string
+	Placeholders int
+	Values       int
+}
+
+func (e *PlaceholderCountError) Error() string {
+	return fmt.Sprintf("dbr: %d placeholders, but %d values in %q", e.Placeholders, e.Values, e.Query)
+}
+
+// Is reports whether target is ErrPlaceholderCount
+func (e *PlaceholderCountError) Is(target error) bool {
+	return target == ErrPlaceholderCount
+}
+
+func (raw *raw) Build(d Dialect, buf Buffer) error {
+	query, value, err := resolveIndexedPlaceholders(raw.Query, raw.Value, d)
+	if err != nil {
+		return err
+	}
+	if n := countPlaceholders(query, d); n != len(value) {
+		return &PlaceholderCountError{Query: raw.Query, Placeholders: n, Values: len(value)}
+	}
+	buf.WriteString(query)
+	buf.WriteValue(value...)
 	return nil
 }
+
+// resolveIndexedPlaceholders replaces `?1`, `?2`, ... with plain placeholders
+// and repeats values they reference by position, so a value can be used several times.
+// Plain and indexed placeholders can not be mixed, and every value must be referenced.
+// Placeholders in quoted literals and comments are skipped
+func resolveIndexedPlaceholders(query string, value []interface{}, d Dialect) (string, []interface{}, error) {
+	var (
+		buf      []byte
+		resolved []interface{}
+		used     = make([]bool, len(value))
+		plain    bool
+		indexed  bool
+		last     int
+	)
+	for i := 0; i < len(query); i++ {
+		index := placeholderIndex(query[i:], d)
+		if index == -1 {
+			break
+		}
+		i += index
+		j := i + 1
+		for j < len(query) && query[j] >= '0' && query[j] <= '9' {
+			j++
+		}
+		if j == i+1 {
+			plain = true
+			continue
+		}
+		indexed = true
+		n, err := strconv.Atoi(query[i+1 : j])
+		if err != nil || n < 1 || n > len(value) {
+			return "", nil, ErrPlaceholderCount
+		}
+		buf = append(buf, query[last:i+1]...)
+		resolved = append(resolved, value[n-1])
+		used[n-1] = true
+		last = j
+		i = j - 1
+	}
+	if !indexed {
+		return query, value, nil
+	}
+	if plain {
+		return "", nil, ErrMixedPlaceholders
+	}
+	for _, ok := range used {
+		if !ok {
+			return "", nil, ErrPlaceholderCount
+		}
+	}
+	buf = append(buf, query[last:]...)
+	return string(buf), resolved, nil
+}
@@ -0,0 +1,149 @@
+package dbr
+
+import (
+	"testing"
+
+	"github.com/mailru/dbr/dialect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexedPlaceholders(t *testing.T) {
+	for _, test := range []struct {
+		query string
+		value []interface{}
+		want  string
+		err   error
+	}{
+		{
+			query: "SELECT * FROM t WHERE a = ?1 OR b = ?1 AND c > ?2",
+			value: []interface{}{"x", 5},
+			want:  "SELECT * FROM t WHERE a = 'x' OR b = 'x' AND c > 5",
+		},
+		{
+			query: "SELECT ?2, ?1",
+			value: []interface{}{1, 2},
+			want:  "SELECT 2, 1",
+		},
+		{
+			query: "SELECT ?, ?",
+			value: []interface{}{1, 2},
+			want:  "SELECT 1, 2",
+		},
+		{
+			query: "SELECT ?1, ?",
+			value: []interface{}{1, 2},
+			err:   ErrMixedPlaceholders,
+		},
+		{
+			query: "SELECT ?3",
+			value: []interface{}{1, 2, 3},
+			err:   ErrPlaceholderCount,
+		},
+		{
+			query: "SELECT ?0",
+			value: []interface{}{1},
+			err:   ErrPlaceholderCount,
+		},
+		{
+			query: "SELECT ?2",
+			value: []interface{}{1},
+			err:   ErrPlaceholderCount,
+		},
+	} {
+		buf := NewBuffer()
+		err := SelectBySql(test.query, test.value...).Build(dialect.MySQL, buf)
+		assert.Equal(t, test.err, err, test.query)
+		if err != nil {
+			continue
+		}
+		query, err := InterpolateForDialect(buf.String(), buf.Value(), dialect.MySQL)
+		assert.NoError(t, err)
+		assert.Equal(t, test.want, query)
+	}
+}
+
+func TestIndexedPlaceholdersBinary(t *testing.T) {
+	// values which are not interpolated are repeated for driver placeholders
+	buf := NewBuffer()
+	err := Expr("a = ?1 OR b = ?1", []byte{1}).Build(dialect.PostgreSQL, buf)
+	assert.NoError(t, err)
+	i := interpolator{
+		Buffer:       NewBuffer(),
+		Dialect:      dialect.PostgreSQL,
+		IgnoreBinary: true,
+	}
+	assert.NoError(t, i.interpolate(buf.String(), buf.Value()))
+	assert.Equal(t, "a = $1 OR b = $2", i.String())
+	assert.Equal(t, []interface{}{[]byte{1}, []byte{1}}, i.Value())
+}
+
+func TestExprPlaceholderCount(t *testing.T) {
+	for _, test := range []struct {
+		query string
+		value []interface{}
+		want  string
+		err   error
+	}{
+		{
+			query: "a = ? AND b = ?",
+			value: []interface{}{1},
+			err:   &PlaceholderCountError{Query: "a = ? AND b = ?", Placeholders: 2, Values: 1},
+		},
+		{
+			query: "a = ?",
+			value: []interface{}{1, 2},
+			err:   &PlaceholderCountError{Query: "a = ?", Placeholders: 1, Values: 2},
+		},
+		{
+			query: "a = 'why?' AND b = ?",
+			value: []interface{}{1},
+			want:  "a = 'why?' AND b = 1",
+		},
+		{
+			query: "a = 'it''s ?' AND `b?` = ?1 OR c = ?1",
+			value: []interface{}{"x"},
+			want:  "a = 'it''s ?' AND `b?` = 'x' OR c = 'x'",
+		},
+		{
+			query: "a = '?'",
+			value: []interface{}{1},
+			err:   &PlaceholderCountError{Query: "a = '?'", Placeholders: 0, Values: 1},
+		},
+		{
+			query: "a = 'no placeholders' AND b = ?",
+			value: []interface{}{"'?'"},
+			want:  "a = 'no placeholders' AND b = '\\'?\\''",
+		},
+	} {
+		buf := NewBuffer()
+		err := Expr(test.query, test.value...).Build(dialect.MySQL, buf)
+		assert.Equal(t, test.err, err, test.query)
+		if err != nil {
+			assert.True(t, err.(*PlaceholderCountError).Is(ErrPlaceholderCount))
+			continue
+		}
+		query, err := InterpolateForDialect(buf.String(), buf.Value(), dialect.MySQL)
+		assert.NoError(t, err)
+		assert.Equal(t, test.want, query)
+	}
+
+	// conditions are validated when the statement is built
+	err := Select("a").From("t").Where("a = ? AND b = ?", 1).Build(dialect.MySQL, NewBuffer())
+	assert.Equal(t, &PlaceholderCountError{Query: "a = ? AND b = ?", Placeholders: 2, Values: 1}, err)
+	assert.EqualError(t, err, `dbr: 2 placeholders, but 1 values in "a = ? AND b = ?"`)
+}
+
+func TestRaw(t *testing.T) {
+	for _, d := range []Dialect{dialect.MySQL, dialect.PostgreSQL} {
+		buf := NewBuffer()
+		err := Select("a").From("t").
+			Where("updated_at > ?", Raw("now() - interval 1 day")).
+			Where(Eq("b", Raw("c + 1"))).
+			Where("d = ?", Raw("'?'")).
+			Build(d, buf)
+		assert.NoError(t, err)
+		query, err := InterpolateForDialect(buf.String(), buf.Value(), d)
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT a FROM t WHERE (updated_at > now() - interval 1 day) AND ("+d.QuoteIdent("b")+" = c + 1) AND (d = '?')", query)
+	}
+}
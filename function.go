@@ -0,0 +1,154 @@
+package dbr
+
+import (
+	"fmt"
+	"reflect"
+)
+
+type function struct {
+	name  string
+	value []interface{}
+	alias string
+}
+
+// Func builds a function call with interpolated arguments, e.g. `generate_series(?, ?)`.
+// It can be used in FROM as a table function for PostgreSQL and ClickHouse:
+//
+//	Select("*").From(Func("generate_series", 1, 10).As("g"))
+func Func(name string, value ...interface{}) interface {
+	Builder
+	As(string) Builder
+} {
+	return &function{
+		name:  name,
+		value: value,
+	}
+}
+
+func (f *function) Build(d Dialect, buf Buffer) error {
+	buf.WriteString(f.name)
+	buf.WriteString("(")
+	for i := range f.value {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(placeholder)
+	}
+	buf.WriteString(")")
+	buf.WriteValue(f.value...)
+
+	if f.alias != "" {
+		buf.WriteString(" AS ")
+		buf.WriteString(d.QuoteIdent(f.alias))
+	}
+	return nil
+}
+
+// As creates alias for function call
+func (f *function) As(alias string) Builder {
+	return &function{
+		name:  f.name,
+		value: f.value,
+		alias: alias,
+	}
+}
+
+type dateTrunc struct {
+	unit  string
+	expr  interface{}
+	alias string
+}
+
+// Collate builds `expr COLLATE collation`, e.g. for case-insensitive ordering and comparison.
+// Collations are of the dialect, e.g. "utf8mb4_unicode_ci" in MySQL, "und-x-icu" in PostgreSQL and "NOCASE"
+// in SQLite3. ClickHouse supports it only in ORDER BY with a locale, e.g. "en". String expr is written as is
+func Collate(expr interface{}, collation string) Builder {
+	return BuildFunc(func(d Dialect, buf Buffer) error {
+		buf.WriteString(placeholder)
+		if s, ok := expr.(string); ok {
+			buf.WriteValue(Raw(s))
+		} else {
+			buf.WriteValue(expr)
+		}
+		buf.WriteString(" ")
+		buf.WriteString(d.Collate(collation))
+		return nil
+	})
+}
+
+// DateTrunc truncates time of expr to the start of unit, which is "hour", "day" or "month", e.g. for time series.
+// It is `date_trunc('hour', expr)` in PostgreSQL, `toStartOfHour(expr)` in ClickHouse, `DATE_FORMAT` in MySQL
+// and `strftime` in SQLite3, the last two return strings. Other units return ErrDateTruncUnit
+func DateTrunc(unit string, expr interface{}) interface {
+	Builder
+	As(string) Builder
+} {
+	return &dateTrunc{
+		unit: unit,
+		expr: expr,
+	}
+}
+
+func (f *dateTrunc) Build(d Dialect, buf Buffer) error {
+	template := d.DateTrunc(f.unit)
+	if len(template) == 0 {
+		return ErrDateTruncUnit
+	}
+	// template has a placeholder for expr
+	buf.WriteString(template)
+	switch expr := f.expr.(type) {
+	case string:
+		buf.WriteValue(Raw(expr))
+	default:
+		buf.WriteValue(expr)
+	}
+
+	if f.alias != "" {
+		buf.WriteString(" AS ")
+		buf.WriteString(d.QuoteIdent(f.alias))
+	}
+	return nil
+}
+
+// As creates alias for truncated time
+func (f *dateTrunc) As(alias string) Builder {
+	return &dateTrunc{
+		unit:  f.unit,
+		expr:  f.expr,
+		alias: alias,
+	}
+}
+
+// Composite builds a value of PostgreSQL composite type from fields of struct, e.g. `ROW(?, ?)::"point"`,
+// e.g. to pass it to a function. Fields are in declaration order, like columns of Record.
+// Build fails if structValue is not a struct, other dialects return ErrCompositeNotSupported
+func Composite(typeName string, structValue interface{}) Builder {
+	v := reflect.Indirect(reflect.ValueOf(structValue))
+	if v.Kind() != reflect.Struct {
+		return BuildFunc(func(Dialect, Buffer) error {
+			return fmt.Errorf("dbr: value of Composite must be a struct, not %T", structValue)
+		})
+	}
+	m := structMap(v.Type())
+	column := structColumns(v.Type())
+	value := make([]interface{}, len(column))
+	for i, col := range column {
+		value[i] = v.FieldByIndex(m[col]).Interface()
+	}
+	return BuildFunc(func(d Dialect, buf Buffer) error {
+		if !d.SupportsCompositeType() {
+			return ErrCompositeNotSupported
+		}
+		buf.WriteString("ROW(")
+		for i := range value {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(placeholder)
+		}
+		buf.WriteString(")::")
+		buf.WriteString(d.QuoteIdent(typeName))
+		buf.WriteValue(value...)
+		return nil
+	})
+}
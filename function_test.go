@@ -0,0 +1,163 @@
+package dbr
+
+import (
+	"testing"
+
+	"github.com/mailru/dbr/dialect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTableFunction(t *testing.T) {
+	for _, test := range []struct {
+		stmt  SelectStmt
+		d     Dialect
+		query string
+	}{
+		{
+			stmt:  Select("g").From(Func("generate_series", 1, 10).As("g")).Where(Gt("g", 5)),
+			d:     dialect.PostgreSQL,
+			query: `SELECT g FROM generate_series(1, 10) AS "g" WHERE ("g" > 5)`,
+		},
+		{
+			stmt:  Select("number").From(Func("numbers", 10)),
+			d:     dialect.ClickHouse,
+			query: "SELECT number FROM numbers(10)",
+		},
+		{
+			stmt:  Select(Func("lower", "A").As("a")),
+			d:     dialect.MySQL,
+			query: "SELECT lower('A') AS `a`",
+		},
+	} {
+		buf := NewBuffer()
+		err := test.stmt.Build(test.d, buf)
+		assert.NoError(t, err)
+		query, err := InterpolateForDialect(buf.String(), buf.Value(), test.d)
+		assert.NoError(t, err)
+		assert.Equal(t, test.query, query)
+	}
+
+	for _, d := range []Dialect{dialect.MySQL, dialect.SQLite3} {
+		err := Select("*").From(Func("generate_series", 1, 10)).Build(d, NewBuffer())
+		assert.Equal(t, ErrTableFunctionNotSupported, err)
+	}
+}
+
+func TestComposite(t *testing.T) {
+	type point struct {
+		X    float64
+		Y    float64
+		Name string `db:"-"`
+	}
+	buf := NewBuffer()
+	err := Select(Func("distance", Composite("geo.point", &point{X: 1.5, Y: -2}), Composite("geo.point", point{}))).Build(dialect.PostgreSQL, buf)
+	assert.NoError(t, err)
+	query, err := InterpolateForDialect(buf.String(), buf.Value(), dialect.PostgreSQL)
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT distance(ROW(1.5, -2)::"geo"."point", ROW(0, 0)::"geo"."point")`, query)
+
+	for _, d := range []Dialect{dialect.MySQL, dialect.SQLite3, dialect.ClickHouse} {
+		err := Composite("point", point{}).Build(d, NewBuffer())
+		assert.Equal(t, ErrCompositeNotSupported, err)
+	}
+	err = Composite("point", 1).Build(dialect.PostgreSQL, NewBuffer())
+	assert.EqualError(t, err, "dbr: value of Composite must be a struct, not int")
+	_, err = InterpolateForDialect("SELECT ?", []interface{}{Composite("point", (*point)(nil))}, dialect.PostgreSQL)
+	assert.EqualError(t, err, "dbr: value of Composite must be a struct, not *dbr.point")
+}
+
+func TestCollate(t *testing.T) {
+	for _, test := range []struct {
+		d         Dialect
+		collation string
+		query     string
+	}{
+		{
+			d:         dialect.MySQL,
+			collation: "utf8mb4_unicode_ci",
+			query:     "SELECT id FROM users WHERE (name COLLATE `utf8mb4_unicode_ci` = 'bob') ORDER BY name COLLATE `utf8mb4_unicode_ci`",
+		},
+		{
+			d:         dialect.MySQL,
+			collation: "a.b`c",
+			query:     "SELECT id FROM users WHERE (name COLLATE `a.b``c` = 'bob') ORDER BY name COLLATE `a.b``c`",
+		},
+		{
+			d:         dialect.PostgreSQL,
+			collation: "und-x-icu",
+			query:     `SELECT id FROM users WHERE (name COLLATE "und-x-icu" = 'bob') ORDER BY name COLLATE "und-x-icu"`,
+		},
+		{
+			d:         dialect.PostgreSQL,
+			collation: "en_US.utf8",
+			query:     `SELECT id FROM users WHERE (name COLLATE "en_US.utf8" = 'bob') ORDER BY name COLLATE "en_US.utf8"`,
+		},
+		{
+			d:         dialect.SQLite3,
+			collation: "NOCASE",
+			query:     `SELECT id FROM users WHERE (name COLLATE "NOCASE" = 'bob') ORDER BY name COLLATE "NOCASE"`,
+		},
+		{
+			d:         dialect.ClickHouse,
+			collation: "en",
+			query:     "SELECT id FROM users WHERE (name COLLATE 'en' = 'bob') ORDER BY name COLLATE 'en'",
+		},
+	} {
+		buf := NewBuffer()
+		err := Select("id").From("users").
+			Where("? = ?", Collate("name", test.collation), "bob").
+			OrderBy(Collate("name", test.collation)).
+			Build(test.d, buf)
+		assert.NoError(t, err)
+		query, err := InterpolateForDialect(buf.String(), buf.Value(), test.d)
+		assert.NoError(t, err)
+		assert.Equal(t, test.query, query)
+	}
+
+	query, err := InterpolateForDialect("?", []interface{}{Collate(I("u.name"), "C")}, dialect.PostgreSQL)
+	assert.NoError(t, err)
+	assert.Equal(t, `"u"."name" COLLATE "C"`, query)
+}
+
+func TestDateTrunc(t *testing.T) {
+	for _, test := range []struct {
+		d     Dialect
+		unit  string
+		query string
+	}{
+		{
+			d:     dialect.PostgreSQL,
+			unit:  "hour",
+			query: `SELECT date_trunc('hour', created_at) AS "bucket", count(*) FROM events GROUP BY bucket`,
+		},
+		{
+			d:     dialect.MySQL,
+			unit:  "hour",
+			query: "SELECT DATE_FORMAT(created_at, '%Y-%m-%d %H:00:00') AS `bucket`, count(*) FROM events GROUP BY bucket",
+		},
+		{
+			d:     dialect.SQLite3,
+			unit:  "day",
+			query: `SELECT strftime('%Y-%m-%d', created_at) AS "bucket", count(*) FROM events GROUP BY bucket`,
+		},
+		{
+			d:     dialect.ClickHouse,
+			unit:  "month",
+			query: "SELECT toStartOfMonth(created_at) AS `bucket`, count(*) FROM events GROUP BY bucket",
+		},
+	} {
+		buf := NewBuffer()
+		err := Select(DateTrunc(test.unit, "created_at").As("bucket"), "count(*)").From("events").GroupBy("bucket").Build(test.d, buf)
+		assert.NoError(t, err)
+		query, err := InterpolateForDialect(buf.String(), buf.Value(), test.d)
+		assert.NoError(t, err)
+		assert.Equal(t, test.query, query)
+	}
+
+	query, err := InterpolateForDialect("?", []interface{}{DateTrunc("day", I("e.created_at"))}, dialect.PostgreSQL)
+	assert.NoError(t, err)
+	assert.Equal(t, `date_trunc('day', "e"."created_at")`, query)
+
+	err = DateTrunc("week", "created_at").Build(dialect.PostgreSQL, NewBuffer())
+	assert.Equal(t, ErrDateTruncUnit, err)
+}
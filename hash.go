@@ -0,0 +1,119 @@
+package dbr
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// LoadWithHash loads value like LoadContext and returns a SHA-256 hash of the loaded rows in hex, e.g. for an ETag.
+// Values are hashed with their kinds, as they are encoded by EncodeCursor, columns of each row in order of their names,
+// so the hash does not depend on the order of struct fields or selected columns, but it depends on the order of rows,
+// which should be fixed by ORDER BY. Structs are hashed by all their fields, fields of columns which are not selected
+// are hashed as zero values. The hash is the same for identical data, but it is not stable across versions of dbr
+func (b *selectBuilder) LoadWithHash(ctx context.Context, value interface{}) (string, int, error) {
+	count, err := b.LoadContext(ctx, value)
+	if err != nil {
+		return "", count, err
+	}
+	h := sha256.New()
+	if err := hashRows(h, reflect.ValueOf(value).Elem()); err != nil {
+		return "", count, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), count, nil
+}
+
+// hashRows writes rows of v, which is a slice of rows or a single row, into h
+func hashRows(h hash.Hash, v reflect.Value) error {
+	if v.Kind() != reflect.Slice || v.Type().Elem().Kind() == reflect.Uint8 {
+		return hashRow(h, v)
+	}
+	for i := 0; i < v.Len(); i++ {
+		if err := hashRow(h, v.Index(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hashRow writes a row into h, a struct or a map is written as sorted pairs
+// of column names and values, other values are written as a single column
+func hashRow(h hash.Hash, v reflect.Value) error {
+	h.Write([]byte{'r'})
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			// a row with all columns NULL loaded as nil, see NullRowsAsNil
+			return hashValue(h, nil)
+		}
+		v = v.Elem()
+	}
+	switch {
+	case v.Kind() == reflect.Map:
+		key := make([]string, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			key = append(key, k.String())
+		}
+		sort.Strings(key)
+		for _, k := range key {
+			hashString(h, k)
+			if err := hashValue(h, v.MapIndex(reflect.ValueOf(k).Convert(v.Type().Key())).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	case v.Kind() == reflect.Struct && !isValueStruct(v):
+		mapping := structMap(v.Type())
+		key := make([]string, 0, len(mapping))
+		for k := range mapping {
+			key = append(key, k)
+		}
+		sort.Strings(key)
+		for _, k := range key {
+			hashString(h, k)
+			if err := hashValue(h, valuerOf(v.FieldByIndex(mapping[k]))); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return hashValue(h, valuerOf(v))
+}
+
+// valuerOf returns the address of v if it implements driver.Valuer, e.g. by its pointer, and v otherwise
+func valuerOf(v reflect.Value) interface{} {
+	if v.CanAddr() && v.Kind() != reflect.Ptr && reflect.PtrTo(v.Type()).Implements(typeValuer) {
+		return v.Addr().Interface()
+	}
+	return v.Interface()
+}
+
+// isValueStruct reports whether struct v is loaded as a single value, e.g. time.Time or sql.NullString
+func isValueStruct(v reflect.Value) bool {
+	if v.Type() == reflect.TypeOf(time.Time{}) || reflect.PtrTo(v.Type()).Implements(typeScanner) {
+		return true
+	}
+	return reflect.PtrTo(v.Type()).Implements(typeValuer)
+}
+
+// hashValue writes a value into h with its kind, so e.g. 1 and "1" differ
+func hashValue(h hash.Hash, value interface{}) error {
+	kind, s, err := encodeCursorValue(value)
+	if err != nil {
+		return err
+	}
+	hashString(h, kind)
+	hashString(h, s)
+	return nil
+}
+
+// hashString writes s with its length, so concatenation of values is not ambiguous
+func hashString(h hash.Hash, s string) {
+	var n [binary.MaxVarintLen64]byte
+	h.Write(n[:binary.PutUvarint(n[:], uint64(len(s)))])
+	h.Write([]byte(s))
+}
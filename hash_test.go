@@ -0,0 +1,69 @@
+package dbr
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadWithHash(t *testing.T) {
+	type user struct {
+		ID   int64
+		Name string
+	}
+	sess, m := newSessionMock()
+	ctx := context.Background()
+
+	hashOf := func(column []string, rows ...[]interface{}) string {
+		r := sqlmock.NewRows(column)
+		for _, row := range rows {
+			values := make([]driver.Value, len(row))
+			for i, v := range row {
+				values[i] = v
+			}
+			r.AddRow(values...)
+		}
+		m.ExpectQuery("SELECT").WillReturnRows(r)
+		var users []user
+		hash, n, err := sess.Select("*").From("users").OrderBy("id").LoadWithHash(ctx, &users)
+		assert.NoError(t, err)
+		assert.Equal(t, len(rows), n)
+		return hash
+	}
+
+	hash := hashOf([]string{"id", "name"}, []interface{}{1, "a"}, []interface{}{2, "b"})
+	assert.Len(t, hash, 64)
+	// identical data
+	assert.Equal(t, hash, hashOf([]string{"id", "name"}, []interface{}{1, "a"}, []interface{}{2, "b"}))
+	// the order of columns does not matter
+	assert.Equal(t, hash, hashOf([]string{"name", "id"}, []interface{}{"a", 1}, []interface{}{"b", 2}))
+	// the order of rows does
+	assert.NotEqual(t, hash, hashOf([]string{"id", "name"}, []interface{}{2, "b"}, []interface{}{1, "a"}))
+	assert.NotEqual(t, hash, hashOf([]string{"id", "name"}, []interface{}{1, "a"}, []interface{}{2, "c"}))
+	assert.NotEqual(t, hash, hashOf([]string{"id", "name"}, []interface{}{1, "ab"}, []interface{}{2, ""}))
+	assert.NotEqual(t, hash, hashOf([]string{"id", "name"}, []interface{}{1, "a"}))
+
+	// maps are hashed by sorted keys, values keep their types
+	hashOfMap := func(column []string, row ...driver.Value) string {
+		m.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows(column).AddRow(row...))
+		var value []map[string]interface{}
+		hash, _, err := sess.Select("*").From("users").LoadWithHash(ctx, &value)
+		assert.NoError(t, err)
+		return hash
+	}
+	hash = hashOfMap([]string{"id", "name"}, int64(1), "a")
+	assert.Equal(t, hash, hashOfMap([]string{"name", "id"}, "a", int64(1)))
+	assert.NotEqual(t, hash, hashOfMap([]string{"id", "name"}, "1", "a"))
+
+	// a single value
+	m.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	var count int
+	hash, n, err := sess.Select("count(*)").From("users").LoadWithHash(ctx, &count)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Len(t, hash, 64)
+	assert.NoError(t, m.ExpectationsWereMet())
+}
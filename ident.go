@@ -1,5 +1,10 @@
 package dbr
 
+import (
+	"strings"
+	"unicode"
+)
+
 // I is a identifier, which always will be quoted
 type I string
 
@@ -14,6 +19,15 @@ func (i I) As(alias string) Builder {
 	return as(i, alias)
 }
 
+// As creates an alias for table, which may be a table name or a subquery, e.g.
+// From(As("users", "u")) builds FROM `users` AS `u`. Table name and alias are quoted
+func As(table interface{}, alias string) Builder {
+	if name, ok := table.(string); ok {
+		return as(I(name), alias)
+	}
+	return as(table, alias)
+}
+
 func as(expr interface{}, alias string) Builder {
 	return BuildFunc(func(d Dialect, buf Buffer) error {
 		buf.WriteString(placeholder)
@@ -23,3 +37,39 @@ func as(expr interface{}, alias string) Builder {
 		return nil
 	})
 }
+
+// qualifyingDialect makes conditions, e.g. Eq("id", 1), qualify their columns with alias of table,
+// other identifiers, e.g. of tables or I, are quoted as is
+type qualifyingDialect struct {
+	Dialect
+	alias string
+}
+
+// quoteColumn quotes column of condition, it is qualified with alias of qualifyingDialect
+func quoteColumn(d Dialect, column string) string {
+	if qd, ok := d.(qualifyingDialect); ok {
+		return qd.Dialect.QuoteIdent(qualify(qd.alias, column))
+	}
+	return d.QuoteIdent(column)
+}
+
+// qualify prefixes column with alias of table, qualified columns and expressions are returned as is
+func qualify(alias, column string) string {
+	if !isColumnName(column) {
+		return column
+	}
+	return alias + "." + column
+}
+
+// isColumnName reports whether s is a bare column name, not an expression, a qualified column or a literal
+func isColumnName(s string) bool {
+	if s == "" || strings.EqualFold(s, "null") || strings.EqualFold(s, "true") || strings.EqualFold(s, "false") {
+		return false
+	}
+	for i, r := range s {
+		if r != '_' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,153 @@
+package dbr
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// inTempChunkSize is the number of values inserted into temporary table by one statement
+const inTempChunkSize = 1000
+
+// inTempTables numbers temporary tables of WhereInTemp, so their names are unique
+var inTempTables uint64
+
+// inTemp is a condition of WhereInTemp
+type inTemp struct {
+	column string
+	value  interface{}
+}
+
+// WhereInTemp adds `column IN (SELECT v FROM tmp)` condition, the values are inserted into a temporary table
+// before the query and it is dropped after, e.g. for lists of 100k values, which are slow to be inlined.
+// The table is created in the transaction of Tx or in a new transaction of Session, because temporary tables
+// exist only in the connection creating them. It applies to all methods loading rows, e.g. Load, LoadEach or WriteCSV,
+// Explain returns ErrTempTableNotSupported. It is supported by PostgreSQL and MySQL, other dialects
+// return ErrTempTableNotSupported. The value must be a slice or an array
+func (b *selectBuilder) WhereInTemp(column string, value interface{}) SelectBuilder {
+	b.inTemp = append(b.inTemp, inTemp{column: column, value: value})
+	return b
+}
+
+// queryInTemp runs query with conditions of WhereInTemp and scans its rows
+func (b *selectBuilder) queryInTemp(ctx context.Context, scan func(rows *sql.Rows) (int, error)) (int, error) {
+	if b.Dialect.DropTempTable() == "" {
+		return 0, ErrTempTableNotSupported
+	}
+	var count int
+	load := func(tx *Tx) (err error) {
+		stmt := *b.selectStmt
+		stmt.WhereCond = append([]Builder(nil), stmt.WhereCond...)
+		for _, in := range b.inTemp {
+			v := reflect.ValueOf(in.value)
+			if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+				return fmt.Errorf("dbr: value of WhereInTemp must be a slice or an array, not %T", in.value)
+			}
+			if v.Len() == 0 {
+				// false as IN with empty list
+				stmt.WhereCond = append(stmt.WhereCond, Eq(in.column, in.value))
+				continue
+			}
+			// err is the result, so the deferred drop can report its error
+			var table string
+			table, err = createInTemp(ctx, tx, in.value)
+			if table != "" {
+				defer func() {
+					_, dropErr := tx.ExecContext(ctx, tx.Dialect.DropTempTable()+" "+tx.Dialect.QuoteIdent(table))
+					// rollback drops the table in PostgreSQL, if it fails because of the query
+					if err == nil {
+						err = dropErr
+					}
+				}()
+			}
+			if err != nil {
+				return err
+			}
+			stmt.WhereCond = append(stmt.WhereCond, inTempTable(in.column, table))
+		}
+		q := *b
+		q.runner = tx
+		q.EventReceiver = tx.EventReceiver
+		q.selectStmt = &stmt
+		q.inTemp = nil
+		count, err = q.queryRows(ctx, scan)
+		return err
+	}
+
+	var err error
+	switch r := b.runner.(type) {
+	case *Tx:
+		err = load(r)
+	case *Session:
+		err = r.Transact(ctx, load)
+	default:
+		err = ErrTempTableNotSupported
+	}
+	return count, err
+}
+
+// createInTemp creates a temporary table with column v and inserts value into it, value must be
+// a non-empty slice or array. It returns the name of the table if it is created
+func createInTemp(ctx context.Context, tx *Tx, value interface{}) (string, error) {
+	v := reflect.ValueOf(value)
+	typ, err := inTempColumnType(v.Type().Elem())
+	if err != nil {
+		return "", err
+	}
+	table := "dbr_in_" + strconv.FormatUint(atomic.AddUint64(&inTempTables, 1), 10)
+	query := fmt.Sprintf("CREATE TEMPORARY TABLE %s (%s %s)",
+		tx.Dialect.QuoteIdent(table), tx.Dialect.QuoteIdent("v"), tx.Dialect.ColumnType(typ))
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return "", tx.EventErrKv("dbr.in_temp.create", err, kvs{"sql": query})
+	}
+	for i := 0; i < v.Len(); i += inTempChunkSize {
+		stmt := tx.InsertInto(table).Columns("v")
+		for j := i; j < v.Len() && j < i+inTempChunkSize; j++ {
+			stmt.Values(v.Index(j).Interface())
+		}
+		if _, err := stmt.ExecContext(ctx); err != nil {
+			return table, err
+		}
+	}
+	return table, nil
+}
+
+// inTempColumnType returns portable type of column for values of type t
+func inTempColumnType(t reflect.Type) (string, error) {
+	if t == reflect.TypeOf(time.Time{}) {
+		return "TIMESTAMP", nil
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "BIGINT", nil
+	case reflect.Float32, reflect.Float64:
+		return "DOUBLE", nil
+	case reflect.String:
+		return "TEXT", nil
+	case reflect.Bool:
+		return "BOOL", nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "BYTES", nil
+		}
+	}
+	return "", fmt.Errorf("dbr: values of %v can not be inserted into temporary table", t)
+}
+
+// inTempTable builds `column IN (SELECT v FROM table)`
+func inTempTable(column, table string) Builder {
+	return BuildFunc(func(d Dialect, buf Buffer) error {
+		buf.WriteString(quoteColumn(d, column))
+		buf.WriteString(" IN (SELECT ")
+		buf.WriteString(d.QuoteIdent("v"))
+		buf.WriteString(" FROM ")
+		buf.WriteString(d.QuoteIdent(table))
+		buf.WriteString(")")
+		return nil
+	})
+}
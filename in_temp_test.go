@@ -0,0 +1,154 @@
+package dbr
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mailru/dbr/dialect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectWhereInTemp(t *testing.T) {
+	db, dbmock, err := sqlmock.New()
+	assert.NoError(t, err)
+	conn := &Connection{DB: db, Dialect: dialect.PostgreSQL, EventReceiver: nullReceiver}
+	dbmock.ExpectBegin()
+	dbmock.ExpectExec(`CREATE TEMPORARY TABLE "dbr_in_\d+" \("v" BIGINT\)`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	dbmock.ExpectExec(`INSERT INTO "dbr_in_\d+" \("v"\) VALUES \(1\), \(2\), \(3\)`).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	dbmock.ExpectQuery(`SELECT name FROM dbr_people WHERE \("active" = TRUE\) AND \("id" IN \(SELECT "v" FROM "dbr_in_\d+"\)\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("a").AddRow("b"))
+	dbmock.ExpectExec(`DROP TABLE "dbr_in_\d+"`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	dbmock.ExpectCommit()
+
+	var names []string
+	count, err := conn.NewSession(nil).Select("name").From("dbr_people").
+		Where(Eq("active", true)).
+		WhereInTemp("id", []int64{1, 2, 3}).
+		Load(&names)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, []string{"a", "b"}, names)
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+
+	// empty list is false and needs no table
+	dbmock.ExpectBegin()
+	dbmock.ExpectQuery(`SELECT name FROM dbr_people WHERE \(FALSE\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"name"}))
+	dbmock.ExpectCommit()
+	names = nil
+	count, err = conn.NewSession(nil).Select("name").From("dbr_people").WhereInTemp("id", []int64{}).Load(&names)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+
+	db, _, err = sqlmock.New()
+	assert.NoError(t, err)
+	conn = &Connection{DB: db, Dialect: dialect.SQLite3, EventReceiver: nullReceiver}
+	_, err = conn.NewSession(nil).Select("name").From("dbr_people").WhereInTemp("id", []int64{1}).Load(&names)
+	assert.Equal(t, ErrTempTableNotSupported, err)
+
+	// value must be a slice or an array
+	for _, value := range []interface{}{nil, 1} {
+		db, dbmock, err = sqlmock.New()
+		assert.NoError(t, err)
+		conn = &Connection{DB: db, Dialect: dialect.PostgreSQL, EventReceiver: nullReceiver}
+		dbmock.ExpectBegin()
+		dbmock.ExpectRollback()
+		_, err = conn.NewSession(nil).Select("name").From("dbr_people").WhereInTemp("id", value).Load(&names)
+		assert.Error(t, err)
+		assert.NoError(t, dbmock.ExpectationsWereMet())
+	}
+}
+
+func TestSelectWhereInTempStreaming(t *testing.T) {
+	db, dbmock, err := sqlmock.New()
+	assert.NoError(t, err)
+	sess := (&Connection{DB: db, Dialect: dialect.PostgreSQL, EventReceiver: nullReceiver}).NewSession(nil)
+	expect := func() {
+		dbmock.ExpectBegin()
+		dbmock.ExpectExec(`CREATE TEMPORARY TABLE "dbr_in_\d+" \("v" TEXT\)`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		dbmock.ExpectExec(`INSERT INTO "dbr_in_\d+" \("v"\) VALUES \('a'\)`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		dbmock.ExpectQuery(`SELECT name FROM dbr_people WHERE \("name" IN \(SELECT "v" FROM "dbr_in_\d+"\)\)`).
+			WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("a"))
+		dbmock.ExpectExec(`DROP TABLE "dbr_in_\d+"`).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		dbmock.ExpectCommit()
+	}
+	query := func() SelectBuilder {
+		return sess.Select("name").From("dbr_people").WhereInTemp("name", []string{"a"})
+	}
+
+	expect()
+	var name string
+	var names []string
+	count, err := query().LoadEach(context.Background(), &name, func() error {
+		names = append(names, name)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []string{"a"}, names)
+
+	expect()
+	buf := new(bytes.Buffer)
+	_, err = query().WriteCSV(context.Background(), buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "name\na\n", buf.String())
+
+	expect()
+	var person struct{ Name string }
+	assert.NoError(t, query().LoadStructStrict(context.Background(), &person))
+	assert.Equal(t, "a", person.Name)
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+
+	_, err = query().Explain(context.Background())
+	assert.Equal(t, ErrTempTableNotSupported, err)
+}
+
+func TestSelectWhereInTempQualifyColumns(t *testing.T) {
+	db, dbmock, err := sqlmock.New()
+	assert.NoError(t, err)
+	sess := (&Connection{DB: db, Dialect: dialect.MySQL, EventReceiver: nullReceiver}).NewSession(nil)
+	dbmock.ExpectBegin()
+	dbmock.ExpectExec("CREATE TEMPORARY TABLE `dbr_in_\\d+` \\(`v` BIGINT\\)").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	dbmock.ExpectExec("INSERT INTO `dbr_in_\\d+` \\(`v`\\) VALUES \\(1\\)").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// only the column of the condition is qualified, not the table and the column of the temporary table
+	dbmock.ExpectQuery("SELECT u.name FROM `users` AS `u` WHERE \\(`u`.`id` IN \\(SELECT `v` FROM `dbr_in_\\d+`\\)\\)$").
+		WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("a"))
+	dbmock.ExpectExec("DROP TEMPORARY TABLE `dbr_in_\\d+`").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	dbmock.ExpectCommit()
+
+	var names []string
+	_, err = sess.Select("name").From(As("users", "u")).QualifyColumns("u").WhereInTemp("id", []int64{1}).Load(&names)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a"}, names)
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+}
+
+func TestSelectWhereInTempDropError(t *testing.T) {
+	db, dbmock, err := sqlmock.New()
+	assert.NoError(t, err)
+	sess := (&Connection{DB: db, Dialect: dialect.PostgreSQL, EventReceiver: nullReceiver}).NewSession(nil)
+	dbmock.ExpectBegin()
+	dbmock.ExpectExec(`CREATE TEMPORARY TABLE`).WillReturnResult(sqlmock.NewResult(0, 0))
+	dbmock.ExpectExec(`INSERT INTO`).WillReturnResult(sqlmock.NewResult(0, 1))
+	dbmock.ExpectQuery(`SELECT name FROM dbr_people`).WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("a"))
+	dbmock.ExpectExec(`DROP TABLE`).WillReturnError(errors.New("drop"))
+	dbmock.ExpectRollback()
+
+	var names []string
+	_, err = sess.Select("name").From("dbr_people").WhereInTemp("id", []int64{1}).Load(&names)
+	assert.EqualError(t, err, "drop")
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strings"
 )
 
 // ConflictStmt is ` ON CONFLICT ...` part of InsertStmt
@@ -29,19 +30,43 @@ type InsertStmt interface {
 	Columns(column ...string) InsertStmt
 	Values(value ...interface{}) InsertStmt
 	Record(structValue interface{}) InsertStmt
+	Records(value interface{}) InsertStmt
 	OnConflictMap(constraint string, actions map[string]interface{}) InsertStmt
 	OnConflict(constraint string) ConflictStmt
+	Returning(column ...string) InsertStmt
+	Set(column string, value interface{}) InsertStmt
+	Async() InsertStmt
+	ReturningInserted() InsertStmt
+	OnConflictDoNothing() InsertStmt
 }
 
 type insertStmt struct {
 	raw
 
-	Table    string
-	Column   []string
-	Value    [][]interface{}
-	Conflict *conflictStmt
+	Table          string
+	Column         []string
+	Value          [][]interface{}
+	Conflict       *conflictStmt
+	ReturnColumn   []string
+	IsSet          bool
+	IsAsync        bool
+	ReturnInserted bool
+	IsDoNothing    bool
+	// recordsErr is the error of Records, it is returned by Build
+	recordsErr error
 }
 
+// Default is a value written as `DEFAULT`, so the column gets its default instead of NULL,
+// e.g. Values(1, dbr.Default, "x"). SQLite3 and ClickHouse return ErrDefaultNotSupported
+var Default Builder = BuildFunc(func(d Dialect, buf Buffer) error {
+	value := d.DefaultValue()
+	if value == "" {
+		return ErrDefaultNotSupported
+	}
+	buf.WriteString(value)
+	return nil
+})
+
 // Proposed is reference to proposed value in on conflict clause
 func Proposed(column string) Builder {
 	return BuildFunc(func(d Dialect, b Buffer) error {
@@ -53,6 +78,9 @@ func Proposed(column string) Builder {
 // Build builds `INSERT INTO ...` in dialect
 func (b *insertStmt) Build(d Dialect, buf Buffer) error {
 	if b.raw.Query != "" {
+		if b.ReturnInserted {
+			return ErrInsertedUndetermined
+		}
 		return b.raw.Build(d, buf)
 	}
 
@@ -60,36 +88,60 @@ func (b *insertStmt) Build(d Dialect, buf Buffer) error {
 		return ErrTableNotSpecified
 	}
 
+	if b.recordsErr != nil {
+		return b.recordsErr
+	}
+
 	if len(b.Column) == 0 {
 		return ErrColumnNotSpecified
 	}
 
-	buf.WriteString("INSERT INTO ")
-	buf.WriteString(d.QuoteIdent(b.Table))
+	var insertedExpr string
+	if b.ReturnInserted {
+		insertedExpr = d.UpsertInserted()
+		if len(b.Value) != 1 || (len(insertedExpr) == 0 && !d.UpsertAffectedRows()) {
+			return ErrInsertedUndetermined
+		}
+	}
 
-	placeholderBuf := new(bytes.Buffer)
-	placeholderBuf.WriteString("(")
-	buf.WriteString(" (")
-	for i, col := range b.Column {
-		if i > 0 {
-			buf.WriteString(",")
-			placeholderBuf.WriteString(",")
+	var asyncKeyword string
+	if b.IsAsync {
+		asyncKeyword = d.AsyncInsert()
+		if len(asyncKeyword) == 0 {
+			return ErrAsyncInsertNotSupported
 		}
-		buf.WriteString(d.QuoteIdent(col))
-		placeholderBuf.WriteString(placeholder)
 	}
-	buf.WriteString(") VALUES ")
-	placeholderBuf.WriteString(")")
-	placeholderStr := placeholderBuf.String()
 
-	for i, tuple := range b.Value {
-		if i > 0 {
-			buf.WriteString(", ")
+	var ignoreKeyword, doNothing string
+	if b.IsDoNothing {
+		if b.Conflict != nil && len(b.Conflict.actions) > 0 {
+			return ErrDoNothingWithConflict
 		}
-		buf.WriteString(placeholderStr)
+		ignoreKeyword, doNothing = d.InsertIgnore(), d.OnConflictDoNothing()
+		if len(ignoreKeyword) == 0 && len(doNothing) == 0 {
+			return ErrDoNothingNotSupported
+		}
+	}
 
-		buf.WriteValue(tuple...)
+	buf.WriteString("INSERT ")
+	if len(ignoreKeyword) > 0 {
+		buf.WriteString(ignoreKeyword)
+		buf.WriteString(" ")
 	}
+	buf.WriteString("INTO ")
+	buf.WriteString(d.QuoteIdent(b.Table))
+
+	if b.IsSet && len(b.Value) == 1 && d.SupportsInsertSet() {
+		b.buildSet(d, buf)
+	} else {
+		b.buildValues(d, buf, asyncKeyword)
+	}
+
+	if len(doNothing) > 0 {
+		buf.WriteString(" ")
+		buf.WriteString(doNothing)
+	}
+
 	if b.Conflict != nil && len(b.Conflict.actions) > 0 {
 		keyword := d.OnConflict(b.Conflict.constraint)
 		if len(keyword) == 0 {
@@ -113,9 +165,77 @@ func (b *insertStmt) Build(d Dialect, buf Buffer) error {
 		}
 	}
 
+	if len(b.ReturnColumn) > 0 || len(insertedExpr) > 0 {
+		keyword := d.Returning()
+		if len(keyword) == 0 {
+			return ErrReturningNotSupported
+		}
+		buf.WriteString(" ")
+		buf.WriteString(keyword)
+		buf.WriteString(" ")
+		for i, col := range b.ReturnColumn {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			buf.WriteString(d.QuoteIdent(col))
+		}
+		if len(insertedExpr) > 0 {
+			if len(b.ReturnColumn) > 0 {
+				buf.WriteString(",")
+			}
+			buf.WriteString(insertedExpr)
+		}
+	}
+
 	return nil
 }
 
+// buildValues builds ` (a,b) VALUES (?,?), (?,?)`, settings are written before VALUES
+func (b *insertStmt) buildValues(d Dialect, buf Buffer, settings string) {
+	placeholderBuf := new(bytes.Buffer)
+	placeholderBuf.WriteString("(")
+	buf.WriteString(" (")
+	for i, col := range b.Column {
+		if i > 0 {
+			buf.WriteString(",")
+			placeholderBuf.WriteString(",")
+		}
+		buf.WriteString(d.QuoteIdent(col))
+		placeholderBuf.WriteString(placeholder)
+	}
+	buf.WriteString(")")
+	if len(settings) > 0 {
+		buf.WriteString(" ")
+		buf.WriteString(settings)
+	}
+	buf.WriteString(" VALUES ")
+	placeholderBuf.WriteString(")")
+	placeholderStr := placeholderBuf.String()
+
+	for i, tuple := range b.Value {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(placeholderStr)
+
+		buf.WriteValue(tuple...)
+	}
+}
+
+// buildSet builds MySQL ` SET a=?, b=?` form of single row insert
+func (b *insertStmt) buildSet(d Dialect, buf Buffer) {
+	buf.WriteString(" SET ")
+	for i, col := range b.Column {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(d.QuoteIdent(col))
+		buf.WriteString("=")
+		buf.WriteString(placeholder)
+	}
+	buf.WriteValue(b.Value[0]...)
+}
+
 // InsertInto creates an InsertStmt
 func InsertInto(table string) InsertStmt {
 	return createInsertStmt(table)
@@ -154,7 +274,11 @@ func (b *insertStmt) Values(value ...interface{}) InsertStmt {
 }
 
 // Record adds a tuple for columns from a struct if no columns where
-// specified yet for this insert, the record fields will be used to populate the columns.
+// specified yet for this insert, the record fields will be used to populate the columns
+// in declaration order.
+// Fields tagged as readonly, e.g. `db:"full_name,readonly"`, are not used to populate the columns.
+// Zero time fields tagged as auto_create or auto_update, e.g. `db:"created_at,auto_create"`,
+// are set to the current time of NowFunc.
 func (b *insertStmt) Record(structValue interface{}) InsertStmt {
 	v := reflect.Indirect(reflect.ValueOf(structValue))
 
@@ -165,18 +289,21 @@ func (b *insertStmt) Record(structValue interface{}) InsertStmt {
 		// populate columns from available record fields
 		// if no columns were specified up to this point
 		if len(b.Column) == 0 {
-			b.Column = make([]string, 0, len(m))
-			for key := range m {
+			w := writableStructMap(v.Type())
+			b.Column = make([]string, 0, len(w))
+			for key := range w {
 				b.Column = append(b.Column, key)
 			}
 
-			// ensure that the column ordering is deterministic
-			sort.Strings(b.Column)
+			// columns are ordered as fields are declared, so the query is the same for the same type
+			sort.Slice(b.Column, func(i, j int) bool {
+				return indexLess(w[b.Column[i]], w[b.Column[j]])
+			})
 		}
 
 		for _, key := range b.Column {
 			if index, ok := m[key]; ok {
-				value = append(value, v.FieldByIndex(index).Interface())
+				value = append(value, autoTimestamp(v, index, false))
 			} else {
 				value = append(value, nil)
 			}
@@ -187,6 +314,61 @@ func (b *insertStmt) Record(structValue interface{}) InsertStmt {
 	return b
 }
 
+// Records adds a tuple for each element of a slice of structs or maps with string keys, so one
+// insert has many rows. If no columns were specified, they are taken from the first element:
+// fields of structs like in Record and sorted keys of maps. Each map must have the same keys
+// as the columns, Build returns an error otherwise
+func (b *insertStmt) Records(value interface{}) InsertStmt {
+	v := reflect.Indirect(reflect.ValueOf(value))
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		b.recordsErr = fmt.Errorf("dbr: records must be a slice of structs or maps, got %T", value)
+		return b
+	}
+	for i := 0; i < v.Len(); i++ {
+		elem := reflect.Indirect(v.Index(i))
+		if elem.Kind() == reflect.Interface {
+			elem = reflect.Indirect(elem.Elem())
+		}
+		switch {
+		case elem.Kind() == reflect.Struct:
+			b.Record(elem.Interface())
+		case elem.Kind() == reflect.Map && elem.Type().Key().Kind() == reflect.String:
+			if err := b.recordMap(elem); err != nil {
+				b.recordsErr = fmt.Errorf("dbr: record %d: %v", i, err)
+				return b
+			}
+		default:
+			b.recordsErr = fmt.Errorf("dbr: records must be a slice of structs or maps, got %T", value)
+			return b
+		}
+	}
+	return b
+}
+
+// recordMap adds a tuple from map m, its keys must be the columns
+func (b *insertStmt) recordMap(m reflect.Value) error {
+	if len(b.Column) == 0 {
+		b.Column = make([]string, 0, m.Len())
+		for _, key := range m.MapKeys() {
+			b.Column = append(b.Column, key.String())
+		}
+		sort.Strings(b.Column)
+	}
+	if m.Len() != len(b.Column) {
+		return fmt.Errorf("keys do not match columns (%s)", strings.Join(b.Column, ", "))
+	}
+	value := make([]interface{}, len(b.Column))
+	for i, key := range b.Column {
+		elem := m.MapIndex(reflect.ValueOf(key).Convert(m.Type().Key()))
+		if !elem.IsValid() {
+			return fmt.Errorf("keys do not match columns (%s)", strings.Join(b.Column, ", "))
+		}
+		value[i] = elem.Interface()
+	}
+	b.Values(value...)
+	return nil
+}
+
 // OnConflictMap allows to add actions for constraint violation, e.g UPSERT
 func (b *insertStmt) OnConflictMap(constraint string, actions map[string]interface{}) InsertStmt {
 	b.Conflict = &conflictStmt{constraint: constraint, actions: actions}
@@ -198,3 +380,47 @@ func (b *insertStmt) OnConflict(constraint string) ConflictStmt {
 	b.Conflict = &conflictStmt{constraint: constraint, actions: make(map[string]interface{})}
 	return b.Conflict
 }
+
+// Set adds a column value pair of single row insert, it is built as `INSERT INTO table SET a=?, b=?`
+// in MySQL and as equivalent `INSERT INTO table (a,b) VALUES (?,?)` in other dialects
+func (b *insertStmt) Set(column string, value interface{}) InsertStmt {
+	b.IsSet = true
+	b.Columns(column)
+	switch len(b.Value) {
+	case 0:
+		b.Values(value)
+	case 1:
+		b.Value[0] = append(b.Value[0], value)
+	default:
+		panic("set only allows one record to insert")
+	}
+	return b
+}
+
+// Async makes ClickHouse insert asynchronously with `SETTINGS async_insert=1`,
+// the data is buffered by server and flushed in batches. Other dialects return ErrAsyncInsertNotSupported
+func (b *insertStmt) Async() InsertStmt {
+	b.IsAsync = true
+	return b
+}
+
+// OnConflictDoNothing skips rows which conflict with existing ones, it is INSERT IGNORE in MySQL
+// and ON CONFLICT DO NOTHING in PostgreSQL and SQLite3. ClickHouse returns ErrDoNothingNotSupported,
+// combined with OnConflict actions Build returns ErrDoNothingWithConflict
+func (b *insertStmt) OnConflictDoNothing() InsertStmt {
+	b.IsDoNothing = true
+	return b
+}
+
+// Returning specifies columns returned by the insert, e.g. generated id and defaults
+func (b *insertStmt) Returning(column ...string) InsertStmt {
+	b.ReturnColumn = append(b.ReturnColumn, column...)
+	return b
+}
+
+// ReturningInserted returns whether the row was inserted, not updated on conflict, it is `RETURNING (xmax = 0)`
+// in PostgreSQL, MySQL reports it by affected rows. Inserts of several rows return ErrInsertedUndetermined
+func (b *insertStmt) ReturningInserted() InsertStmt {
+	b.ReturnInserted = true
+	return b
+}
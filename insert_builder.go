@@ -3,6 +3,7 @@ package dbr
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"reflect"
 )
 
@@ -14,9 +15,21 @@ type InsertBuilder interface {
 	Columns(column ...string) InsertBuilder
 	Values(value ...interface{}) InsertBuilder
 	Record(structValue interface{}) InsertBuilder
+	Records(value interface{}) InsertBuilder
 	OnConflictMap(constraint string, actions map[string]interface{}) InsertBuilder
 	OnConflict(constraint string) ConflictStmt
 	Pair(column string, value interface{}) InsertBuilder
+	Set(column string, value interface{}) InsertBuilder
+	Async() InsertBuilder
+	WithEventKv(key, value string) InsertBuilder
+	Returning(column ...string) InsertBuilder
+	ReturningInserted(inserted *bool) InsertBuilder
+	OnConflictDoNothing() InsertBuilder
+	ExecInsertedCount(ctx context.Context) (int64, error)
+	BatchSize(n int) InsertBuilder
+	StreamFrom(ctx context.Context, ch <-chan []interface{}) (int64, error)
+	LoadStruct(value interface{}) error
+	LoadStructContext(ctx context.Context, value interface{}) error
 }
 
 // InsertBuilder builds "INSERT ..." stmt
@@ -27,8 +40,14 @@ type insertBuilder struct {
 	Dialect    Dialect
 	RecordID   reflect.Value
 	insertStmt *insertStmt
+	eventKvs   kvs
+	inserted   *bool
+	batchSize  int
 }
 
+// defaultStreamBatchSize is the number of rows inserted at once by StreamFrom, unless BatchSize is set
+const defaultStreamBatchSize = 1000
+
 // InsertInto creates a InsertBuilder
 func (sess *Session) InsertInto(table string) InsertBuilder {
 	return &insertBuilder{
@@ -87,6 +106,26 @@ func (b *insertBuilder) Pair(column string, value interface{}) InsertBuilder {
 	return b
 }
 
+// Set adds a column value pair, it is built as `INSERT INTO table SET a=?, b=?` in MySQL
+// and as `INSERT INTO table (a,b) VALUES (?,?)` in other dialects
+func (b *insertBuilder) Set(column string, value interface{}) InsertBuilder {
+	b.insertStmt.Set(column, value)
+	return b
+}
+
+// Async makes ClickHouse insert asynchronously with `SETTINGS async_insert=1`,
+// other dialects return ErrAsyncInsertNotSupported
+func (b *insertBuilder) Async() InsertBuilder {
+	b.insertStmt.Async()
+	return b
+}
+
+// OnConflictDoNothing skips rows which conflict with existing ones
+func (b *insertBuilder) OnConflictDoNothing() InsertBuilder {
+	b.insertStmt.OnConflictDoNothing()
+	return b
+}
+
 // Exec executes the stmt with background context
 func (b *insertBuilder) Exec() (sql.Result, error) {
 	return b.ExecContext(context.Background())
@@ -94,11 +133,24 @@ func (b *insertBuilder) Exec() (sql.Result, error) {
 
 // ExecContext executes the stmt
 func (b *insertBuilder) ExecContext(ctx context.Context) (sql.Result, error) {
-	result, err := exec(ctx, b.runner, b.EventReceiver, b, b.Dialect)
+	if b.inserted != nil && !b.Dialect.UpsertAffectedRows() {
+		return b.execReturningInserted(ctx)
+	}
+
+	result, err := exec(ctx, b.runner, b.EventReceiver, b, b.Dialect, b.eventKvs)
 	if err != nil {
 		return nil, err
 	}
 
+	if b.inserted != nil {
+		n, err := result.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		// 1 for inserted row, 2 for updated one and 0 for row left unchanged
+		*b.inserted = n == 1
+	}
+
 	if b.RecordID.IsValid() {
 		if id, err := result.LastInsertId(); err == nil {
 			b.RecordID.SetInt(id)
@@ -108,6 +160,74 @@ func (b *insertBuilder) ExecContext(ctx context.Context) (sql.Result, error) {
 	return result, nil
 }
 
+// ExecInsertedCount executes the stmt and returns the number of inserted rows, e.g. of OnConflictDoNothing,
+// rows skipped because of conflicts are not counted. It returns ErrInsertedUndetermined for upserts,
+// because updated rows are counted too, e.g. twice in MySQL
+func (b *insertBuilder) ExecInsertedCount(ctx context.Context) (int64, error) {
+	if b.insertStmt.Conflict != nil && len(b.insertStmt.Conflict.actions) > 0 {
+		return 0, ErrInsertedUndetermined
+	}
+	result, err := b.ExecContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// BatchSize sets the number of rows inserted by one statement of StreamFrom, 1000 by default
+func (b *insertBuilder) BatchSize(n int) InsertBuilder {
+	b.batchSize = n
+	return b
+}
+
+// StreamFrom inserts rows read from ch in batches until ch is closed, the last partial batch
+// is inserted on close. Rows are read only while the previous batch is not being inserted,
+// so slow inserts block the producer. It returns the number of inserted rows,
+// on error or cancel of ctx the rows of the batches inserted before are counted
+func (b *insertBuilder) StreamFrom(ctx context.Context, ch <-chan []interface{}) (int64, error) {
+	size := b.batchSize
+	if size <= 0 {
+		size = defaultStreamBatchSize
+	}
+	var count int64
+	flush := func(batch [][]interface{}) error {
+		b.insertStmt.Value = batch
+		result, err := exec(ctx, b.runner, b.EventReceiver, b, b.Dialect, b.eventKvs)
+		if err != nil {
+			return err
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			// the driver does not report affected rows, all rows of the batch are counted
+			n = int64(len(batch))
+		}
+		count += n
+		return nil
+	}
+	batch := make([][]interface{}, 0, size)
+	for {
+		select {
+		case <-ctx.Done():
+			return count, ctx.Err()
+		case row, ok := <-ch:
+			if !ok {
+				if len(batch) == 0 {
+					return count, nil
+				}
+				return count, flush(batch)
+			}
+			batch = append(batch, row)
+			if len(batch) < size {
+				continue
+			}
+			if err := flush(batch); err != nil {
+				return count, err
+			}
+			batch = make([][]interface{}, 0, size)
+		}
+	}
+}
+
 // Columns adds columns
 func (b *insertBuilder) Columns(column ...string) InsertBuilder {
 	b.insertStmt.Columns(column...)
@@ -138,6 +258,12 @@ func (b *insertBuilder) Record(structValue interface{}) InsertBuilder {
 	return b
 }
 
+// Records adds a tuple for each element of a slice of structs or maps, ids of the structs are not set
+func (b *insertBuilder) Records(value interface{}) InsertBuilder {
+	b.insertStmt.Records(value)
+	return b
+}
+
 // OnConflictMap allows to add actions for constraint violation, e.g UPSERT
 func (b *insertBuilder) OnConflictMap(constraint string, actions map[string]interface{}) InsertBuilder {
 	b.insertStmt.OnConflictMap(constraint, actions)
@@ -148,3 +274,64 @@ func (b *insertBuilder) OnConflictMap(constraint string, actions map[string]inte
 func (b *insertBuilder) OnConflict(constraint string) ConflictStmt {
 	return b.insertStmt.OnConflict(constraint)
 }
+
+// WithEventKv adds a key/value pair to the events of this query,
+// it never overwrites the keys set by dbr itself, e.g. "sql"
+func (b *insertBuilder) WithEventKv(key, value string) InsertBuilder {
+	if b.eventKvs == nil {
+		b.eventKvs = make(kvs)
+	}
+	b.eventKvs[key] = value
+	return b
+}
+
+// Returning specifies columns returned by the insert, use LoadStruct to load them
+func (b *insertBuilder) Returning(column ...string) InsertBuilder {
+	b.insertStmt.Returning(column...)
+	return b
+}
+
+// ReturningInserted makes Exec report whether the row was inserted, not updated on conflict.
+// PostgreSQL returns `xmax = 0` of the row, MySQL reports it by affected rows, which are 1 for inserted row,
+// so it is wrong if the connection sets CLIENT_FOUND_ROWS flag. Other dialects and inserts of several rows
+// return ErrInsertedUndetermined
+func (b *insertBuilder) ReturningInserted(inserted *bool) InsertBuilder {
+	b.insertStmt.ReturningInserted()
+	b.inserted = inserted
+	return b
+}
+
+// execReturningInserted executes the stmt loading whether the row was inserted from RETURNING
+func (b *insertBuilder) execReturningInserted(ctx context.Context) (sql.Result, error) {
+	if err := checkWritable(b.runner, b.EventReceiver); err != nil {
+		return nil, err
+	}
+	*b.inserted = false
+	count, err := query(ctx, b.runner, b.EventReceiver, b, b.Dialect, b.eventKvs, b.inserted)
+	if err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(count), nil
+}
+
+// LoadStruct executes the stmt with background context and loads the returned columns into struct
+func (b *insertBuilder) LoadStruct(value interface{}) error {
+	return b.LoadStructContext(context.Background(), value)
+}
+
+// LoadStructContext executes the stmt and loads the returned columns into struct,
+// fields which do not match returned columns are left intact,
+// returns ErrNotFound if nothing was returned
+func (b *insertBuilder) LoadStructContext(ctx context.Context, value interface{}) error {
+	if err := checkWritable(b.runner, b.EventReceiver); err != nil {
+		return err
+	}
+	count, err := query(ctx, b.runner, b.EventReceiver, b, b.Dialect, b.eventKvs, value)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -1,8 +1,12 @@
 package dbr
 
 import (
+	"context"
+	"regexp"
 	"testing"
+	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/mailru/dbr/dialect"
 	"github.com/stretchr/testify/assert"
 )
@@ -13,6 +17,24 @@ type insertTest struct {
 	C string `db:"b"`
 }
 
+type readonlyTest struct {
+	A        int
+	FullName string `db:"full_name,readonly"`
+}
+
+type timestampTest struct {
+	A         int
+	CreatedAt time.Time  `db:"created_at,auto_create"`
+	UpdatedAt *time.Time `db:"updated_at,auto_update"`
+	DeletedAt NullTime   `db:"deleted_at"`
+}
+
+// setNow makes NowFunc return now, the returned func restores it
+func setNow(now time.Time) func() {
+	NowFunc = func() time.Time { return now }
+	return func() { NowFunc = time.Now }
+}
+
 func TestInsertStmt(t *testing.T) {
 	buf := NewBuffer()
 	builder := InsertInto("table").Columns("a", "b").Values(1, "one").Record(&insertTest{
@@ -25,6 +47,91 @@ func TestInsertStmt(t *testing.T) {
 	assert.Equal(t, []interface{}{1, "one", 2, "two"}, buf.Value())
 }
 
+func TestInsertSetStmt(t *testing.T) {
+	for _, test := range []struct {
+		d     Dialect
+		query string
+	}{
+		{d: dialect.MySQL, query: "INSERT INTO `table` SET `a`=?, `b`=?"},
+		{d: dialect.PostgreSQL, query: `INSERT INTO "table" ("a","b") VALUES (?,?)`},
+		{d: dialect.SQLite3, query: `INSERT INTO "table" ("a","b") VALUES (?,?)`},
+		{d: dialect.ClickHouse, query: "INSERT INTO `table` (`a`,`b`) VALUES (?,?)"},
+	} {
+		buf := NewBuffer()
+		err := InsertInto("table").Set("a", 1).Set("b", "one").Build(test.d, buf)
+		assert.NoError(t, err)
+		assert.Equal(t, test.query, buf.String())
+		assert.Equal(t, []interface{}{1, "one"}, buf.Value())
+	}
+
+	// ON DUPLICATE KEY UPDATE works with SET form
+	buf := NewBuffer()
+	stmt := InsertInto("table").Set("a", 1).Set("b", "one")
+	stmt.OnConflict("").Action("b", "two")
+	err := stmt.Build(dialect.MySQL, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `table` SET `a`=?, `b`=? ON DUPLICATE KEY UPDATE `b`=?", buf.String())
+	assert.Equal(t, []interface{}{1, "one", "two"}, buf.Value())
+
+	assert.Panics(t, func() {
+		InsertInto("table").Columns("a").Values(1).Values(2).Set("b", 3)
+	})
+}
+
+func TestInsertDefault(t *testing.T) {
+	for _, test := range []struct {
+		d            Dialect
+		query        string
+		placeholders string
+	}{
+		{
+			d:            dialect.MySQL,
+			query:        "INSERT INTO `table` (`a`,`b`,`c`) VALUES (1,DEFAULT,'x'), (DEFAULT,2,NULL)",
+			placeholders: "INSERT INTO `table` (`a`,`b`,`c`) VALUES (?,DEFAULT,?), (DEFAULT,?,?)",
+		},
+		{
+			d:            dialect.PostgreSQL,
+			query:        `INSERT INTO "table" ("a","b","c") VALUES (1,DEFAULT,'x'), (DEFAULT,2,NULL)`,
+			placeholders: `INSERT INTO "table" ("a","b","c") VALUES ($1,DEFAULT,$2), (DEFAULT,$3,$4)`,
+		},
+	} {
+		buf := NewBuffer()
+		err := InsertInto("table").Columns("a", "b", "c").Values(1, Default, "x").Values(Default, 2, nil).Build(test.d, buf)
+		assert.NoError(t, err)
+		query, err := InterpolateForDialect(buf.String(), buf.Value(), test.d)
+		assert.NoError(t, err)
+		assert.Equal(t, test.query, query)
+
+		// DEFAULT is a keyword, not a bound value
+		i := interpolator{Buffer: NewBuffer(), Dialect: test.d, UsePlaceholders: true}
+		err = i.interpolate(buf.String(), buf.Value())
+		assert.NoError(t, err)
+		assert.Equal(t, test.placeholders, i.String())
+		assert.Equal(t, []interface{}{1, "x", 2, nil}, i.Value())
+	}
+
+	for _, d := range []Dialect{dialect.SQLite3, dialect.ClickHouse} {
+		buf := NewBuffer()
+		err := InsertInto("table").Columns("a", "b").Values(1, Default).Build(d, buf)
+		assert.NoError(t, err)
+		_, err = InterpolateForDialect(buf.String(), buf.Value(), d)
+		assert.Equal(t, ErrDefaultNotSupported, err)
+	}
+}
+
+func TestInsertAsyncStmt(t *testing.T) {
+	buf := NewBuffer()
+	err := InsertInto("table").Columns("a", "b").Values(1, "one").Values(2, "two").Async().Build(dialect.ClickHouse, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `table` (`a`,`b`) SETTINGS async_insert=1 VALUES (?,?), (?,?)", buf.String())
+	assert.Equal(t, []interface{}{1, "one", 2, "two"}, buf.Value())
+
+	for _, d := range []Dialect{dialect.MySQL, dialect.PostgreSQL, dialect.SQLite3} {
+		err := InsertInto("table").Columns("a").Values(1).Async().Build(d, NewBuffer())
+		assert.Equal(t, ErrAsyncInsertNotSupported, err)
+	}
+}
+
 func TestInsertRecordNoColumns(t *testing.T) {
 	buf := NewBuffer()
 	builder := InsertInto("table").Record(&insertTest{
@@ -37,6 +144,84 @@ func TestInsertRecordNoColumns(t *testing.T) {
 	assert.Equal(t, []interface{}{2, "two", 1, "one"}, buf.Value())
 }
 
+func TestInsertRecordColumnOrder(t *testing.T) {
+	type record struct {
+		Zeta  int
+		Alpha string
+		Mid   bool `db:"m"`
+		Beta  int64
+	}
+	for n := 0; n < 2; n++ {
+		buf := NewBuffer()
+		err := InsertInto("table").Record(&record{Zeta: 1, Alpha: "a", Mid: true, Beta: 2}).Build(dialect.MySQL, buf)
+		assert.NoError(t, err)
+		assert.Equal(t, "INSERT INTO `table` (`zeta`,`alpha`,`m`,`beta`) VALUES (?,?,?,?)", buf.String())
+		assert.Equal(t, []interface{}{1, "a", true, int64(2)}, buf.Value())
+	}
+}
+
+func TestInsertRecordReadonly(t *testing.T) {
+	buf := NewBuffer()
+	builder := InsertInto("table").Record(&readonlyTest{
+		A:        2,
+		FullName: "two",
+	})
+	err := builder.Build(dialect.MySQL, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `table` (`a`) VALUES (?)", buf.String())
+	assert.Equal(t, []interface{}{2}, buf.Value())
+}
+
+func TestInsertRecordAutoTimestamps(t *testing.T) {
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	defer setNow(now)()
+
+	record := &timestampTest{A: 1}
+	buf := NewBuffer()
+	err := InsertInto("table").Record(record).Build(dialect.MySQL, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `table` (`a`,`created_at`,`updated_at`,`deleted_at`) VALUES (?,?,?,?)", buf.String())
+	assert.Equal(t, []interface{}{1, now, &now, NullTime{}}, buf.Value())
+	// the fields are set too
+	assert.Equal(t, now, record.CreatedAt)
+	assert.Equal(t, &now, record.UpdatedAt)
+
+	// set fields are kept, records passed by value are not changed
+	created := now.Add(-time.Hour)
+	buf = NewBuffer()
+	err = InsertInto("table").Records([]timestampTest{{A: 2, CreatedAt: created}}).Build(dialect.MySQL, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{2, created, &now, NullTime{}}, buf.Value())
+}
+
+func TestInsertRecords(t *testing.T) {
+	buf := NewBuffer()
+	err := InsertInto("table").Records([]insertTest{{A: 1, C: "one"}, {A: 2, C: "two"}}).Build(dialect.MySQL, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `table` (`a`,`b`) VALUES (?,?), (?,?)", buf.String())
+	assert.Equal(t, []interface{}{1, "one", 2, "two"}, buf.Value())
+
+	buf = NewBuffer()
+	err = InsertInto("table").Records([]map[string]interface{}{
+		{"b": "one", "a": 1},
+		{"a": 2, "b": "two"},
+	}).Build(dialect.MySQL, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO `table` (`a`,`b`) VALUES (?,?), (?,?)", buf.String())
+	assert.Equal(t, []interface{}{1, "one", 2, "two"}, buf.Value())
+
+	for _, records := range []interface{}{
+		[]map[string]interface{}{{"a": 1, "b": "one"}, {"a": 2}},
+		[]map[string]interface{}{{"a": 1, "b": "one"}, {"a": 2, "c": "two"}},
+		[]map[string]interface{}{{"a": 1}, {"a": 2, "b": "two"}},
+		[]int{1, 2},
+		insertTest{},
+	} {
+		err := InsertInto("table").Records(records).Build(dialect.MySQL, NewBuffer())
+		assert.Error(t, err, "%v", records)
+	}
+}
+
 func TestInsertOnConflictStmt(t *testing.T) {
 	buf := NewBuffer()
 	exp := Expr("a + ?", 1)
@@ -58,6 +243,186 @@ func TestInsertOnConflictMapStmt(t *testing.T) {
 	assert.Equal(t, []interface{}{1, "one", exp, "one"}, buf.Value())
 }
 
+func TestInsertReturningStmt(t *testing.T) {
+	buf := NewBuffer()
+	builder := InsertInto("table").Columns("a", "b").Values(1, "one").Returning("id", "created_at")
+	err := builder.Build(dialect.PostgreSQL, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, `INSERT INTO "table" ("a","b") VALUES (?,?) RETURNING "id","created_at"`, buf.String())
+	assert.Equal(t, []interface{}{1, "one"}, buf.Value())
+
+	for _, d := range []Dialect{dialect.MySQL, dialect.SQLite3} {
+		err = builder.Build(d, NewBuffer())
+		assert.Equal(t, ErrReturningNotSupported, err)
+	}
+}
+
+func TestInsertReturningLoadStruct(t *testing.T) {
+	type returningRecord struct {
+		ID        int64
+		Name      string
+		Email     string
+		CreatedAt string
+	}
+	db, dbmock, err := sqlmock.New()
+	assert.NoError(t, err)
+	conn := Connection{DB: db, Dialect: dialect.PostgreSQL, EventReceiver: nullReceiver}
+	sess := conn.NewSession(nil)
+
+	rows := sqlmock.NewRows([]string{"id", "created_at"}).AddRow(7, "2020-01-01")
+	dbmock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "people" ("name") VALUES ('jonathan') RETURNING "id","created_at"`)).WillReturnRows(rows)
+
+	record := returningRecord{Name: "jonathan", Email: "untouched"}
+	err = sess.InsertInto("people").Columns("name").Record(&record).Returning("id", "created_at").LoadStruct(&record)
+	assert.NoError(t, err)
+	assert.Equal(t, returningRecord{ID: 7, Name: "jonathan", Email: "untouched", CreatedAt: "2020-01-01"}, record)
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+}
+
+func TestInsertReturningInserted(t *testing.T) {
+	db, dbmock, err := sqlmock.New()
+	assert.NoError(t, err)
+	conn := Connection{DB: db, Dialect: dialect.PostgreSQL, EventReceiver: nullReceiver}
+	sess := conn.NewSession(nil)
+
+	dbmock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "keys" ("k","v") VALUES ('a','b') ON CONFLICT ON CONSTRAINT "keys_pkey" DO UPDATE SET "v"='b' RETURNING (xmax = 0)`)).
+		WillReturnRows(sqlmock.NewRows([]string{"?column?"}).AddRow(false))
+
+	inserted := true
+	_, err = sess.InsertInto("keys").Columns("k", "v").Values("a", "b").
+		OnConflictMap("keys_pkey", map[string]interface{}{"v": "b"}).
+		ReturningInserted(&inserted).
+		Exec()
+	assert.NoError(t, err)
+	assert.False(t, inserted)
+
+	conn.Dialect = dialect.MySQL
+	sess = conn.NewSession(nil)
+	for _, test := range []struct {
+		affected int64
+		inserted bool
+	}{
+		{affected: 1, inserted: true},
+		{affected: 2, inserted: false},
+		{affected: 0, inserted: false},
+	} {
+		dbmock.ExpectExec(regexp.QuoteMeta("INSERT INTO `keys` (`k`,`v`) VALUES ('a','b') ON DUPLICATE KEY UPDATE `v`='b'")).
+			WillReturnResult(sqlmock.NewResult(0, test.affected))
+		_, err = sess.InsertInto("keys").Columns("k", "v").Values("a", "b").
+			OnConflictMap("", map[string]interface{}{"v": "b"}).
+			ReturningInserted(&inserted).
+			Exec()
+		assert.NoError(t, err)
+		assert.Equal(t, test.inserted, inserted)
+	}
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+
+	for _, d := range []Dialect{dialect.SQLite3, dialect.ClickHouse} {
+		err = InsertInto("keys").Columns("k").Values("a").ReturningInserted().Build(d, NewBuffer())
+		assert.Equal(t, ErrInsertedUndetermined, err)
+	}
+	err = InsertInto("keys").Columns("k").Values("a").Values("b").ReturningInserted().Build(dialect.PostgreSQL, NewBuffer())
+	assert.Equal(t, ErrInsertedUndetermined, err)
+}
+
+func TestInsertOnConflictDoNothing(t *testing.T) {
+	for _, test := range []struct {
+		d     Dialect
+		query string
+	}{
+		{d: dialect.MySQL, query: "INSERT IGNORE INTO `keys` (`k`,`v`) VALUES ('a','b'), ('c','d')"},
+		{d: dialect.PostgreSQL, query: `INSERT INTO "keys" ("k","v") VALUES ('a','b'), ('c','d') ON CONFLICT DO NOTHING`},
+		{d: dialect.SQLite3, query: `INSERT INTO "keys" ("k","v") VALUES ('a','b'), ('c','d') ON CONFLICT DO NOTHING`},
+	} {
+		buf := NewBuffer()
+		err := InsertInto("keys").Columns("k", "v").Values("a", "b").Values("c", "d").OnConflictDoNothing().Build(test.d, buf)
+		assert.NoError(t, err)
+		query, err := InterpolateForDialect(buf.String(), buf.Value(), test.d)
+		assert.NoError(t, err)
+		assert.Equal(t, test.query, query)
+	}
+	err := InsertInto("keys").Columns("k").Values("a").OnConflictDoNothing().Build(dialect.ClickHouse, NewBuffer())
+	assert.Equal(t, ErrDoNothingNotSupported, err)
+	for _, d := range []Dialect{dialect.MySQL, dialect.PostgreSQL, dialect.SQLite3} {
+		err = InsertInto("keys").Columns("k", "v").Values("a", "b").
+			OnConflictMap("keys_pkey", map[string]interface{}{"v": "b"}).
+			OnConflictDoNothing().
+			Build(d, NewBuffer())
+		assert.Equal(t, ErrDoNothingWithConflict, err)
+	}
+
+	db, dbmock, err := sqlmock.New()
+	assert.NoError(t, err)
+	conn := Connection{DB: db, Dialect: dialect.PostgreSQL, EventReceiver: nullReceiver}
+	sess := conn.NewSession(nil)
+	dbmock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "keys" ("k","v") VALUES ('a','b'), ('c','d') ON CONFLICT DO NOTHING`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	n, err := sess.InsertInto("keys").Columns("k", "v").Values("a", "b").Values("c", "d").
+		OnConflictDoNothing().
+		ExecInsertedCount(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+
+	_, err = sess.InsertInto("keys").Columns("k", "v").Values("a", "b").
+		OnConflictMap("keys_pkey", map[string]interface{}{"v": "b"}).
+		ExecInsertedCount(context.Background())
+	assert.Equal(t, ErrInsertedUndetermined, err)
+}
+
+func TestInsertStreamFrom(t *testing.T) {
+	db, dbmock, err := sqlmock.New()
+	assert.NoError(t, err)
+	conn := Connection{DB: db, Dialect: dialect.PostgreSQL, EventReceiver: nullReceiver}
+	sess := conn.NewSession(nil)
+
+	// the first batch is inserted slowly, the producer is blocked meanwhile
+	const delay = 50 * time.Millisecond
+	dbmock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "events" ("id","name") VALUES (1,'a'), (2,'b')`)).
+		WillDelayFor(delay).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	dbmock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "events" ("id","name") VALUES (3,'c'), (4,'d')`)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	// the partial batch is inserted on close
+	dbmock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "events" ("id","name") VALUES (5,'e')`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	ch := make(chan []interface{})
+	sent := make([]time.Duration, 0, 5)
+	start := time.Now()
+	go func() {
+		for i, name := range []string{"a", "b", "c", "d", "e"} {
+			ch <- []interface{}{i + 1, name}
+			sent = append(sent, time.Since(start))
+		}
+		close(ch)
+	}()
+	n, err := sess.InsertInto("events").Columns("id", "name").BatchSize(2).StreamFrom(context.Background(), ch)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+	if assert.Len(t, sent, 5) {
+		assert.True(t, sent[1] < delay)
+		assert.True(t, sent[2] >= delay)
+	}
+
+	// rows of the inserted batches are counted on cancel
+	dbmock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "events" ("id","name") VALUES (1,'a'), (2,'b')`)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	ctx, cancel := context.WithCancel(context.Background())
+	ch = make(chan []interface{})
+	go func() {
+		ch <- []interface{}{1, "a"}
+		ch <- []interface{}{2, "b"}
+		ch <- []interface{}{3, "c"}
+		cancel()
+	}()
+	n, err = sess.InsertInto("events").Columns("id", "name").BatchSize(2).StreamFrom(ctx, ch)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, int64(2), n)
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+}
+
 func BenchmarkInsertValuesSQL(b *testing.B) {
 	buf := NewBuffer()
 	for i := 0; i < b.N; i++ {
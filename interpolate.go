@@ -2,10 +2,13 @@ package dbr
 
 import (
 	"database/sql/driver"
+	"encoding/json"
+	"math/big"
 	"reflect"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 	_ "unsafe" // needs for reflect.UnsafeAddr
 )
@@ -14,7 +17,43 @@ type interpolator struct {
 	Buffer
 	Dialect
 	IgnoreBinary bool
-	N            int
+	// UsePlaceholders writes placeholders of the dialect for all values, not only binary,
+	// slices are still expanded, so each element has its own placeholder
+	UsePlaceholders bool
+	// Redact masks values marked by Sensitive
+	Redact bool
+	// KeywordCase changes case of keywords in queries, not in values
+	KeywordCase KeywordCase
+	N           int
+}
+
+// typeEncoders are functions registered by RegisterType
+var typeEncoders sync.Map
+
+// RegisterType registers fn converting values of type t to strings, which are interpolated as quoted strings,
+// e.g. for enums implementing fmt.Stringer instead of their underlying int. Types implementing driver.Valuer
+// use Value instead. It is safe for concurrent use, nil fn removes the registration
+func RegisterType(t reflect.Type, fn func(v interface{}) (string, error)) {
+	if fn == nil {
+		typeEncoders.Delete(t)
+		return
+	}
+	typeEncoders.Store(t, fn)
+}
+
+// encodeRegisteredType converts value by the function registered for its type
+func encodeRegisteredType(value interface{}) (interface{}, error) {
+	if value == nil {
+		return value, nil
+	}
+	if _, ok := value.(driver.Valuer); ok {
+		return value, nil
+	}
+	fn, ok := typeEncoders.Load(reflect.TypeOf(value))
+	if !ok {
+		return value, nil
+	}
+	return fn.(func(v interface{}) (string, error))(value)
 }
 
 // InterpolateForDialect replaces placeholder in query with corresponding value in dialect
@@ -30,26 +69,124 @@ func InterpolateForDialect(query string, value []interface{}, d Dialect) (string
 	return i.String(), nil
 }
 
+// placeholderIndex returns index of the first placeholder in query, which is not in a quoted literal
+// or identifier, `--` or `/* */` comment, or -1 if there is no placeholder. `??` is not a placeholder,
+// it is an escaped literal `?`, e.g. of PostgreSQL JSON operators `??|` and `??&`
+func placeholderIndex(query string, d Dialect) int {
+	for i := 0; i < len(query); i++ {
+		if end := skipLiteral(query, i, d); end > i {
+			i = end - 1
+			continue
+		}
+		if query[i] == placeholder[0] {
+			if i+1 < len(query) && query[i+1] == placeholder[0] {
+				i++
+				continue
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// unescapePlaceholders replaces escaped placeholders `??` with `?`, except in quoted literals and comments
+func unescapePlaceholders(query string, d Dialect) string {
+	if !strings.Contains(query, placeholder+placeholder) {
+		return query
+	}
+	buf := make([]byte, 0, len(query))
+	for i := 0; i < len(query); i++ {
+		if end := skipLiteral(query, i, d); end > i {
+			buf = append(buf, query[i:end]...)
+			i = end - 1
+			continue
+		}
+		buf = append(buf, query[i])
+		if query[i] == placeholder[0] && i+1 < len(query) && query[i+1] == placeholder[0] {
+			i++
+		}
+	}
+	return string(buf)
+}
+
+// skipLiteral returns index after a quoted literal or identifier, `--` or `/* */` comment starting at i,
+// or i if there is none. Unterminated ones last till the end of query. Doubled quotes are scanned
+// as adjacent literals, backslash escapes a quote of a string only if d.BackslashEscapes, e.g. in MySQL,
+// or in PostgreSQL escape strings like E'it\'s'
+func skipLiteral(query string, i int, d Dialect) int {
+	switch c := query[i]; c {
+	case '\'', '"', '`':
+		backslash := c != '`' && d.BackslashEscapes() || c == '\'' && isEscapeString(query, i)
+		for j := i + 1; j < len(query); j++ {
+			switch query[j] {
+			case '\\':
+				if backslash {
+					j++
+				}
+			case c:
+				return j + 1
+			}
+		}
+		return len(query)
+	case '-':
+		if strings.HasPrefix(query[i:], "--") {
+			if end := strings.IndexByte(query[i:], '\n'); end != -1 {
+				return i + end
+			}
+			return len(query)
+		}
+	case '/':
+		if strings.HasPrefix(query[i:], "/*") {
+			if end := strings.Index(query[i+2:], "*/"); end != -1 {
+				return i + end + 4
+			}
+			return len(query)
+		}
+	}
+	return i
+}
+
+// isEscapeString reports whether a quote at i starts a PostgreSQL escape string, e.g. E'\n'
+func isEscapeString(query string, i int) bool {
+	return i > 0 && (query[i-1] == 'E' || query[i-1] == 'e') && (i == 1 || !isWordByte(query[i-2]))
+}
+
+// countPlaceholders returns the number of placeholders in query found by placeholderIndex
+func countPlaceholders(query string, d Dialect) int {
+	n := 0
+	for {
+		index := placeholderIndex(query, d)
+		if index == -1 {
+			return n
+		}
+		n++
+		query = query[index+len(placeholder):]
+	}
+}
+
 func (i *interpolator) interpolate(query string, value []interface{}) error {
-	if strings.Count(query, placeholder) != len(value) {
+	if countPlaceholders(query, i.Dialect) != len(value) {
 		return ErrPlaceholderCount
 	}
+	query = formatKeywords(query, i.KeywordCase, i.Dialect)
 
 	valueIndex := 0
 
 	for {
-		index := strings.Index(query, placeholder)
+		index := placeholderIndex(query, i.Dialect)
 		if index == -1 {
 			break
 		}
 
-		i.WriteString(query[:index])
-		if _, ok := value[valueIndex].([]byte); ok && i.IgnoreBinary {
-			i.WriteString(i.Placeholder(i.N))
-			i.N++
-			i.WriteValue(value[valueIndex])
+		i.WriteString(unescapePlaceholders(query[:index], i.Dialect))
+		v := value[valueIndex]
+		if s, ok := v.(sensitive); ok && !i.Redact {
+			v = s.value
+		}
+		if _, ok := v.([]byte); ok && i.IgnoreBinary {
+			i.writePlaceholder(v)
 		} else {
-			err := i.encodePlaceholder(value[valueIndex])
+			err := i.encodePlaceholder(v)
 			if err != nil {
 				return err
 			}
@@ -59,11 +196,18 @@ func (i *interpolator) interpolate(query string, value []interface{}) error {
 	}
 
 	// placeholder not found; write remaining query
-	i.WriteString(query)
+	i.WriteString(unescapePlaceholders(query, i.Dialect))
 
 	return nil
 }
 
+// writePlaceholder writes a placeholder of the dialect and adds value to the buffer
+func (i *interpolator) writePlaceholder(value interface{}) {
+	i.WriteString(i.Placeholder(i.N))
+	i.N++
+	i.WriteValue(value)
+}
+
 func (i *interpolator) encodePlaceholder(value interface{}) error {
 	if builder, ok := value.(Builder); ok {
 		pbuf := NewBuffer()
@@ -91,7 +235,75 @@ func (i *interpolator) encodePlaceholder(value interface{}) error {
 		return nil
 	}
 
+	switch v := value.(type) {
+	case Raw:
+		i.WriteString(string(v))
+		return nil
+	case sensitive:
+		if i.Redact {
+			i.WriteString(i.EncodeString(redactedValue))
+			return nil
+		}
+		return i.encodePlaceholder(v.value)
+	}
+
+	value, err := encodeRegisteredType(value)
+	if err != nil {
+		return err
+	}
+	if raw, ok := value.(json.RawMessage); ok {
+		// JSON is text, it must not be encoded as binary
+		if raw == nil {
+			value = nil
+		} else {
+			value = string(raw)
+		}
+	}
+
+	value = underlyingValue(value)
+	if !i.SupportsBool() {
+		value = boolToUInt8(value)
+	}
+
+	if i.UsePlaceholders && !isExpandable(value) {
+		// *big.Rat is not a driver.Valuer, it is bound as text like Decimal
+		if v, ok := value.(*big.Rat); ok {
+			if v == nil {
+				value = nil
+			} else {
+				value = formatRat(v)
+			}
+		}
+		i.writePlaceholder(value)
+		return nil
+	}
+
+	switch v := value.(type) {
+	case Decimal:
+		// unquoted, so it is not parsed as float
+		if v.Rat != nil {
+			i.WriteString(formatRat(v.Rat))
+			return nil
+		}
+	case *Decimal:
+		if v != nil && v.Rat != nil {
+			i.WriteString(formatRat(v.Rat))
+			return nil
+		}
+	case *big.Rat:
+		if v != nil {
+			i.WriteString(formatRat(v))
+			return nil
+		}
+	}
+
 	if valuer, ok := value.(driver.Valuer); ok {
+		// nil pointer to a type with value receiver Value is NULL, like in database/sql,
+		// calling Value on it would panic
+		if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr && v.IsNil() && v.Type().Elem().Implements(typeValuer) {
+			i.WriteString("NULL")
+			return nil
+		}
 		// get driver.Valuer's data
 		var err error
 		value, err = valuer.Value()
@@ -126,10 +338,19 @@ func (i *interpolator) encodePlaceholder(value interface{}) error {
 			i.WriteString(i.EncodeTime(v.Interface().(time.Time)))
 			return nil
 		}
-	case reflect.Slice:
+	case reflect.Slice, reflect.Array:
 		if v.Type().Elem().Kind() == reflect.Uint8 {
-			// []byte
-			i.WriteString(i.EncodeBytes(v.Bytes()))
+			if v.Kind() == reflect.Slice {
+				// []byte
+				i.WriteString(i.EncodeBytes(v.Bytes()))
+				return nil
+			}
+			// [N]byte
+			b := make([]byte, v.Len())
+			for n := range b {
+				b[n] = byte(v.Index(n).Uint())
+			}
+			i.WriteString(i.EncodeBytes(b))
 			return nil
 		}
 		if v.Len() == 0 {
@@ -180,6 +401,78 @@ func (i *interpolator) encodePlaceholder(value interface{}) error {
 	return ErrNotSupported
 }
 
+// underlyingTypes are the types of values of named types with kinds of them, see underlyingValue
+var underlyingTypes = map[reflect.Kind]reflect.Type{
+	reflect.Bool:    reflect.TypeOf(false),
+	reflect.Int:     reflect.TypeOf(int(0)),
+	reflect.Int8:    reflect.TypeOf(int8(0)),
+	reflect.Int16:   reflect.TypeOf(int16(0)),
+	reflect.Int32:   reflect.TypeOf(int32(0)),
+	reflect.Int64:   reflect.TypeOf(int64(0)),
+	reflect.Uint:    reflect.TypeOf(uint(0)),
+	reflect.Uint8:   reflect.TypeOf(uint8(0)),
+	reflect.Uint16:  reflect.TypeOf(uint16(0)),
+	reflect.Uint32:  reflect.TypeOf(uint32(0)),
+	reflect.Uint64:  reflect.TypeOf(uint64(0)),
+	reflect.Float32: reflect.TypeOf(float32(0)),
+	reflect.Float64: reflect.TypeOf(float64(0)),
+	reflect.String:  reflect.TypeOf(""),
+}
+
+// underlyingValue converts a value of named type, e.g. `type Status int` constant, to the type of its kind,
+// so it is encoded like the value of that type and bound as it, some drivers reject named types.
+// Values of types implementing driver.Valuer are returned as they are
+func underlyingValue(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	if _, ok := value.(driver.Valuer); ok {
+		return value
+	}
+	v := reflect.ValueOf(value)
+	if t, ok := underlyingTypes[v.Kind()]; ok && v.Type() != t {
+		return v.Convert(t).Interface()
+	}
+	return value
+}
+
+// boolToUInt8 converts bool and *bool to 1 or 0 of uint8 for dialects without Bool type,
+// so they are bound as UInt8 in ClickHouse, other values are returned as they are
+func boolToUInt8(value interface{}) interface{} {
+	switch v := value.(type) {
+	case bool:
+		if v {
+			return uint8(1)
+		}
+		return uint8(0)
+	case *bool:
+		if v == nil {
+			return nil
+		}
+		return boolToUInt8(*v)
+	}
+	return value
+}
+
+// isExpandable reports whether value is a slice, an array or a map written as `(a,b)`,
+// []byte, [N]byte and driver.Valuer are single values
+func isExpandable(value interface{}) bool {
+	if value == nil {
+		return false
+	}
+	if _, ok := value.(driver.Valuer); ok {
+		return false
+	}
+	t := reflect.TypeOf(value)
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		return t.Elem().Kind() != reflect.Uint8
+	case reflect.Map:
+		return true
+	}
+	return false
+}
+
 type mapKeys []reflect.Value
 
 func (k mapKeys) Len() int {
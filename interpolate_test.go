@@ -1,10 +1,17 @@
 package dbr
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/mailru/dbr/dialect"
 	"github.com/stretchr/testify/assert"
 )
@@ -55,6 +62,157 @@ func TestInterpolateIgnoreBinary(t *testing.T) {
 	}
 }
 
+func TestInterpolateQuotedPlaceholders(t *testing.T) {
+	for _, test := range []struct {
+		query string
+		value []interface{}
+		want  string
+	}{
+		{
+			query: "SELECT '?'",
+			want:  "SELECT '?'",
+		},
+		{
+			query: "SELECT 'why?', \"a?\", `b?` FROM t WHERE c = ?",
+			value: []interface{}{1},
+			want:  "SELECT 'why?', \"a?\", `b?` FROM t WHERE c = 1",
+		},
+		{
+			query: "SELECT 'it''s ?', 'it\\'s ?', ?",
+			value: []interface{}{1},
+			want:  "SELECT 'it''s ?', 'it\\'s ?', 1",
+		},
+		{
+			query: "SELECT a -- is it ?\nFROM t WHERE b = ?",
+			value: []interface{}{2},
+			want:  "SELECT a -- is it ?\nFROM t WHERE b = 2",
+		},
+		{
+			query: "/* why? */ SELECT ? /* and ? */",
+			value: []interface{}{3},
+			want:  "/* why? */ SELECT 3 /* and ? */",
+		},
+		{
+			query: "SELECT a - ?, 4 / ?",
+			value: []interface{}{1, 2},
+			want:  "SELECT a - 1, 4 / 2",
+		},
+	} {
+		query, err := InterpolateForDialect(test.query, test.value, dialect.MySQL)
+		assert.NoError(t, err, test.query)
+		assert.Equal(t, test.want, query)
+	}
+
+	_, err := InterpolateForDialect("SELECT '?', ?", nil, dialect.MySQL)
+	assert.Equal(t, ErrPlaceholderCount, err)
+
+	// comments of select are not interpolated
+	buf := NewBuffer()
+	err = Select("a").From("t").AddComment("is it ok?").Where("b = ?", 1).Build(dialect.MySQL, buf)
+	assert.NoError(t, err)
+	query, err := InterpolateForDialect(buf.String(), buf.Value(), dialect.MySQL)
+	assert.NoError(t, err)
+	assert.Equal(t, "/* is it ok? */SELECT a FROM t WHERE (b = 1)", query)
+}
+
+func TestInterpolateBackslashLiterals(t *testing.T) {
+	// backslash is not an escape in strings of PostgreSQL and SQLite
+	for _, d := range []Dialect{dialect.PostgreSQL, dialect.SQLite3} {
+		query, err := InterpolateForDialect("SELECT 'C:\\', ?", []interface{}{1}, d)
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT 'C:\\', 1", query)
+
+		query, err = InterpolateForDialect("SELECT \"a\\\" ??| ?", []interface{}{2}, d)
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT \"a\\\" ?| 2", query)
+
+		buf := NewBuffer()
+		err = Select("a").From("t").Where("path = 'C:\\' AND b = ?", 1).Build(d, buf)
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT a FROM t WHERE (path = 'C:\\' AND b = ?)", buf.String())
+
+		assert.Equal(t, "select 'C:\\' as a from t where b = ?", formatKeywords("SELECT 'C:\\' AS a FROM t WHERE b = ?", KeywordLower, d))
+	}
+
+	// but it is in E'' strings of PostgreSQL
+	query, err := InterpolateForDialect("SELECT E'it\\'s ?', e'?\\\\', ?", []interface{}{1}, dialect.PostgreSQL)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT E'it\\'s ?', e'?\\\\', 1", query)
+
+	// and in strings of MySQL and ClickHouse
+	for _, d := range []Dialect{dialect.MySQL, dialect.ClickHouse} {
+		_, err := InterpolateForDialect("SELECT 'C:\\', ?", []interface{}{1}, d)
+		assert.Equal(t, ErrPlaceholderCount, err)
+		query, err := InterpolateForDialect("SELECT 'C:\\\\', ?", []interface{}{1}, d)
+		assert.NoError(t, err)
+		assert.Equal(t, "SELECT 'C:\\\\', 1", query)
+	}
+}
+
+func TestInterpolateEscapedPlaceholders(t *testing.T) {
+	for _, test := range []struct {
+		query string
+		value []interface{}
+		want  string
+	}{
+		{
+			query: "data ?? ?",
+			value: []interface{}{"a"},
+			want:  `data ? 'a'`,
+		},
+		{
+			query: "data ??| ?",
+			value: []interface{}{Expr("array['a', 'b']")},
+			want:  `data ?| array['a', 'b']`,
+		},
+		{
+			query: "data ??& array[?, ?]",
+			value: []interface{}{"a", "b"},
+			want:  `data ?& array['a', 'b']`,
+		},
+		{
+			query: "???",
+			value: []interface{}{1},
+			want:  "?1",
+		},
+		{
+			query: "'??' /* ?? */ ??",
+			want:  "'??' /* ?? */ ?",
+		},
+	} {
+		query, err := InterpolateForDialect(test.query, test.value, dialect.PostgreSQL)
+		assert.NoError(t, err, test.query)
+		assert.Equal(t, test.want, query)
+	}
+
+	buf := NewBuffer()
+	err := Select("id").From("docs").
+		Where("data ?? ?", "a").
+		Where(Expr("data ??| ?", Expr("array[?, ?]", "b", "c"))).
+		Where(Or(Expr("data ??& array[?]", "d"), Eq("id", 1))).
+		Build(dialect.PostgreSQL, buf)
+	assert.NoError(t, err)
+	query, err := InterpolateForDialect(buf.String(), buf.Value(), dialect.PostgreSQL)
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT id FROM docs WHERE (data ? 'a') AND (data ?| array['b', 'c']) AND ((data ?& array['d']) OR ("id" = 1))`, query)
+
+	i := interpolator{
+		Buffer:          NewBuffer(),
+		Dialect:         dialect.PostgreSQL,
+		UsePlaceholders: true,
+	}
+	err = i.interpolate(buf.String(), buf.Value())
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT id FROM docs WHERE (data ? $1) AND (data ?| array[$2, $3]) AND ((data ?& array[$4]) OR ("id" = $5))`, i.String())
+
+	buf = NewBuffer()
+	err = SelectBySql("SELECT id FROM docs WHERE data ?? ?", "a").Build(dialect.PostgreSQL, buf)
+	assert.NoError(t, err)
+	query, err = InterpolateForDialect(buf.String(), buf.Value(), dialect.PostgreSQL)
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT id FROM docs WHERE data ? 'a'`, query)
+}
+
 func TestInterpolateForDialect(t *testing.T) {
 	for _, test := range []struct {
 		query string
@@ -148,8 +306,300 @@ func TestInterpolateForDialect(t *testing.T) {
 	}
 }
 
+func TestInterpolateBool(t *testing.T) {
+	for _, test := range []struct {
+		d    Dialect
+		want string
+	}{
+		{d: dialect.MySQL, want: "1 0"},
+		{d: dialect.PostgreSQL, want: "TRUE FALSE"},
+		{d: dialect.SQLite3, want: "1 0"},
+		{d: dialect.ClickHouse, want: "1 0"},
+	} {
+		s, err := InterpolateForDialect("? ?", []interface{}{true, false}, test.d)
+		assert.NoError(t, err)
+		assert.Equal(t, test.want, s)
+	}
+}
+
 // Attempts to test common SQL injection strings. See `InjectionAttempts` for
 // more information on the source and the strings themselves.
+func TestInterpolateUsePlaceholders(t *testing.T) {
+	for _, test := range []struct {
+		d     Dialect
+		query string
+	}{
+		{
+			d:     dialect.PostgreSQL,
+			query: `SELECT a FROM t WHERE ("b" = $1) AND ("c" IN ($2,$3)) AND ("d" = (SELECT d FROM u WHERE ("e" = $4))) AND (f > now()) AND (g = $5) AND ("h" IS NULL)`,
+		},
+		{
+			d:     dialect.MySQL,
+			query: "SELECT a FROM t WHERE (`b` = ?) AND (`c` IN (?,?)) AND (`d` = (SELECT d FROM u WHERE (`e` = ?))) AND (f > now()) AND (g = ?) AND (`h` IS NULL)",
+		},
+	} {
+		buf := NewBuffer()
+		err := Select("a").From("t").
+			Where(Eq("b", "one")).
+			Where(Eq("c", []int{1, 2})).
+			Where(Eq("d", Select("d").From("u").Where(Eq("e", true)))).
+			Where("f > ?", Raw("now()")).
+			Where("g = ?", []byte{1}).
+			Where(Eq("h", nil)).
+			Build(test.d, buf)
+		assert.NoError(t, err)
+
+		i := interpolator{
+			Buffer:          NewBuffer(),
+			Dialect:         test.d,
+			IgnoreBinary:    true,
+			UsePlaceholders: true,
+		}
+		err = i.interpolate(buf.String(), buf.Value())
+		assert.NoError(t, err)
+		assert.Equal(t, test.query, i.String())
+		assert.Equal(t, []interface{}{"one", 1, 2, true, []byte{1}}, i.Value())
+	}
+}
+
+type pointerRecord struct {
+	S *string
+	I *int64
+	F *float64
+	B *bool
+	T *time.Time
+	N *NullString
+	D *Decimal
+}
+
+func TestInterpolateJSONRawMessage(t *testing.T) {
+	type document struct {
+		ID   int64
+		Body json.RawMessage
+	}
+	doc := document{ID: 1, Body: json.RawMessage(`{"tags":["a","b"],"note":"it's"}`)}
+	for _, test := range []struct {
+		d            Dialect
+		query        string
+		placeholders string
+	}{
+		{
+			d:            dialect.PostgreSQL,
+			query:        `INSERT INTO "documents" ("id","body") VALUES (1,'{"tags":["a","b"],"note":"it''s"}'), (2,NULL)`,
+			placeholders: `INSERT INTO "documents" ("id","body") VALUES ($1,$2), ($3,$4)`,
+		},
+		{
+			// the same as a string, not 0x... of binary
+			d:            dialect.MySQL,
+			query:        "INSERT INTO `documents` (`id`,`body`) VALUES (1," + dialect.MySQL.EncodeString(string(doc.Body)) + "), (2,NULL)",
+			placeholders: "INSERT INTO `documents` (`id`,`body`) VALUES (?,?), (?,?)",
+		},
+	} {
+		buf := NewBuffer()
+		err := InsertInto("documents").Columns("id", "body").Record(&doc).Record(&document{ID: 2}).Build(test.d, buf)
+		assert.NoError(t, err)
+		query, err := InterpolateForDialect(buf.String(), buf.Value(), test.d)
+		assert.NoError(t, err)
+		assert.Equal(t, test.query, query)
+
+		i := interpolator{Buffer: NewBuffer(), Dialect: test.d, IgnoreBinary: true, UsePlaceholders: true}
+		err = i.interpolate(buf.String(), buf.Value())
+		assert.NoError(t, err)
+		assert.Equal(t, test.placeholders, i.String())
+		assert.Equal(t, []interface{}{int64(1), string(doc.Body), int64(2), nil}, i.Value())
+	}
+}
+
+type testUUID [16]byte
+
+func (u testUUID) Value() (driver.Value, error) {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:]), nil
+}
+
+func TestInterpolateIn(t *testing.T) {
+	times := []time.Time{
+		time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC),
+		time.Date(2023, 6, 7, 8, 9, 10, 0, time.FixedZone("UTC+3", 3*3600)),
+	}
+	for _, test := range []struct {
+		d     Dialect
+		query string
+	}{
+		{
+			d:     dialect.MySQL,
+			query: "SELECT * FROM t WHERE (`at` IN ('2023-01-02 03:04:05.000000','2023-06-07 05:09:10.000000'))",
+		},
+		{
+			d:     dialect.PostgreSQL,
+			query: `SELECT * FROM t WHERE ("at" IN ('2023-01-02 03:04:05.000000','2023-06-07 05:09:10.000000'))`,
+		},
+		{
+			d:     dialect.SQLite3,
+			query: `SELECT * FROM t WHERE ("at" IN ('2023-01-02 03:04:05.000000','2023-06-07 05:09:10.000000'))`,
+		},
+		{
+			d:     dialect.ClickHouse,
+			query: "SELECT * FROM t WHERE (`at` IN ('2023-01-02 03:04:05','2023-06-07 05:09:10'))",
+		},
+	} {
+		buf := NewBuffer()
+		err := Select("*").From("t").Where(Eq("at", times)).Build(test.d, buf)
+		assert.NoError(t, err)
+		query, err := InterpolateForDialect(buf.String(), buf.Value(), test.d)
+		assert.NoError(t, err)
+		assert.Equal(t, test.query, query)
+	}
+
+	id := testUUID{0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde, 0xf0, 0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde, 0xf0}
+	for _, test := range []struct {
+		cond  Builder
+		query string
+	}{
+		{
+			cond:  Eq("id", []testUUID{id}),
+			query: "`id` IN ('12345678-9abc-def0-1234-56789abcdef0')",
+		},
+		{
+			cond:  Neq("status", [2]string{"new", "done"}),
+			query: "`status` NOT IN ('new','done')",
+		},
+		{
+			cond:  Eq("n", []*int64{nil}),
+			query: "`n` IN (NULL)",
+		},
+		{
+			// binary values are compared as a whole
+			cond:  Eq("hash", []byte{1, 2}),
+			query: "`hash` = 0x0102",
+		},
+		{
+			cond:  Eq("hash", [2]byte{1, 2}),
+			query: "`hash` = 0x0102",
+		},
+		{
+			// a valuer is a single value even if it is an array
+			cond:  Eq("id", id),
+			query: "`id` = '12345678-9abc-def0-1234-56789abcdef0'",
+		},
+	} {
+		buf := NewBuffer()
+		err := test.cond.Build(dialect.MySQL, buf)
+		assert.NoError(t, err)
+		query, err := InterpolateForDialect(buf.String(), buf.Value(), dialect.MySQL)
+		assert.NoError(t, err)
+		assert.Equal(t, test.query, query)
+	}
+}
+
+func TestInterpolatePointers(t *testing.T) {
+	s, i, f, b := "one", int64(1), 1.5, true
+	tm := time.Date(2008, 9, 17, 20, 4, 26, 0, time.UTC)
+	n := NewNullString("two")
+	d, err := NewDecimal("0.25")
+	assert.NoError(t, err)
+	filled := pointerRecord{S: &s, I: &i, F: &f, B: &b, T: &tm, N: &n, D: &d}
+
+	for _, test := range []struct {
+		d      Dialect
+		filled string
+	}{
+		{
+			d:      dialect.MySQL,
+			filled: "INSERT INTO `table` (`s`,`i`,`f`,`b`,`t`,`n`,`d`) VALUES ('one',1,1.5,1,'2008-09-17 20:04:26.000000','two',0.25)",
+		},
+		{
+			d:      dialect.PostgreSQL,
+			filled: `INSERT INTO "table" ("s","i","f","b","t","n","d") VALUES ('one',1,1.5,TRUE,'2008-09-17 20:04:26.000000','two',0.25)`,
+		},
+		{
+			d:      dialect.SQLite3,
+			filled: `INSERT INTO "table" ("s","i","f","b","t","n","d") VALUES ('one',1,1.5,1,'2008-09-17 20:04:26.000000','two',0.25)`,
+		},
+		{
+			d:      dialect.ClickHouse,
+			filled: "INSERT INTO `table` (`s`,`i`,`f`,`b`,`t`,`n`,`d`) VALUES ('one',1,1.5,1,'2008-09-17 20:04:26','two',0.25)",
+		},
+	} {
+		for _, rec := range []pointerRecord{{}, filled} {
+			buf := NewBuffer()
+			err := InsertInto("table").Record(rec).Build(test.d, buf)
+			assert.NoError(t, err)
+			query, err := InterpolateForDialect(buf.String(), buf.Value(), test.d)
+			assert.NoError(t, err)
+			if rec.S == nil {
+				assert.Contains(t, query, "VALUES (NULL,NULL,NULL,NULL,NULL,NULL,NULL)")
+			} else {
+				assert.Equal(t, test.filled, query)
+			}
+		}
+	}
+}
+
+type testColor int
+
+func (c testColor) String() string {
+	return [...]string{"red", "green", "it's blue"}[c]
+}
+
+type testValuerColor int
+
+func (c testValuerColor) Value() (driver.Value, error) {
+	return int64(c) * 10, nil
+}
+
+func TestRegisterType(t *testing.T) {
+	stringer := func(v interface{}) (string, error) {
+		return v.(fmt.Stringer).String(), nil
+	}
+	RegisterType(reflect.TypeOf(testColor(0)), stringer)
+	RegisterType(reflect.TypeOf(testValuerColor(0)), stringer)
+	defer RegisterType(reflect.TypeOf(testColor(0)), nil)
+	defer RegisterType(reflect.TypeOf(testValuerColor(0)), nil)
+
+	color := testColor(2)
+	query, err := InterpolateForDialect("? ? ? ?", []interface{}{testColor(1), []testColor{0, 2}, &color, testValuerColor(1)}, dialect.PostgreSQL)
+	assert.NoError(t, err)
+	// driver.Valuer is preferred to registered function
+	assert.Equal(t, `'green' ('red','it''s blue') 'it''s blue' 10`, query)
+
+	i := interpolator{
+		Buffer:          NewBuffer(),
+		Dialect:         dialect.PostgreSQL,
+		UsePlaceholders: true,
+	}
+	err = i.interpolate("?", []interface{}{testColor(1)})
+	assert.NoError(t, err)
+	assert.Equal(t, "$1", i.String())
+	assert.Equal(t, []interface{}{"green"}, i.Value())
+
+	boom := errors.New("boom")
+	RegisterType(reflect.TypeOf(testColor(0)), func(v interface{}) (string, error) {
+		return "", boom
+	})
+	_, err = InterpolateForDialect("?", []interface{}{testColor(1)}, dialect.PostgreSQL)
+	assert.Equal(t, boom, err)
+
+	RegisterType(reflect.TypeOf(testColor(0)), nil)
+	query, err = InterpolateForDialect("?", []interface{}{testColor(1)}, dialect.PostgreSQL)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", query)
+
+	// registry is safe for concurrent use
+	var wg sync.WaitGroup
+	for n := 0; n < 8; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := 0; k < 100; k++ {
+				RegisterType(reflect.TypeOf(testColor(0)), stringer)
+				_, err := InterpolateForDialect("?", []interface{}{testColor(0)}, dialect.MySQL)
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
 func TestCommonSQLInjections(t *testing.T) {
 	for _, sess := range testSession {
 		for _, injectionAttempt := range strings.Split(injectionAttempts, "\n") {
@@ -299,3 +749,109 @@ x' or 1=1 or 'x'='y
 //*
 */*
 `
+
+func TestClickHouseBool(t *testing.T) {
+	active := false
+	buf := NewBuffer()
+	err := Select("id").From("users").Where(Eq("active", true)).Where(Eq("deleted", &active)).Build(dialect.ClickHouse, buf)
+	assert.NoError(t, err)
+
+	query, err := InterpolateForDialect(buf.String(), buf.Value(), dialect.ClickHouse)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE (`active` = 1) AND (`deleted` = 0)", query)
+
+	i := interpolator{
+		Buffer:          NewBuffer(),
+		Dialect:         dialect.ClickHouse,
+		IgnoreBinary:    true,
+		UsePlaceholders: true,
+	}
+	err = i.interpolate(buf.String(), buf.Value())
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users WHERE (`active` = ?) AND (`deleted` = ?)", i.String())
+	assert.Equal(t, []interface{}{uint8(1), uint8(0)}, i.Value())
+
+	// UInt8 columns are loaded into bools
+	db, dbmock, err := sqlmock.New()
+	assert.NoError(t, err)
+	conn := &Connection{DB: db, Dialect: dialect.ClickHouse, EventReceiver: nullReceiver}
+	dbmock.ExpectQuery("SELECT active, deleted, verified FROM users").
+		WillReturnRows(sqlmock.NewRows([]string{"active", "deleted", "verified"}).AddRow(uint8(1), uint8(0), nil))
+	var user struct {
+		Active   bool
+		Deleted  *bool
+		Verified NullBool
+	}
+	err = conn.NewSession(nil).Select("active", "deleted", "verified").From("users").LoadStruct(&user)
+	assert.NoError(t, err)
+	assert.True(t, user.Active)
+	assert.Equal(t, &active, user.Deleted)
+	assert.False(t, user.Verified.Valid)
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+}
+
+type (
+	testStatus    int8
+	testColorName string
+	testRatio     float32
+	testFlag      bool
+	testQuantity  uint16
+)
+
+func TestInterpolateNamedTypes(t *testing.T) {
+	const (
+		statusActive testStatus    = 2
+		colorRed     testColorName = "red"
+	)
+	stmt := Select("id").From("items").
+		Where(Eq("status", statusActive)).
+		Where(Eq("color", []testColorName{colorRed, "blue"})).
+		Where(Gt("ratio", testRatio(0.5))).
+		Where(Eq("visible", testFlag(true))).
+		Where(Lt("quantity", testQuantity(10)))
+	for _, test := range []struct {
+		d     Dialect
+		query string
+	}{
+		{d: dialect.MySQL, query: "SELECT id FROM items WHERE (`status` = 2) AND (`color` IN ('red','blue')) AND (`ratio` > 0.5) AND (`visible` = 1) AND (`quantity` < 10)"},
+		{d: dialect.PostgreSQL, query: `SELECT id FROM items WHERE ("status" = 2) AND ("color" IN ('red','blue')) AND ("ratio" > 0.5) AND ("visible" = TRUE) AND ("quantity" < 10)`},
+		{d: dialect.SQLite3, query: `SELECT id FROM items WHERE ("status" = 2) AND ("color" IN ('red','blue')) AND ("ratio" > 0.5) AND ("visible" = 1) AND ("quantity" < 10)`},
+		{d: dialect.ClickHouse, query: "SELECT id FROM items WHERE (`status` = 2) AND (`color` IN ('red','blue')) AND (`ratio` > 0.5) AND (`visible` = 1) AND (`quantity` < 10)"},
+	} {
+		buf := NewBuffer()
+		err := stmt.Build(test.d, buf)
+		assert.NoError(t, err)
+		query, err := InterpolateForDialect(buf.String(), buf.Value(), test.d)
+		assert.NoError(t, err)
+		assert.Equal(t, test.query, query)
+	}
+
+	// bound values have the types of their kinds
+	buf := NewBuffer()
+	err := stmt.Build(dialect.PostgreSQL, buf)
+	assert.NoError(t, err)
+	i := interpolator{
+		Buffer:          NewBuffer(),
+		Dialect:         dialect.PostgreSQL,
+		IgnoreBinary:    true,
+		UsePlaceholders: true,
+	}
+	err = i.interpolate(buf.String(), buf.Value())
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT id FROM items WHERE ("status" = $1) AND ("color" IN ($2,$3)) AND ("ratio" > $4) AND ("visible" = $5) AND ("quantity" < $6)`, i.String())
+	assert.Equal(t, []interface{}{int8(2), "red", "blue", float32(0.5), true, uint16(10)}, i.Value())
+
+	buf = NewBuffer()
+	err = stmt.Build(dialect.ClickHouse, buf)
+	assert.NoError(t, err)
+	i = interpolator{
+		Buffer:          NewBuffer(),
+		Dialect:         dialect.ClickHouse,
+		IgnoreBinary:    true,
+		UsePlaceholders: true,
+	}
+	err = i.interpolate(buf.String(), buf.Value())
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM items WHERE (`status` = ?) AND (`color` IN (?,?)) AND (`ratio` > ?) AND (`visible` = ?) AND (`quantity` < ?)", i.String())
+	assert.Equal(t, []interface{}{int8(2), "red", "blue", float32(0.5), uint8(1), uint16(10)}, i.Value())
+}
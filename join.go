@@ -7,26 +7,89 @@ const (
 	left
 	right
 	full
+	cross
 )
 
+func writeJoinTable(t joinType, table interface{}, d Dialect, buf Buffer) {
+	writeJoin(t, buf)
+	switch table := table.(type) {
+	case string:
+		buf.WriteString(d.QuoteIdent(table))
+	default:
+		buf.WriteString(placeholder)
+		buf.WriteValue(table)
+	}
+}
+
+// writeJoin writes ` LEFT JOIN ` for join type
+func writeJoin(t joinType, buf Buffer) {
+	buf.WriteString(" ")
+	switch t {
+	case left:
+		buf.WriteString("LEFT ")
+	case right:
+		buf.WriteString("RIGHT ")
+	case full:
+		buf.WriteString("FULL ")
+	case cross:
+		buf.WriteString("CROSS ")
+	}
+	buf.WriteString("JOIN ")
+}
+
 func join(t joinType, table, on interface{}) Builder {
 	return BuildFunc(func(d Dialect, buf Buffer) error {
-		buf.WriteString(" ")
-		switch t {
-		case left:
-			buf.WriteString("LEFT ")
-		case right:
-			buf.WriteString("RIGHT ")
-		case full:
-			buf.WriteString("FULL ")
-		}
-		buf.WriteString("JOIN ")
-		switch table := table.(type) {
+		writeJoinTable(t, table, d, buf)
+		buf.WriteString(" ON ")
+		switch on := on.(type) {
 		case string:
-			buf.WriteString(d.QuoteIdent(table))
-		default:
+			buf.WriteString(on)
+		case Builder:
 			buf.WriteString(placeholder)
-			buf.WriteValue(table)
+			buf.WriteValue(on)
+		}
+		return nil
+	})
+}
+
+// joinUsing joins table on equality of columns with the same name, all dialects support it
+func joinUsing(t joinType, table interface{}, column []string) Builder {
+	return BuildFunc(func(d Dialect, buf Buffer) error {
+		if len(column) == 0 {
+			return ErrColumnNotSpecified
+		}
+		writeJoinTable(t, table, d, buf)
+		buf.WriteString(" USING (")
+		for i, col := range column {
+			if col == "" {
+				return ErrColumnNotSpecified
+			}
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(d.QuoteIdent(col))
+		}
+		buf.WriteString(")")
+		return nil
+	})
+}
+
+// joinLateral joins subquery which may reference columns of preceding tables,
+// e.g. `LEFT JOIN LATERAL (SELECT ...) AS "alias" ON true`, cross join has no condition
+func joinLateral(t joinType, subquery Builder, alias string, on interface{}) Builder {
+	return BuildFunc(func(d Dialect, buf Buffer) error {
+		if !d.SupportsLateralJoin() {
+			return ErrLateralJoinNotSupported
+		}
+		writeJoin(t, buf)
+		buf.WriteString("LATERAL (")
+		if err := subquery.Build(d, buf); err != nil {
+			return err
+		}
+		buf.WriteString(") AS ")
+		buf.WriteString(d.QuoteIdent(alias))
+		if t == cross {
+			return nil
 		}
 		buf.WriteString(" ON ")
 		switch on := on.(type) {
@@ -0,0 +1,89 @@
+package dbr
+
+import "strings"
+
+// KeywordCase is the case of SQL keywords in queries of a session
+type KeywordCase int
+
+// cases of SQL keywords
+const (
+	// KeywordCaseAsIs keeps keywords as they are written: builders write them in uppercase
+	KeywordCaseAsIs KeywordCase = iota
+	// KeywordUpper writes keywords in uppercase, including keywords of Expr
+	KeywordUpper
+	// KeywordLower writes keywords in lowercase, including keywords of Expr
+	KeywordLower
+)
+
+// keywords are reserved words changed by KeywordCase, words which are likely to be
+// unquoted identifiers or case-sensitive functions, e.g. KEY or IF, are not changed
+var keywords = map[string]bool{}
+
+func init() {
+	for _, keyword := range strings.Fields(`
+		ALL ANALYZE AND AS ASC BETWEEN BY CASCADE CASE CONFLICT CREATE CROSS DELETE DESC DISTINCT DO DROP
+		DUPLICATE ELSE END EXISTS EXPLAIN FALSE FOR FOREIGN FROM FULL GROUP HAVING ILIKE IN INNER INSERT
+		INTERVAL INTO IS JOIN LEFT LIKE LIMIT LOCKED MATCHED MERGE NOT NOTHING NULL OFFSET ON OR ORDER
+		OUTER PREWHERE PRIMARY REFERENCES RETURNING RIGHT SELECT SET SETTINGS SKIP TABLE THEN TOTALS
+		TRUE UNION UPDATE USING VALUES WHEN WHERE WITH`) {
+		keywords[keyword] = true
+	}
+}
+
+// formatKeywords changes case of keywords in query before values are interpolated,
+// quoted literals and identifiers and comments are untouched
+func formatKeywords(query string, c KeywordCase, d Dialect) string {
+	if c != KeywordUpper && c != KeywordLower {
+		return query
+	}
+	var buf []byte
+	for i := 0; i < len(query); {
+		if end := skipLiteral(query, i, d); end > i {
+			i = end
+			continue
+		}
+		if !isWordByte(query[i]) {
+			i++
+			continue
+		}
+		j := i + 1
+		for j < len(query) && isWordByte(query[j]) {
+			j++
+		}
+		word := query[i:j]
+		if keywords[strings.ToUpper(word)] {
+			if c == KeywordUpper {
+				word = strings.ToUpper(word)
+			} else {
+				word = strings.ToLower(word)
+			}
+			if word != query[i:j] {
+				if buf == nil {
+					buf = []byte(query)
+				}
+				// keywords are ASCII, so the length is the same
+				copy(buf[i:j], word)
+			}
+		}
+		i = j
+	}
+	if buf == nil {
+		return query
+	}
+	return string(buf)
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c == '$' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// keywordCase returns KeywordCase of session or transaction runner
+func keywordCase(runner runner) KeywordCase {
+	switch r := runner.(type) {
+	case *Session:
+		return r.KeywordCase
+	case *Tx:
+		return r.KeywordCase
+	}
+	return KeywordCaseAsIs
+}
@@ -0,0 +1,48 @@
+package dbr
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mailru/dbr/dialect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatKeywords(t *testing.T) {
+	query := "SELECT `Select`, \"from\", count(*) AS n FROM t -- Where\nWHERE (a = 'or') and b IS NOT NULL /* Limit */ GROUP BY c ORDER BY d DESC"
+	assert.Equal(t,
+		"select `Select`, \"from\", count(*) as n from t -- Where\nwhere (a = 'or') and b is not null /* Limit */ group by c order by d desc",
+		formatKeywords(query, KeywordLower, dialect.MySQL))
+	assert.Equal(t,
+		"SELECT `Select`, \"from\", count(*) AS n FROM t -- Where\nWHERE (a = 'or') AND b IS NOT NULL /* Limit */ GROUP BY c ORDER BY d DESC",
+		formatKeywords(query, KeywordUpper, dialect.MySQL))
+	assert.Equal(t, query, formatKeywords(query, KeywordCaseAsIs, dialect.MySQL))
+	// words which contain keywords are untouched
+	assert.Equal(t, "select order_id, from_date, selected from t", formatKeywords("SELECT order_id, from_date, selected FROM t", KeywordLower, dialect.MySQL))
+}
+
+func TestSessionKeywordCase(t *testing.T) {
+	sess, m, _ := newRecordingSessionMock()
+	sess.KeywordCase = KeywordLower
+
+	// values are interpolated after keywords are changed
+	m.ExpectQuery("^select id from `users` where \\(`name` = 'SELECT'\\) limit 1$").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	var id int
+	err := sess.Select("id").From(I("users")).Where(Eq("name", "SELECT")).Limit(1).LoadValue(&id)
+	assert.NoError(t, err)
+
+	m.ExpectBegin()
+	m.ExpectExec("^update `users` set `name` = 'Where' where \\(`id` = 1\\)$").WillReturnResult(sqlmock.NewResult(0, 1))
+	tx, err := sess.NewSession(nil).Begin()
+	assert.NoError(t, err)
+	_, err = tx.Update("users").Set("name", "Where").Where(Eq("id", 1)).Exec()
+	assert.NoError(t, err)
+
+	buf := NewBuffer()
+	i := interpolator{Buffer: buf, Dialect: dialect.PostgreSQL, KeywordCase: KeywordUpper}
+	err = i.interpolate("select ? from t where a in ?", []interface{}{Raw("not_a_keyword"), []string{"and", "or"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT not_a_keyword FROM t WHERE a IN ('and','or')", buf.String())
+	assert.NoError(t, m.ExpectationsWereMet())
+}
@@ -4,12 +4,21 @@ import (
 	"database/sql"
 	"fmt"
 	"reflect"
+	"sync"
 )
 
-// Load loads any value from sql.Rows
+// Load loads any value from sql.Rows, it returns ErrRawBytes if value holds sql.RawBytes,
+// because they are invalid after the rows are closed, use LoadEach instead
 func Load(rows *sql.Rows, value interface{}) (int, error) {
 	defer rows.Close()
+	return load(rows, value, false, false)
+}
 
+// load loads value from rows, if nullRowsAsNil is set, rows with all columns NULL
+// are loaded as nil elements of slice of pointers to structs. If strict is set,
+// it returns an error if a column has no field in the struct instead of skipping the column.
+// Rows are closed by the caller, queryRows cancels the query before closing them on error
+func load(rows *sql.Rows, value interface{}, nullRowsAsNil, strict bool) (int, error) {
 	column, err := rows.Columns()
 	if err != nil {
 		return 0, err
@@ -28,6 +37,18 @@ func Load(rows *sql.Rows, value interface{}) (int, error) {
 	} else {
 		elemType = v.Type()
 	}
+	if hasRawBytes(elemType) {
+		return 0, ErrRawBytes
+	}
+	if strict {
+		if err := checkStructColumns(column, elemType); err != nil {
+			return 0, err
+		}
+	}
+	lc := lowCardinalityColumns(rows)
+	if isSlice && nullRowsAsNil && elemType.Kind() == reflect.Ptr && elemType.Elem().Kind() == reflect.Struct {
+		return loadNullRowsAsNil(rows, column, lc, v)
+	}
 	extractor, err := findExtractor(elemType)
 	if err != nil {
 		return count, err
@@ -35,25 +56,207 @@ func Load(rows *sql.Rows, value interface{}) (int, error) {
 	for rows.Next() {
 		var elem reflect.Value
 		if isSlice {
-			elem = reflect.New(v.Type().Elem()).Elem()
+			elem = appendZero(v)
 		} else {
 			elem = v
 		}
 		ptr := extractor(column, elem)
+		wrapLowCardinality(ptr, lc)
 		err = rows.Scan(ptr...)
 		if err != nil {
+			if isSlice {
+				v.SetLen(v.Len() - 1)
+			}
 			return count, err
 		}
 		count++
-		if isSlice {
-			v.Set(reflect.Append(v, elem))
-		} else {
+		if !isSlice {
 			break
 		}
 	}
 	return count, rows.Err()
 }
 
+// appendZero appends a zero element to slice v and returns it, the spare capacity of v
+// is reused, so loading into a slice truncated to zero length does not allocate it again
+func appendZero(v reflect.Value) reflect.Value {
+	n := v.Len()
+	if n < v.Cap() {
+		v.SetLen(n + 1)
+		elem := v.Index(n)
+		// the element may hold a previous value, e.g. a pointer, which must not be reused
+		elem.Set(reflect.Zero(elem.Type()))
+		return elem
+	}
+	v.Set(reflect.Append(v, reflect.Zero(v.Type().Elem())))
+	return v.Index(n)
+}
+
+// loadNullRowsAsNil loads rows into slice of pointers to structs, each column is scanned into a pointer,
+// so NULL does not fail scanning into non-nullable fields, and rows with all columns NULL are appended as nil
+func loadNullRowsAsNil(rows *sql.Rows, column []string, lc []bool, v reflect.Value) (int, error) {
+	t := v.Type().Elem().Elem()
+	mapping := structMap(t)
+	count := 0
+	for rows.Next() {
+		ptr := make([]interface{}, len(column))
+		for i, key := range column {
+			if index, ok := mapping[key]; ok {
+				ptr[i] = reflect.New(reflect.PtrTo(t.FieldByIndex(index).Type)).Interface()
+			} else {
+				ptr[i] = dummyDest
+			}
+		}
+		// the destinations of ptr are read below, so the wrapped ones are scanned from a copy
+		dest := append([]interface{}(nil), ptr...)
+		wrapLowCardinality(dest, lc)
+		err := rows.Scan(dest...)
+		if err != nil {
+			return count, err
+		}
+		count++
+		elem := reflect.New(t)
+		isNull := true
+		for i, key := range column {
+			index, ok := mapping[key]
+			if !ok {
+				continue
+			}
+			field := reflect.ValueOf(ptr[i]).Elem()
+			if field.IsNil() {
+				continue
+			}
+			isNull = false
+			elem.Elem().FieldByIndex(index).Set(field.Elem())
+		}
+		if isNull {
+			elem = reflect.Zero(elem.Type())
+		}
+		v.Set(reflect.Append(v, elem))
+	}
+	return count, rows.Err()
+}
+
+// LoadEach loads rows of sql.Rows one by one into value and calls fn after each of them.
+// Value may hold sql.RawBytes, they are scanned without a copy and valid only until fn returns
+func LoadEach(rows *sql.Rows, value interface{}, fn func() error) (int, error) {
+	defer rows.Close()
+	return loadEach(rows, value, fn)
+}
+
+// loadEach is LoadEach, which does not close rows
+func loadEach(rows *sql.Rows, value interface{}, fn func() error) (int, error) {
+	column, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return 0, ErrInvalidPointer
+	}
+	v = v.Elem()
+	extractor, err := findExtractor(v.Type())
+	if err != nil {
+		return 0, err
+	}
+	lc := lowCardinalityColumns(rows)
+	count := 0
+	for rows.Next() {
+		ptr := extractor(column, v)
+		wrapLowCardinality(ptr, lc)
+		err = rows.Scan(ptr...)
+		if err != nil {
+			return count, err
+		}
+		count++
+		if err := fn(); err != nil {
+			return count, err
+		}
+	}
+	return count, rows.Err()
+}
+
+// loadPooled scans each row into a pointer to struct obtained from pool and calls fn with it, the struct is
+// reset to zero value before scanning and put back into pool after fn returns. It does not close rows
+func loadPooled(rows *sql.Rows, pool *sync.Pool, fn func(value interface{}) error) (int, error) {
+	column, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	lc := lowCardinalityColumns(rows)
+	var (
+		elemType  reflect.Type
+		extractor pointersExtractor
+	)
+	count := 0
+	for rows.Next() {
+		value := pool.Get()
+		v := reflect.ValueOf(value)
+		if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+			return count, ErrInvalidPointer
+		}
+		if v.Type() != elemType {
+			// the type is checked for each value, pool may return values of other types
+			elemType = v.Type()
+			extractor = getStructFieldsExtractor(elemType.Elem())
+		}
+		elem := v.Elem()
+		// a pooled value holds the fields of the previous row
+		elem.Set(reflect.Zero(elem.Type()))
+		ptr := extractor(column, elem)
+		wrapLowCardinality(ptr, lc)
+		if err := rows.Scan(ptr...); err != nil {
+			pool.Put(value)
+			return count, err
+		}
+		count++
+		err := fn(value)
+		pool.Put(value)
+		if err != nil {
+			return count, err
+		}
+	}
+	return count, rows.Err()
+}
+
+// hasRawBytes reports whether t is sql.RawBytes or a struct with sql.RawBytes fields
+func hasRawBytes(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == typeRawBytes {
+		return true
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for _, index := range structMap(t) {
+		if t.FieldByIndex(index).Type == typeRawBytes {
+			return true
+		}
+	}
+	return false
+}
+
+// checkStructColumns returns an error if t is a struct or a pointer to it and
+// a column has no field in it, other types are not checked
+func checkStructColumns(column []string, t reflect.Type) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || reflect.PtrTo(t).Implements(typeScanner) {
+		return nil
+	}
+	mapping := structMap(t)
+	for _, key := range column {
+		if _, ok := mapping[key]; !ok {
+			return fmt.Errorf("dbr: column %q has no field in %v", key, t)
+		}
+	}
+	return nil
+}
+
 type dummyScanner struct{}
 
 func (dummyScanner) Scan(interface{}) error {
@@ -85,6 +288,7 @@ var (
 	dummyDest       sql.Scanner = dummyScanner{}
 	typeScanner                 = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
 	typeKeyValueMap             = reflect.TypeOf(keyValueMap(nil))
+	typeRawBytes                = reflect.TypeOf(sql.RawBytes(nil))
 )
 
 func getStructFieldsExtractor(t reflect.Type) pointersExtractor {
@@ -128,6 +332,9 @@ func dummyExtractor(columns []string, value reflect.Value) []interface{} {
 }
 
 func findExtractor(t reflect.Type) (pointersExtractor, error) {
+	if t == typePlanRow {
+		return planRowExtractor, nil
+	}
 	if reflect.PtrTo(t).Implements(typeScanner) {
 		return dummyExtractor, nil
 	}
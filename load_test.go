@@ -1,8 +1,12 @@
 package dbr
 
 import (
+	"context"
+	"database/sql"
 	"database/sql/driver"
+	"errors"
 	"reflect"
+	"sync"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -39,7 +43,7 @@ func TestLoad(t *testing.T) {
 		rows := sqlmock.NewRows(tc.columns).AddRow(values...)
 		dbmock.ExpectQuery("SELECT .+").WillReturnRows(rows)
 		v := reflect.New(reflect.TypeOf(tc.expected)).Elem().Addr().Interface()
-		session.Select(tc.columns...).From("table").Load(v)
+		session.Select(prepareSelect(tc.columns)...).From("table").Load(v)
 		assert.Equal(t, tc.expected, reflect.Indirect(reflect.ValueOf(v)).Interface())
 	}
 }
@@ -53,12 +57,189 @@ func TestLoadWithBytesValue(t *testing.T) {
 	rows := sqlmock.NewRows(columns).AddRow(values...)
 	dbmock.ExpectQuery("SELECT .+").WillReturnRows(rows)
 	v := reflect.New(reflect.TypeOf(map[string]interface{}(nil))).Elem().Addr().Interface()
-	session.Select(columns...).From("table").Load(v)
+	session.Select(prepareSelect(columns)...).From("table").Load(v)
 	value[0] = byte('a')
 	assert.Equal(t, map[string]interface{}{"fieldname": []byte("fieldvalue")},
 		reflect.Indirect(reflect.ValueOf(v)).Interface())
 }
 
+func TestLoadReadonly(t *testing.T) {
+	session, dbmock := newSessionMock()
+	rows := sqlmock.NewRows([]string{"a", "full_name"}).AddRow(1, "one")
+	dbmock.ExpectQuery("SELECT .+").WillReturnRows(rows)
+	var v readonlyTest
+	err := session.Select("a", "full_name").From("table").LoadStruct(&v)
+	assert.NoError(t, err)
+	assert.Equal(t, readonlyTest{A: 1, FullName: "one"}, v)
+}
+
+func TestLoadBoolFromInteger(t *testing.T) {
+	type boolStruct struct {
+		A bool
+		B bool
+		C NullBool
+	}
+	session, dbmock := newSessionMock()
+	// sqlite3 and mysql return integers, clickhouse returns UInt8
+	rows := sqlmock.NewRows([]string{"a", "b", "c"}).AddRow(int64(1), uint8(0), []byte("1"))
+	dbmock.ExpectQuery("SELECT .+").WillReturnRows(rows)
+	var v boolStruct
+	err := session.Select("a", "b", "c").From("table").LoadStruct(&v)
+	assert.NoError(t, err)
+	assert.Equal(t, boolStruct{A: true, B: false, C: NewNullBool(true)}, v)
+}
+
+func TestLoadValuesReuse(t *testing.T) {
+	session, dbmock := newSessionMock()
+	dbmock.ExpectQuery("SELECT id FROM jobs").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2).AddRow(3))
+	dbmock.ExpectQuery("SELECT id FROM jobs").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	dbmock.ExpectQuery("SELECT id FROM jobs").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5).AddRow(6).AddRow(7).AddRow(8))
+
+	ids := make([]int64, 0, 3)
+	backing := &ids[:1][0]
+	n, err := session.Select("id").From("jobs").LoadValues(&ids)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, []int64{1, 2, 3}, ids)
+	assert.True(t, backing == &ids[0], "backing array is reused")
+
+	// the previous values are dropped, not appended to
+	n, err = session.Select("id").From("jobs").LoadValues(&ids)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []int64{4}, ids)
+	assert.Equal(t, 3, cap(ids))
+	assert.True(t, backing == &ids[0], "backing array is reused")
+
+	// the slice grows when its capacity is exceeded
+	n, err = session.Select("id").From("jobs").LoadValues(&ids)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, n)
+	assert.Equal(t, []int64{5, 6, 7, 8}, ids)
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+
+	// reused pointer elements are not scanned into, so previous results are kept intact
+	dbmock.ExpectQuery("SELECT name FROM jobs").WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("a"))
+	dbmock.ExpectQuery("SELECT name FROM jobs").WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("b"))
+	names := make([]*string, 0, 1)
+	_, err = session.Select("name").From("jobs").LoadValues(&names)
+	assert.NoError(t, err)
+	first := names[0]
+	_, err = session.Select("name").From("jobs").LoadValues(&names)
+	assert.NoError(t, err)
+	assert.Equal(t, "a", *first)
+	assert.Equal(t, "b", *names[0])
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+}
+
+func BenchmarkLoadValuesReuse(b *testing.B) {
+	session, dbmock := newSessionMock()
+	newRows := func() *sqlmock.Rows {
+		rows := sqlmock.NewRows([]string{"id"})
+		for i := 0; i < 100; i++ {
+			rows = rows.AddRow(i)
+		}
+		return rows
+	}
+	b.Run("new", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			dbmock.ExpectQuery("SELECT id FROM jobs").WillReturnRows(newRows())
+			var ids []int64
+			session.Select("id").From("jobs").LoadValues(&ids)
+		}
+	})
+	b.Run("reuse", func(b *testing.B) {
+		b.ReportAllocs()
+		ids := make([]int64, 0, 100)
+		for i := 0; i < b.N; i++ {
+			dbmock.ExpectQuery("SELECT id FROM jobs").WillReturnRows(newRows())
+			session.Select("id").From("jobs").LoadValues(&ids)
+		}
+	})
+}
+
+type pooledUser struct {
+	ID    int64
+	Name  string
+	Score int `db:"-"`
+}
+
+func TestLoadStructsPooled(t *testing.T) {
+	session, dbmock := newSessionMock()
+	allocated := 0
+	pool := &sync.Pool{New: func() interface{} {
+		allocated++
+		return new(pooledUser)
+	}}
+	newRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a").AddRow(2, "b").AddRow(3, "c")
+	}
+
+	dbmock.ExpectQuery("SELECT id, name FROM users").WillReturnRows(newRows())
+	var users []pooledUser
+	n, err := session.Select("id", "name").From("users").LoadStructsPooled(context.Background(), pool, func(value interface{}) error {
+		u := value.(*pooledUser)
+		// fields of the previous row are reset
+		assert.Equal(t, 0, u.Score)
+		u.Score = 10
+		users = append(users, *u)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, []pooledUser{{1, "a", 10}, {2, "b", 10}, {3, "c", 10}}, users)
+	// values are put back after fn returns, the pool may drop them, e.g. on GC
+	assert.True(t, allocated >= 1)
+
+	// an error of fn stops loading
+	stop := errors.New("stop")
+	dbmock.ExpectQuery("SELECT id, name FROM users").WillReturnRows(newRows())
+	calls := 0
+	_, err = session.Select("id", "name").From("users").LoadStructsPooled(context.Background(), pool, func(value interface{}) error {
+		calls++
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, calls)
+
+	dbmock.ExpectQuery("SELECT id, name FROM users").WillReturnRows(newRows())
+	_, err = session.Select("id", "name").From("users").LoadStructsPooled(context.Background(), &sync.Pool{}, func(interface{}) error {
+		return nil
+	})
+	assert.Equal(t, ErrInvalidPointer, err)
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+}
+
+func BenchmarkLoadStructsPooled(b *testing.B) {
+	session, dbmock := newSessionMock()
+	newRows := func() *sqlmock.Rows {
+		rows := sqlmock.NewRows([]string{"id", "name"})
+		for i := 0; i < 100; i++ {
+			rows = rows.AddRow(i, "name")
+		}
+		return rows
+	}
+	b.Run("slice", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			dbmock.ExpectQuery("SELECT id, name FROM users").WillReturnRows(newRows())
+			var users []*pooledUser
+			session.Select("id", "name").From("users").LoadStructs(&users)
+		}
+	})
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		pool := &sync.Pool{New: func() interface{} { return new(pooledUser) }}
+		for i := 0; i < b.N; i++ {
+			dbmock.ExpectQuery("SELECT id, name FROM users").WillReturnRows(newRows())
+			session.Select("id", "name").From("users").LoadStructsPooled(context.Background(), pool, func(interface{}) error {
+				return nil
+			})
+		}
+	})
+}
+
 func BenchmarkLoad(b *testing.B) {
 	session, dbmock := newSessionMock()
 	rows := sqlmock.NewRows([]string{"a", "b", "c"})
@@ -84,6 +265,183 @@ func BenchmarkLoad(b *testing.B) {
 	}
 }
 
+func TestLoadAnonymousStructs(t *testing.T) {
+	session, dbmock := newSessionMock()
+	newRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"user_id", "total", "extra"}).
+			AddRow(1, 10.5, "x").
+			AddRow(2, 0.25, "y")
+	}
+
+	dbmock.ExpectQuery("SELECT .+").WillReturnRows(newRows())
+	var totals []struct {
+		UserID int     `db:"user_id"`
+		Total  float64 `db:"total"`
+	}
+	n, err := session.Select("user_id", "sum(amount) AS total", "'x' AS extra").From("orders").GroupBy("user_id").LoadStructs(&totals)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, 1, totals[0].UserID)
+	assert.Equal(t, 10.5, totals[0].Total)
+	assert.Equal(t, 2, totals[1].UserID)
+	assert.Equal(t, 0.25, totals[1].Total)
+
+	dbmock.ExpectQuery("SELECT .+").WillReturnRows(newRows())
+	var ptrs []*struct {
+		UserID int
+		Total  float64
+	}
+	n, err = session.Select("user_id", "sum(amount) AS total", "'x' AS extra").From("orders").GroupBy("user_id").LoadStructs(&ptrs)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, 2, ptrs[1].UserID)
+	assert.Equal(t, 0.25, ptrs[1].Total)
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+}
+
+func TestLoadSliceOfPointers(t *testing.T) {
+	type order struct {
+		ID     int
+		Amount float64
+		Note   *string
+	}
+	newRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "amount", "note"}).
+			AddRow(1, 10.5, "gift").
+			AddRow(nil, nil, nil).
+			AddRow(3, 0.25, nil)
+	}
+	note := "gift"
+
+	session, dbmock := newSessionMock()
+	dbmock.ExpectQuery("SELECT .+").WillReturnRows(newRows())
+	var ptrs []*order
+	n, err := session.Select("o.id", "o.amount", "o.note").From("users").NullRowsAsNil().LoadStructs(&ptrs)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, []*order{{ID: 1, Amount: 10.5, Note: &note}, nil, {ID: 3, Amount: 0.25}}, ptrs)
+
+	// rows are loaded as is without the option, so NULL fails scanning into int
+	dbmock.ExpectQuery("SELECT .+").WillReturnRows(newRows())
+	ptrs = nil
+	_, err = session.Select("o.id", "o.amount", "o.note").From("users").LoadStructs(&ptrs)
+	assert.Error(t, err)
+
+	// slices of values ignore the option
+	dbmock.ExpectQuery("SELECT .+").WillReturnRows(sqlmock.NewRows([]string{"id", "amount"}).AddRow(1, 10.5).AddRow(2, 0.5))
+	var values []order
+	n, err = session.Select("id", "amount").From("orders").NullRowsAsNil().LoadStructs(&values)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []order{{ID: 1, Amount: 10.5}, {ID: 2, Amount: 0.5}}, values)
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+}
+
+func TestLoadStructStrict(t *testing.T) {
+	type user struct {
+		ID   int
+		Name string
+	}
+	session, dbmock := newSessionMock()
+	dbmock.ExpectQuery("SELECT .+").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a"))
+	var u user
+	err := session.Select("id", "name").From("users").LoadStructStrict(context.Background(), &u)
+	assert.NoError(t, err)
+	assert.Equal(t, user{ID: 1, Name: "a"}, u)
+
+	dbmock.ExpectQuery("SELECT .+").WillReturnRows(sqlmock.NewRows([]string{"id", "nmae"}).AddRow(1, "a"))
+	err = session.Select("id", "nmae").From("users").LoadStructStrict(context.Background(), &u)
+	assert.EqualError(t, err, `dbr: column "nmae" has no field in dbr.user`)
+
+	// the column is skipped without strict mode
+	dbmock.ExpectQuery("SELECT .+").WillReturnRows(sqlmock.NewRows([]string{"id", "nmae"}).AddRow(2, "b"))
+	err = session.Select("id", "nmae").From("users").LoadStruct(&u)
+	assert.NoError(t, err)
+	assert.Equal(t, user{ID: 2, Name: "a"}, u)
+
+	dbmock.ExpectQuery("SELECT .+").WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	err = session.Select("id", "name").From("users").LoadStructStrict(context.Background(), &u)
+	assert.Equal(t, ErrNotFound, err)
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+}
+
+type rawBytesRecord struct {
+	ID      int
+	Payload sql.RawBytes
+}
+
+func TestLoadEachRawBytes(t *testing.T) {
+	session, dbmock := newSessionMock()
+	rows := sqlmock.NewRows([]string{"id", "payload"}).
+		AddRow(1, []byte("one")).
+		AddRow(2, []byte("two"))
+	dbmock.ExpectQuery("SELECT id, payload FROM table").WillReturnRows(rows)
+
+	var r rawBytesRecord
+	var payloads []string
+	n, err := session.Select("id", "payload").From("table").LoadEach(context.Background(), &r, func() error {
+		// r.Payload refers to the memory of driver and it is valid only until fn returns,
+		// so it must be copied to be kept, string conversion copies it here
+		payloads = append(payloads, string(r.Payload))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, 2, r.ID)
+	assert.Equal(t, []string{"one", "two"}, payloads)
+
+	dbmock.ExpectQuery("SELECT id, payload FROM table").WillReturnRows(sqlmock.NewRows([]string{"id", "payload"}).
+		AddRow(1, []byte("one")).
+		AddRow(2, []byte("two")))
+	stop := errors.New("stop")
+	n, err = session.Select("id", "payload").From("table").LoadEach(context.Background(), &r, func() error {
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 0, n)
+
+	// sql.RawBytes are invalid after the rows are closed, so Load refuses them
+	dbmock.ExpectQuery("SELECT id, payload FROM table").WillReturnRows(sqlmock.NewRows([]string{"id", "payload"}).AddRow(1, []byte("one")))
+	var records []rawBytesRecord
+	_, err = session.Select("id", "payload").From("table").LoadStructs(&records)
+	assert.Equal(t, ErrRawBytes, err)
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+}
+
+func BenchmarkLoadEachRawBytes(b *testing.B) {
+	session, dbmock := newSessionMock()
+	payload := make([]byte, 1024)
+	b.Run("bytes", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			rows := sqlmock.NewRows([]string{"id", "payload"})
+			for j := 0; j < 100; j++ {
+				rows = rows.AddRow(j, payload)
+			}
+			dbmock.ExpectQuery("SELECT id, payload FROM table").WillReturnRows(rows)
+			var r struct {
+				ID      int
+				Payload []byte
+			}
+			session.Select("id", "payload").From("table").LoadEach(context.Background(), &r, func() error {
+				return nil
+			})
+		}
+	})
+	b.Run("raw_bytes", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			rows := sqlmock.NewRows([]string{"id", "payload"})
+			for j := 0; j < 100; j++ {
+				rows = rows.AddRow(j, payload)
+			}
+			dbmock.ExpectQuery("SELECT id, payload FROM table").WillReturnRows(rows)
+			var r rawBytesRecord
+			session.Select("id", "payload").From("table").LoadEach(context.Background(), &r, func() error {
+				return nil
+			})
+		}
+	})
+}
+
 func newSessionMock() (SessionRunner, sqlmock.Sqlmock) {
 	db, m, err := sqlmock.New()
 	if err != nil {
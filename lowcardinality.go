@@ -0,0 +1,112 @@
+package dbr
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// lowCardinalityColumns reports which columns hold ClickHouse LowCardinality strings, the driver
+// returns their values as escaped bytes instead of decoding them like String. It is nil if there are none
+func lowCardinalityColumns(rows *sql.Rows) []bool {
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return nil
+	}
+	var lc []bool
+	for i, t := range types {
+		if !isLowCardinalityString(t.DatabaseTypeName()) {
+			continue
+		}
+		if lc == nil {
+			lc = make([]bool, len(types))
+		}
+		lc[i] = true
+	}
+	return lc
+}
+
+// isLowCardinalityString reports whether typ is LowCardinality of String, FixedString or Nullable of them
+func isLowCardinalityString(typ string) bool {
+	base, ok := unwrapType(typ, "LowCardinality")
+	if !ok {
+		return false
+	}
+	if nullable, ok := unwrapType(base, "Nullable"); ok {
+		base = nullable
+	}
+	return base == "String" || strings.HasPrefix(base, "FixedString(")
+}
+
+// unwrapType returns T of `wrapper(T)`
+func unwrapType(typ, wrapper string) (string, bool) {
+	if !strings.HasPrefix(typ, wrapper+"(") || !strings.HasSuffix(typ, ")") {
+		return "", false
+	}
+	return typ[len(wrapper)+1 : len(typ)-1], true
+}
+
+// wrapLowCardinality wraps destinations of LowCardinality columns by lowCardinalityScanner
+func wrapLowCardinality(ptr []interface{}, lc []bool) {
+	for i := range ptr {
+		if i < len(lc) && lc[i] {
+			ptr[i] = lowCardinalityScanner{dest: ptr[i]}
+		}
+	}
+}
+
+// clickhouseUnescaper unescapes values like the driver does for String columns
+var clickhouseUnescaper = strings.NewReplacer(`\\`, `\`, `\'`, `'`)
+
+// lowCardinalityScanner decodes value of LowCardinality column and scans it into dest,
+// which may be a string, []byte, a pointer to them, interface{} or sql.Scanner
+type lowCardinalityScanner struct {
+	dest interface{}
+}
+
+func (s lowCardinalityScanner) Scan(value interface{}) error {
+	if b, ok := value.([]byte); ok {
+		if string(b) == `\N` {
+			// NULL of LowCardinality(Nullable(String)), a string `\N` is escaped as `\\N`
+			value = nil
+		} else {
+			value = clickhouseUnescaper.Replace(string(b))
+		}
+	}
+	switch dest := s.dest.(type) {
+	case sql.Scanner:
+		return dest.Scan(value)
+	case *interface{}:
+		*dest = value
+		return nil
+	}
+
+	v := reflect.ValueOf(s.dest).Elem()
+	if value == nil {
+		if v.Kind() != reflect.Ptr && v.Kind() != reflect.Slice {
+			return fmt.Errorf("dbr: can not scan NULL into %v", v.Type())
+		}
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	str, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("dbr: can not scan %T of LowCardinality column into %v", value, v.Type())
+	}
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	switch {
+	case v.Kind() == reflect.String:
+		v.SetString(str)
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+		v.SetBytes([]byte(str))
+	default:
+		return fmt.Errorf("dbr: can not scan LowCardinality string into %v", v.Type())
+	}
+	return nil
+}
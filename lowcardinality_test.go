@@ -0,0 +1,137 @@
+package dbr
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/mailru/dbr/dialect"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLowCardinalityScanner(t *testing.T) {
+	for _, typ := range []string{"LowCardinality(String)", "LowCardinality(Nullable(String))", "LowCardinality(FixedString(2))"} {
+		assert.True(t, isLowCardinalityString(typ), typ)
+	}
+	for _, typ := range []string{"String", "LowCardinality(UInt32)", "Nullable(String)", "Array(LowCardinality(String))"} {
+		assert.False(t, isLowCardinalityString(typ), typ)
+	}
+
+	var s string
+	assert.NoError(t, lowCardinalityScanner{dest: &s}.Scan([]byte(`it\'s a \\N`)))
+	assert.Equal(t, `it's a \N`, s)
+
+	var p *string
+	assert.NoError(t, lowCardinalityScanner{dest: &p}.Scan([]byte("ru")))
+	assert.Equal(t, "ru", *p)
+	assert.NoError(t, lowCardinalityScanner{dest: &p}.Scan([]byte(`\N`)))
+	assert.Nil(t, p)
+
+	var ns NullString
+	assert.NoError(t, lowCardinalityScanner{dest: &ns}.Scan([]byte("en")))
+	assert.Equal(t, "en", ns.String)
+	assert.True(t, ns.Valid)
+
+	var n int
+	assert.EqualError(t, lowCardinalityScanner{dest: &n}.Scan([]byte("en")), "dbr: can not scan LowCardinality string into int")
+	assert.EqualError(t, lowCardinalityScanner{dest: &s}.Scan([]byte(`\N`)), "dbr: can not scan NULL into string")
+}
+
+func TestLowCardinality(t *testing.T) {
+	sess := clickhouseSession
+	for _, query := range []string{
+		"DROP TABLE IF EXISTS dbr_low_cardinality",
+		"CREATE TABLE dbr_low_cardinality (id Int32, lang LowCardinality(String), country LowCardinality(Nullable(String))) Engine=Memory",
+	} {
+		_, err := sess.Exec(query)
+		assert.NoError(t, err)
+	}
+
+	type row struct {
+		ID      int32
+		Lang    string
+		Country *string
+	}
+	country := "it's"
+	_, err := sess.InsertInto("dbr_low_cardinality").Columns("id", "lang", "country").
+		Record(&row{ID: 1, Lang: `e\n`, Country: &country}).
+		Record(&row{ID: 2, Lang: "ru"}).
+		Exec()
+	assert.NoError(t, err)
+
+	var rows []row
+	n, err := sess.Select("id", "lang", "country").From("dbr_low_cardinality").OrderAsc("id").Load(&rows)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []row{{ID: 1, Lang: `e\n`, Country: &country}, {ID: 2, Lang: "ru"}}, rows)
+
+	var lang []string
+	_, err = sess.Select("lang").From("dbr_low_cardinality").OrderAsc("id").Load(&lang)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`e\n`, "ru"}, lang)
+}
+
+// lowCardinalityTestRows returns rows with ClickHouse column types, as the driver does for LowCardinality
+type lowCardinalityTestRows struct {
+	column []string
+	types  []string
+	rows   [][]driver.Value
+}
+
+func (r *lowCardinalityTestRows) Columns() []string { return r.column }
+func (r *lowCardinalityTestRows) Close() error      { return nil }
+
+func (r *lowCardinalityTestRows) ColumnTypeDatabaseTypeName(i int) string { return r.types[i] }
+
+func (r *lowCardinalityTestRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+type lowCardinalityTestConn struct {
+	rows *lowCardinalityTestRows
+}
+
+func (c lowCardinalityTestConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+func (c lowCardinalityTestConn) Close() error              { return nil }
+func (c lowCardinalityTestConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+func (c lowCardinalityTestConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.rows, nil
+}
+
+func (c lowCardinalityTestConn) Connect(context.Context) (driver.Conn, error) { return c, nil }
+func (c lowCardinalityTestConn) Driver() driver.Driver                        { return nil }
+
+func TestLowCardinalityNullRowsAsNil(t *testing.T) {
+	rows := &lowCardinalityTestRows{
+		column: []string{"id", "country"},
+		types:  []string{"Nullable(Int32)", "LowCardinality(Nullable(String))"},
+		rows: [][]driver.Value{
+			{int64(1), []byte(`it\'s`)},
+			{nil, []byte(`\N`)},
+			{int64(3), []byte(`\N`)},
+		},
+	}
+	conn := Connection{DB: sql.OpenDB(lowCardinalityTestConn{rows: rows}), Dialect: dialect.ClickHouse, EventReceiver: nullReceiver}
+
+	type row struct {
+		ID      int32
+		Country string
+	}
+	var value []*row
+	n, err := conn.NewSession(nil).Select("id", "country").From("t").NullRowsAsNil().Load(&value)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, []*row{{ID: 1, Country: "it's"}, nil, {ID: 3}}, value)
+}
@@ -0,0 +1,140 @@
+package dbr
+
+// MergeStmt builds `MERGE INTO ...`
+type MergeStmt interface {
+	Builder
+	Using(source interface{}) MergeStmt
+	On(query interface{}, value ...interface{}) MergeStmt
+	WhenMatched(set map[string]interface{}) MergeStmt
+	WhenMatchedDelete() MergeStmt
+	WhenNotMatched(value map[string]interface{}) MergeStmt
+}
+
+type mergeStmt struct {
+	Table  string
+	Source interface{}
+	OnCond []Builder
+	When   []Builder
+}
+
+// Build builds `MERGE INTO ...` in dialect
+func (b *mergeStmt) Build(d Dialect, buf Buffer) error {
+	if !d.SupportsMerge() {
+		return ErrMergeNotSupported
+	}
+	if b.Table == "" || b.Source == nil {
+		return ErrTableNotSpecified
+	}
+	if len(b.OnCond) == 0 || len(b.When) == 0 {
+		return ErrColumnNotSpecified
+	}
+
+	buf.WriteString("MERGE INTO ")
+	buf.WriteString(d.QuoteIdent(b.Table))
+	buf.WriteString(" USING ")
+	switch source := b.Source.(type) {
+	case string:
+		buf.WriteString(d.QuoteIdent(source))
+	default:
+		buf.WriteString(placeholder)
+		buf.WriteValue(source)
+	}
+
+	buf.WriteString(" ON ")
+	err := And(b.OnCond...).Build(d, buf)
+	if err != nil {
+		return err
+	}
+
+	for _, when := range b.When {
+		err := when.Build(d, buf)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MergeInto creates a MergeStmt, MERGE is supported by PostgreSQL 15+ only
+func MergeInto(table string) MergeStmt {
+	return createMergeStmt(table)
+}
+
+func createMergeStmt(table string) *mergeStmt {
+	return &mergeStmt{
+		Table: table,
+	}
+}
+
+// Using specifies source of rows, a table name or a subquery, e.g. As(Select(...), "s")
+func (b *mergeStmt) Using(source interface{}) MergeStmt {
+	b.Source = source
+	return b
+}
+
+// On adds a condition matching source rows with target rows
+func (b *mergeStmt) On(query interface{}, value ...interface{}) MergeStmt {
+	switch query := query.(type) {
+	case string:
+		b.OnCond = append(b.OnCond, Expr(query, value...))
+	case Builder:
+		b.OnCond = append(b.OnCond, query)
+	}
+	return b
+}
+
+// WhenMatched updates matched target rows with ` WHEN MATCHED THEN UPDATE SET a = ?`,
+// values may reference source columns, e.g. I("s.name")
+func (b *mergeStmt) WhenMatched(set map[string]interface{}) MergeStmt {
+	b.When = append(b.When, BuildFunc(func(d Dialect, buf Buffer) error {
+		if len(set) == 0 {
+			return ErrColumnNotSpecified
+		}
+		buf.WriteString(" WHEN MATCHED THEN UPDATE SET ")
+		for i, col := range sortedKeys(set) {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(d.QuoteIdent(col))
+			buf.WriteString(" = ")
+			buf.WriteString(placeholder)
+			buf.WriteValue(set[col])
+		}
+		return nil
+	}))
+	return b
+}
+
+// WhenMatchedDelete deletes matched target rows with ` WHEN MATCHED THEN DELETE`
+func (b *mergeStmt) WhenMatchedDelete() MergeStmt {
+	b.When = append(b.When, Expr(" WHEN MATCHED THEN DELETE"))
+	return b
+}
+
+// WhenNotMatched inserts source rows without a match with ` WHEN NOT MATCHED THEN INSERT (a) VALUES (?)`
+func (b *mergeStmt) WhenNotMatched(value map[string]interface{}) MergeStmt {
+	b.When = append(b.When, BuildFunc(func(d Dialect, buf Buffer) error {
+		if len(value) == 0 {
+			return ErrColumnNotSpecified
+		}
+		keys := sortedKeys(value)
+		buf.WriteString(" WHEN NOT MATCHED THEN INSERT (")
+		for i, col := range keys {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			buf.WriteString(d.QuoteIdent(col))
+		}
+		buf.WriteString(") VALUES (")
+		for i, col := range keys {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			buf.WriteString(placeholder)
+			buf.WriteValue(value[col])
+		}
+		buf.WriteString(")")
+		return nil
+	}))
+	return b
+}
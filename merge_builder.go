@@ -0,0 +1,104 @@
+package dbr
+
+import (
+	"context"
+	"database/sql"
+)
+
+// MergeBuilder builds "MERGE ..." stmt
+type MergeBuilder interface {
+	Builder
+	EventReceiver
+	Executer
+
+	Using(source interface{}) MergeBuilder
+	On(query interface{}, value ...interface{}) MergeBuilder
+	WhenMatched(set map[string]interface{}) MergeBuilder
+	WhenMatchedDelete() MergeBuilder
+	WhenNotMatched(value map[string]interface{}) MergeBuilder
+	WithEventKv(key, value string) MergeBuilder
+}
+
+type mergeBuilder struct {
+	runner
+	EventReceiver
+
+	Dialect   Dialect
+	mergeStmt *mergeStmt
+	eventKvs  kvs
+}
+
+// MergeInto creates a MergeBuilder, MERGE is supported by PostgreSQL 15+ only
+func (sess *Session) MergeInto(table string) MergeBuilder {
+	return &mergeBuilder{
+		runner:        sess,
+		EventReceiver: sess.EventReceiver,
+		Dialect:       sess.Dialect,
+		mergeStmt:     createMergeStmt(table),
+	}
+}
+
+// MergeInto creates a MergeBuilder, MERGE is supported by PostgreSQL 15+ only
+func (tx *Tx) MergeInto(table string) MergeBuilder {
+	return &mergeBuilder{
+		runner:        tx,
+		EventReceiver: tx.EventReceiver,
+		Dialect:       tx.Dialect,
+		mergeStmt:     createMergeStmt(table),
+	}
+}
+
+// Exec executes the stmt with background context
+func (b *mergeBuilder) Exec() (sql.Result, error) {
+	return b.ExecContext(context.Background())
+}
+
+// ExecContext executes the stmt
+func (b *mergeBuilder) ExecContext(ctx context.Context) (sql.Result, error) {
+	return exec(ctx, b.runner, b.EventReceiver, b, b.Dialect, b.eventKvs)
+}
+
+// Build builds `MERGE ...` in dialect
+func (b *mergeBuilder) Build(d Dialect, buf Buffer) error {
+	return b.mergeStmt.Build(b.Dialect, buf)
+}
+
+// Using specifies source of rows, a table name or a subquery
+func (b *mergeBuilder) Using(source interface{}) MergeBuilder {
+	b.mergeStmt.Using(source)
+	return b
+}
+
+// On adds a condition matching source rows with target rows
+func (b *mergeBuilder) On(query interface{}, value ...interface{}) MergeBuilder {
+	b.mergeStmt.On(query, value...)
+	return b
+}
+
+// WhenMatched updates matched target rows
+func (b *mergeBuilder) WhenMatched(set map[string]interface{}) MergeBuilder {
+	b.mergeStmt.WhenMatched(set)
+	return b
+}
+
+// WhenMatchedDelete deletes matched target rows
+func (b *mergeBuilder) WhenMatchedDelete() MergeBuilder {
+	b.mergeStmt.WhenMatchedDelete()
+	return b
+}
+
+// WhenNotMatched inserts source rows without a match
+func (b *mergeBuilder) WhenNotMatched(value map[string]interface{}) MergeBuilder {
+	b.mergeStmt.WhenNotMatched(value)
+	return b
+}
+
+// WithEventKv adds a key/value pair to the events of this query,
+// it never overwrites the keys set by dbr itself, e.g. "sql"
+func (b *mergeBuilder) WithEventKv(key, value string) MergeBuilder {
+	if b.eventKvs == nil {
+		b.eventKvs = make(kvs)
+	}
+	b.eventKvs[key] = value
+	return b
+}
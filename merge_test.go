@@ -0,0 +1,67 @@
+package dbr
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mailru/dbr/dialect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeStmt(t *testing.T) {
+	source := As(Select("id", "name", "balance").From("staging").Where(Gt("batch", 7)), "s")
+	stmt := MergeInto("accounts").
+		Using(source).
+		On(Eq("accounts.id", I("s.id"))).
+		On("accounts.tenant_id = ?", 3).
+		WhenMatched(map[string]interface{}{
+			"name":       I("s.name"),
+			"updated_by": "sync",
+		}).
+		WhenNotMatched(map[string]interface{}{
+			"id":      I("s.id"),
+			"name":    I("s.name"),
+			"balance": 0,
+		})
+
+	buf := NewBuffer()
+	err := stmt.Build(dialect.PostgreSQL, buf)
+	assert.NoError(t, err)
+	query, err := InterpolateForDialect(buf.String(), buf.Value(), dialect.PostgreSQL)
+	assert.NoError(t, err)
+	assert.Equal(t, `MERGE INTO "accounts" USING (SELECT id, name, balance FROM staging WHERE ("batch" > 7)) AS "s"`+
+		` ON ("accounts"."id" = "s"."id") AND (accounts.tenant_id = 3)`+
+		` WHEN MATCHED THEN UPDATE SET "name" = "s"."name", "updated_by" = 'sync'`+
+		` WHEN NOT MATCHED THEN INSERT ("balance","id","name") VALUES (0,"s"."id","s"."name")`, query)
+
+	buf = NewBuffer()
+	err = MergeInto("accounts").Using("staging").On("accounts.id = staging.id").WhenMatchedDelete().Build(dialect.PostgreSQL, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, `MERGE INTO "accounts" USING "staging" ON (accounts.id = staging.id) WHEN MATCHED THEN DELETE`, buf.String())
+
+	for _, d := range []Dialect{dialect.MySQL, dialect.SQLite3, dialect.ClickHouse} {
+		err := stmt.Build(d, NewBuffer())
+		assert.Equal(t, ErrMergeNotSupported, err)
+	}
+	err = MergeInto("accounts").Using("staging").WhenMatchedDelete().Build(dialect.PostgreSQL, NewBuffer())
+	assert.Equal(t, ErrColumnNotSpecified, err)
+}
+
+func TestMergeBuilder(t *testing.T) {
+	db, dbmock, err := sqlmock.New()
+	assert.NoError(t, err)
+	conn := &Connection{DB: db, Dialect: dialect.PostgreSQL, EventReceiver: nullReceiver}
+	dbmock.ExpectExec(`MERGE INTO "accounts" USING "staging" ON \(accounts.id = staging.id\) WHEN MATCHED THEN UPDATE SET "name" = 'x'`).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	result, err := conn.NewSession(nil).MergeInto("accounts").
+		Using("staging").
+		On("accounts.id = staging.id").
+		WhenMatched(map[string]interface{}{"name": "x"}).
+		Exec()
+	assert.NoError(t, err)
+	n, err := result.RowsAffected()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, n)
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+}
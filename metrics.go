@@ -0,0 +1,48 @@
+package dbr
+
+import (
+	"fmt"
+	"time"
+)
+
+// Metrics is an optional interface to collect query metrics, e.g. Prometheus counters.
+// Operation is "exec" or "select", dialect is the name of the Dialect, e.g. "postgresql".
+type Metrics interface {
+	IncQuery(operation, dialect string)
+	IncError(operation, dialect string)
+	ObserveDuration(operation, dialect string, d time.Duration)
+}
+
+// queryMetrics reports metrics of a single query, it does nothing if no Metrics is attached
+type queryMetrics struct {
+	Metrics
+	operation string
+	dialect   string
+}
+
+func newQueryMetrics(runner runner, operation string, d Dialect) queryMetrics {
+	var m Metrics
+	switch r := runner.(type) {
+	case *Session:
+		m = r.Metrics
+	case *Tx:
+		m = r.Metrics
+	}
+	if m == nil {
+		return queryMetrics{}
+	}
+	m.IncQuery(operation, fmt.Sprint(d))
+	return queryMetrics{Metrics: m, operation: operation, dialect: fmt.Sprint(d)}
+}
+
+func (m queryMetrics) incError() {
+	if m.Metrics != nil {
+		m.IncError(m.operation, m.dialect)
+	}
+}
+
+func (m queryMetrics) observeDuration(d time.Duration) {
+	if m.Metrics != nil {
+		m.ObserveDuration(m.operation, m.dialect, d)
+	}
+}
@@ -0,0 +1,67 @@
+package dbr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type testMetrics struct {
+	queries   []string
+	errors    []string
+	durations int
+}
+
+func (m *testMetrics) IncQuery(operation, dialect string) {
+	m.queries = append(m.queries, fmt.Sprintf("%s/%s", operation, dialect))
+}
+
+func (m *testMetrics) IncError(operation, dialect string) {
+	m.errors = append(m.errors, fmt.Sprintf("%s/%s", operation, dialect))
+}
+
+func (m *testMetrics) ObserveDuration(operation, dialect string, d time.Duration) {
+	m.durations++
+}
+
+func TestMetrics(t *testing.T) {
+	sess, dbmock, _ := newRecordingSessionMock()
+	metrics := &testMetrics{}
+	sess.Metrics = metrics
+
+	dbmock.ExpectQuery("SELECT a FROM table").WillReturnRows(sqlmock.NewRows([]string{"a"}).AddRow(1))
+	dbmock.ExpectExec("UPDATE `table`").WillReturnError(errors.New("boom"))
+	dbmock.ExpectBegin()
+	dbmock.ExpectExec("DELETE FROM `table`").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	var a int
+	_, err := sess.Select("a").From("table").Load(&a)
+	assert.NoError(t, err)
+	_, err = sess.Update("table").Set("a", 1).Exec()
+	assert.Error(t, err)
+
+	// forked sessions and transactions inherit metrics
+	tx, err := sess.NewSession(nil).Begin()
+	assert.NoError(t, err)
+	_, err = tx.DeleteFrom("table").Exec()
+	assert.NoError(t, err)
+
+	// interpolation error
+	_, err = sess.Select("a").From("table").Where("a = ?").Load(&a)
+	assert.Equal(t, &PlaceholderCountError{Query: "a = ?", Placeholders: 1, Values: 0}, err)
+
+	assert.Equal(t, []string{"select/mysql", "exec/mysql", "exec/mysql", "select/mysql"}, metrics.queries)
+	assert.Equal(t, []string{"exec/mysql", "select/mysql"}, metrics.errors)
+	assert.Equal(t, 3, metrics.durations)
+}
+
+func TestMetricsNotSet(t *testing.T) {
+	sess, dbmock, _ := newRecordingSessionMock()
+	dbmock.ExpectExec("UPDATE `table`").WillReturnError(errors.New("boom"))
+	_, err := sess.Update("table").Set("a", 1).Exec()
+	assert.EqualError(t, err, "boom")
+}
@@ -2,6 +2,7 @@ package dbr
 
 import (
 	"database/sql/driver"
+	"reflect"
 	"time"
 )
 
@@ -17,3 +18,60 @@ func (n nowSentinel) Value() (driver.Value, error) {
 	now := time.Now().UTC().Format(timeFormat)
 	return now, nil
 }
+
+// NowFunc returns the current time set to fields tagged `auto_create` or `auto_update`
+// by Record and SetRecord, it can be replaced in tests
+var NowFunc = time.Now
+
+var (
+	typeTime     = reflect.TypeOf(time.Time{})
+	typeNullTime = reflect.TypeOf(NullTime{})
+)
+
+// autoTimestamp returns the value of the struct field of index in v, fields tagged `auto_create`
+// or `auto_update`, e.g. `db:"created_at,auto_create"`, are set to the current time on insert if they are zero,
+// and fields tagged `auto_update` are set on every update. The field is set too if v is addressable.
+// Fields of types other than time.Time, *time.Time and NullTime are never set
+func autoTimestamp(v reflect.Value, index []int, update bool) interface{} {
+	field := v.FieldByIndex(index)
+	_, opts := parseTag(v.Type().FieldByIndex(index).Tag.Get("db"))
+	var auto bool
+	if update {
+		// the value is the time of the previous update
+		auto = opts.Contains("auto_update")
+	} else {
+		auto = (opts.Contains("auto_create") || opts.Contains("auto_update")) && isZeroTime(field)
+	}
+	if !auto {
+		return field.Interface()
+	}
+	now := NowFunc()
+	var value reflect.Value
+	switch field.Type() {
+	case typeTime:
+		value = reflect.ValueOf(now)
+	case reflect.PtrTo(typeTime):
+		value = reflect.ValueOf(&now)
+	case typeNullTime:
+		value = reflect.ValueOf(NullTime{Time: now, Valid: true})
+	default:
+		return field.Interface()
+	}
+	if field.CanSet() {
+		field.Set(value)
+	}
+	return value.Interface()
+}
+
+// isZeroTime reports whether v is a zero time.Time, a nil *time.Time or a NULL NullTime
+func isZeroTime(v reflect.Value) bool {
+	switch t := v.Interface().(type) {
+	case time.Time:
+		return t.IsZero()
+	case *time.Time:
+		return t == nil
+	case NullTime:
+		return !t.Valid
+	}
+	return false
+}
@@ -1,5 +1,10 @@
 package dbr
 
+import (
+	"fmt"
+	"strings"
+)
+
 type direction bool
 
 // orderby directions
@@ -9,16 +14,128 @@ const (
 	desc           = true
 )
 
+// NullsOrdering is the position of NULLs in ordering of select
+type NullsOrdering int
+
+const (
+	// NullsDefault keeps ordering of the dialect, e.g. NULLs are first in ascending order in MySQL
+	// and last in PostgreSQL
+	NullsDefault NullsOrdering = iota
+	// NullsFirst puts NULLs before other values in both directions
+	NullsFirst
+	// NullsLast puts NULLs after other values in both directions
+	NullsLast
+)
+
+// nullsOrdering returns ordering of NULLs of session or transaction
+func nullsOrdering(runner runner) NullsOrdering {
+	switch r := runner.(type) {
+	case *Session:
+		return r.NullsOrdering
+	case *Tx:
+		return r.NullsOrdering
+	}
+	return NullsDefault
+}
+
+type orderBy struct {
+	column string
+	dir    direction
+	// isAlias quotes column like in As
+	isAlias bool
+}
+
+// orderAlias orders by alias of a column, which is quoted like in As,
+// so the alias matches it in dialects folding case of unquoted identifiers
+func orderAlias(alias string, dir direction) Builder {
+	return &orderBy{column: alias, dir: dir, isAlias: true}
+}
+
 func order(column string, dir direction) Builder {
-	return BuildFunc(func(d Dialect, buf Buffer) error {
-		// FIXME: no quote ident
+	return &orderBy{column: column, dir: dir}
+}
+
+func (o *orderBy) Build(d Dialect, buf Buffer) error {
+	return o.build(d, buf, NullsDefault)
+}
+
+// build writes the ordering with NULLs ordered by nulls, it is emulated by ordering
+// by `column IS NULL` first in dialects without NULLS FIRST and NULLS LAST
+func (o *orderBy) build(d Dialect, buf Buffer, nulls NullsOrdering) error {
+	// FIXME: no quote ident
+	column := o.column
+	if o.isAlias {
+		column = d.QuoteIdent(column)
+	}
+	emulate := nulls != NullsDefault && !d.SupportsNullsOrdering()
+	if emulate {
 		buf.WriteString(column)
-		switch dir {
-		case asc:
-			buf.WriteString(" ASC")
-		case desc:
-			buf.WriteString(" DESC")
+		if nulls == NullsFirst {
+			buf.WriteString(" IS NULL DESC, ")
+		} else {
+			buf.WriteString(" IS NULL ASC, ")
+		}
+	}
+	buf.WriteString(column)
+	switch o.dir {
+	case asc:
+		buf.WriteString(" ASC")
+	case desc:
+		buf.WriteString(" DESC")
+	}
+	if nulls != NullsDefault && !emulate {
+		if nulls == NullsFirst {
+			buf.WriteString(" NULLS FIRST")
+		} else {
+			buf.WriteString(" NULLS LAST")
+		}
+	}
+	return nil
+}
+
+// checkDistinctOn returns an error if the leading orderings are not by columns of DISTINCT ON,
+// because PostgreSQL rejects such queries. Orderings by expressions with values are not checked
+func checkDistinctOn(on []string, order []Builder) error {
+	for i := 0; i < len(order) && i < len(on); i++ {
+		column, ok := orderColumn(order[i])
+		if !ok {
+			return nil
+		}
+		found := false
+		for _, col := range on {
+			if strings.TrimSpace(col) == column {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("dbr: ORDER BY %s does not match DISTINCT ON (%s), leading orderings must be by its columns",
+				column, strings.Join(on, ", "))
+		}
+	}
+	return nil
+}
+
+// orderColumn returns the column or expression of ordering without direction,
+// e.g. `a` of `a DESC NULLS LAST` added by OrderBy
+func orderColumn(order Builder) (string, bool) {
+	switch order := order.(type) {
+	case *orderBy:
+		return order.column, true
+	case *raw:
+		if len(order.Value) > 0 {
+			return "", false
+		}
+		field := strings.Fields(order.Query)
+		for len(field) > 1 {
+			switch strings.ToUpper(field[len(field)-1]) {
+			case "ASC", "DESC", "NULLS", "FIRST", "LAST":
+				field = field[:len(field)-1]
+				continue
+			}
+			break
 		}
-		return nil
-	})
+		return strings.Join(field, " "), true
+	}
+	return "", false
 }
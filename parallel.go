@@ -0,0 +1,60 @@
+package dbr
+
+import (
+	"context"
+	"sync"
+)
+
+// defaultParallelSelectLimit is used by ParallelSelect if Session.ParallelSelectLimit is not set
+const defaultParallelSelectLimit = 4
+
+// ParallelSelect runs independent queries concurrently, each on its own connection of the pool,
+// and loads the result of queries[i] into dests[i]. At most ParallelSelectLimit queries run at once.
+// It waits for all queries, the first error cancels the queries which are still running and is returned
+func (sess *Session) ParallelSelect(ctx context.Context, queries []SelectBuilder, dests []interface{}) error {
+	if len(queries) != len(dests) {
+		return ErrDestinationCount
+	}
+	limit := sess.ParallelSelectLimit
+	if limit <= 0 {
+		limit = defaultParallelSelectLimit
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	sem := make(chan struct{}, limit)
+	for i := range queries {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if err := ctx.Err(); err != nil {
+			// query failed or parent context is done
+			fail(err)
+			break
+		}
+		wg.Add(1)
+		go func(query SelectBuilder, dest interface{}) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if _, err := query.LoadContext(ctx, dest); err != nil {
+				fail(err)
+			}
+		}(queries[i], dests[i])
+	}
+	wg.Wait()
+	return firstErr
+}
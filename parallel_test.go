@@ -0,0 +1,67 @@
+package dbr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mailru/dbr/dialect"
+	"github.com/stretchr/testify/assert"
+)
+
+func newParallelSessionMock(t *testing.T) (*Session, sqlmock.Sqlmock) {
+	db, dbmock, err := sqlmock.New()
+	assert.NoError(t, err)
+	dbmock.MatchExpectationsInOrder(false)
+	conn := &Connection{DB: db, Dialect: dialect.MySQL, EventReceiver: nullReceiver}
+	return conn.NewSession(nil), dbmock
+}
+
+func TestParallelSelect(t *testing.T) {
+	sess, dbmock := newParallelSessionMock(t)
+	sess.ParallelSelectLimit = 2
+
+	var queries []SelectBuilder
+	var dests []interface{}
+	values := make([]int, 4)
+	for i := range values {
+		dbmock.ExpectQuery(fmt.Sprintf("SELECT %d", i)).
+			WillDelayFor(50 * time.Millisecond).
+			WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(i * 10))
+		queries = append(queries, sess.Select(fmt.Sprint(i)))
+		dests = append(dests, &values[i])
+	}
+
+	start := time.Now()
+	err := sess.ParallelSelect(context.Background(), queries, dests)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 10, 20, 30}, values)
+	// 4 queries run by 2 at once
+	assert.True(t, time.Since(start) >= 100*time.Millisecond)
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+
+	err = sess.ParallelSelect(context.Background(), queries, dests[:1])
+	assert.Equal(t, ErrDestinationCount, err)
+}
+
+func TestParallelSelectError(t *testing.T) {
+	sess, dbmock := newParallelSessionMock(t)
+	boom := errors.New("boom")
+	dbmock.ExpectQuery("SELECT 1").WillReturnError(boom)
+	dbmock.ExpectQuery("SELECT 2").WillDelayFor(time.Second).WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(2))
+
+	var a, b int
+	start := time.Now()
+	err := sess.ParallelSelect(context.Background(), []SelectBuilder{sess.Select("1"), sess.Select("2")}, []interface{}{&a, &b})
+	assert.Equal(t, boom, err)
+	// the slow query is canceled
+	assert.True(t, time.Since(start) < time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = sess.ParallelSelect(ctx, []SelectBuilder{sess.Select("1")}, []interface{}{&a})
+	assert.Equal(t, context.Canceled, err)
+}
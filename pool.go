@@ -0,0 +1,52 @@
+package dbr
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+)
+
+// PoolStats returns statistics of the connection pool, e.g. to monitor its saturation
+func (conn *Connection) PoolStats() sql.DBStats {
+	return conn.DB.Stats()
+}
+
+// ReportPoolStats sends statistics of the connection pool to EventKv of the EventReceiver
+// as "dbr.pool_stats" event every interval. It reports in a goroutine until ctx is done,
+// the returned channel is closed when the goroutine exits. Nothing is reported if interval
+// is not positive, the channel is closed at once
+func (conn *Connection) ReportPoolStats(ctx context.Context, interval time.Duration) <-chan struct{} {
+	done := make(chan struct{})
+	if interval <= 0 {
+		close(done)
+		return done
+	}
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				conn.EventKv("dbr.pool_stats", poolStatsKvs(conn.PoolStats()))
+			}
+		}
+	}()
+	return done
+}
+
+func poolStatsKvs(stats sql.DBStats) kvs {
+	return kvs{
+		"max_open":            strconv.Itoa(stats.MaxOpenConnections),
+		"open":                strconv.Itoa(stats.OpenConnections),
+		"in_use":              strconv.Itoa(stats.InUse),
+		"idle":                strconv.Itoa(stats.Idle),
+		"wait_count":          strconv.FormatInt(stats.WaitCount, 10),
+		"wait_duration":       stats.WaitDuration.String(),
+		"max_idle_closed":     strconv.FormatInt(stats.MaxIdleClosed, 10),
+		"max_lifetime_closed": strconv.FormatInt(stats.MaxLifetimeClosed, 10),
+	}
+}
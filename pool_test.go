@@ -0,0 +1,66 @@
+package dbr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mailru/dbr/dialect"
+	"github.com/stretchr/testify/assert"
+)
+
+// poolStatsReceiver sends events to a channel, they come from the goroutine of ReportPoolStats
+type poolStatsReceiver struct {
+	NullEventReceiver
+	events chan testEvent
+}
+
+func (r *poolStatsReceiver) EventKv(eventName string, kvs map[string]string) {
+	r.events <- testEvent{name: eventName, kvs: kvs}
+}
+
+func TestReportPoolStats(t *testing.T) {
+	db, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	db.SetMaxOpenConns(3)
+	recv := &poolStatsReceiver{events: make(chan testEvent)}
+	conn := &Connection{DB: db, Dialect: dialect.MySQL, EventReceiver: recv}
+
+	assert.Equal(t, 3, conn.PoolStats().MaxOpenConnections)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := conn.ReportPoolStats(ctx, time.Millisecond)
+	for i := 0; i < 2; i++ {
+		select {
+		case event := <-recv.events:
+			assert.Equal(t, "dbr.pool_stats", event.name)
+			assert.Equal(t, "3", event.kvs["max_open"])
+			assert.Equal(t, "0", event.kvs["in_use"])
+		case <-time.After(time.Second):
+			t.Fatal("no pool stats reported")
+		}
+	}
+
+	cancel()
+	for {
+		select {
+		case <-recv.events:
+			// reported before the cancellation was noticed
+			continue
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("reporting did not stop")
+		}
+		break
+	}
+
+	// nothing is reported without a positive interval
+	for _, interval := range []time.Duration{0, -time.Second} {
+		select {
+		case <-conn.ReportPoolStats(context.Background(), interval):
+		case <-time.After(time.Second):
+			t.Fatal("reporting did not stop")
+		}
+	}
+}
@@ -0,0 +1,107 @@
+// Package pqlisten delivers PostgreSQL NOTIFY payloads of LISTEN channels via github.com/lib/pq,
+// it is separate from dbr, so dbr does not register the "postgres" driver
+package pqlisten
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/mailru/dbr"
+)
+
+// Notification is a payload delivered by PostgreSQL NOTIFY
+type Notification struct {
+	Channel string
+	Payload string
+}
+
+const (
+	minReconnectInterval = 10 * time.Second
+	maxReconnectInterval = time.Minute
+	pingInterval         = 90 * time.Second
+)
+
+// Listen subscribes to PostgreSQL channel via LISTEN on a dedicated connection to dsn and delivers NOTIFY payloads,
+// events and errors are sent to log, which may be nil. The connection is re-established automatically if lost,
+// notifications sent while it is down are lost. Listen waits for the first connection until ctx is done,
+// then it returns the error of ctx. The returned channel is closed and the connection is closed when ctx is done
+func Listen(ctx context.Context, dsn string, log dbr.EventReceiver, channel string) (<-chan Notification, error) {
+	if log == nil {
+		log = &dbr.NullEventReceiver{}
+	}
+	kvs := map[string]string{"channel": channel}
+	connected := make(chan struct{})
+	var once sync.Once
+	listener := pq.NewListener(dsn, minReconnectInterval, maxReconnectInterval,
+		func(ev pq.ListenerEventType, err error) {
+			switch ev {
+			case pq.ListenerEventConnected:
+				once.Do(func() { close(connected) })
+			case pq.ListenerEventDisconnected, pq.ListenerEventConnectionAttemptFailed:
+				log.EventErrKv("dbr.listen.disconnected", err, kvs)
+			case pq.ListenerEventReconnected:
+				log.EventKv("dbr.listen.reconnected", kvs)
+			}
+		})
+
+	// Listen of pq waits for a connection without a timeout, so it waits only after the first one
+	select {
+	case <-connected:
+	case <-ctx.Done():
+		listener.Close()
+		return nil, log.EventErrKv("dbr.listen", ctx.Err(), kvs)
+	}
+	listened := make(chan error, 1)
+	go func() {
+		listened <- listener.Listen(channel)
+	}()
+	var err error
+	select {
+	case err = <-listened:
+	case <-ctx.Done():
+		// Close makes Listen return if it waits for reconnection
+		listener.Close()
+		<-listened
+		err = ctx.Err()
+	}
+	if err != nil {
+		listener.Close()
+		return nil, log.EventErrKv("dbr.listen", err, kvs)
+	}
+	log.EventKv("dbr.listen", kvs)
+
+	notifications := make(chan Notification)
+	go func() {
+		defer close(notifications)
+		defer listener.Close()
+
+		ping := time.NewTicker(pingInterval)
+		defer ping.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ping.C:
+				// detects a dead connection, which starts reconnection
+				go listener.Ping()
+			case n, ok := <-listener.Notify:
+				if !ok {
+					return
+				}
+				if n == nil {
+					// sent after reconnection, notifications may have been lost
+					continue
+				}
+				select {
+				case notifications <- Notification{Channel: n.Channel, Payload: n.Extra}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return notifications, nil
+}
@@ -0,0 +1,46 @@
+package pqlisten
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const postgresDSN = "postgres://postgres@localhost:5432/dbr_test?sslmode=disable"
+
+func TestListenUnreachable(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	notifications, err := Listen(ctx, "postgres://postgres@127.0.0.1:1/dbr_test?sslmode=disable", nil, "dbr_events")
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Nil(t, notifications)
+	assert.True(t, time.Since(start) < 5*time.Second)
+}
+
+func TestListen(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	notifications, err := Listen(ctx, postgresDSN, nil, "dbr_events")
+	assert.NoError(t, err)
+
+	db, err := sql.Open("postgres", postgresDSN)
+	assert.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec("SELECT pg_notify('dbr_events', 'hello')")
+	assert.NoError(t, err)
+
+	select {
+	case n := <-notifications:
+		assert.Equal(t, Notification{Channel: "dbr_events", Payload: "hello"}, n)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no notification")
+	}
+
+	cancel()
+	_, ok := <-notifications
+	assert.False(t, ok)
+}
@@ -0,0 +1,40 @@
+package dbr
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// protoTypes are struct types of protobuf messages registered by RegisterProtoMessage
+var protoTypes sync.Map
+
+// RegisterProtoMessage makes columns map onto fields of the generated struct of a protobuf message
+// by their proto field names, e.g. (*pb.User)(nil). A `db` tag overrides the proto name, fields without
+// `protobuf` tag, e.g. internal state and oneof wrappers, are not mapped. Fields of message, repeated
+// and map types are mapped as well, so they must not be selected unless they implement sql.Scanner.
+// Messages which are not registered are mapped as any other struct. Register messages before
+// they are used, e.g. in init, the columns of structs are cached. It is safe for concurrent use
+func RegisterProtoMessage(message interface{}) {
+	t := reflect.TypeOf(message)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	protoTypes.Store(t, struct{}{})
+}
+
+func isProtoMessage(t reflect.Type) bool {
+	_, ok := protoTypes.Load(t)
+	return ok
+}
+
+// protoFieldName returns the name of field from its tag generated by protoc,
+// e.g. `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3"`
+func protoFieldName(tag string) string {
+	for _, opt := range strings.Split(tag, ",") {
+		if strings.HasPrefix(opt, "name=") {
+			return opt[len("name="):]
+		}
+	}
+	return ""
+}
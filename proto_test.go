@@ -0,0 +1,50 @@
+package dbr
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// testProtoUser is shaped like a struct generated by protoc-gen-go
+type testProtoUser struct {
+	state         struct{}
+	sizeCache     int32
+	unknownFields []byte
+
+	Id       int64   `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	EMail    string  `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Score    float64 `protobuf:"fixed64,3,opt,name=score,proto3" json:"score,omitempty" db:"rating"`
+	Internal string
+}
+
+func TestLoadProtoMessage(t *testing.T) {
+	sess, dbmock := newSessionMock()
+
+	dbmock.ExpectQuery("SELECT id, email, rating, internal FROM users").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "rating", "internal"}).AddRow(1, "a@b.c", 4.5, "x"))
+	var before testProtoUser
+	_, err := sess.Select("id", "email", "rating", "internal").From("users").Load(&before)
+	assert.NoError(t, err)
+	// not registered, names of fields without db tag are used
+	assert.Equal(t, testProtoUser{Id: 1, Score: 4.5, Internal: "x"}, before)
+
+	RegisterProtoMessage((*testProtoUser)(nil))
+	defer protoTypes.Delete(reflect.TypeOf(testProtoUser{}))
+
+	dbmock.ExpectQuery("SELECT id, email, rating, internal FROM users").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "rating", "internal"}).
+			AddRow(1, "a@b.c", 4.5, "x").
+			AddRow(2, "d@e.f", 3.0, "y"))
+	var users []*testProtoUser
+	n, err := sess.Select("id", "email", "rating", "internal").From("users").Load(&users)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []*testProtoUser{
+		{Id: 1, EMail: "a@b.c", Score: 4.5},
+		{Id: 2, EMail: "d@e.f", Score: 3.0},
+	}, users)
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+}
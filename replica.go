@@ -0,0 +1,27 @@
+package dbr
+
+import "context"
+
+type replicaKey struct{}
+
+// WithReplica returns a copy of ctx which makes selects of a session run on its Replica connection,
+// e.g. sess.Select("*").From("suggestions").LoadContext(dbr.WithReplica(ctx), &suggestions).
+// Writes and transactions always use the primary connection, so does a session without Replica
+func WithReplica(ctx context.Context) context.Context {
+	return context.WithValue(ctx, replicaKey{}, true)
+}
+
+// prefersReplica reports whether ctx is made by WithReplica
+func prefersReplica(ctx context.Context) bool {
+	prefer, _ := ctx.Value(replicaKey{}).(bool)
+	return prefer
+}
+
+// readRunner returns replica connection of session runner if ctx prefers it, otherwise runner itself
+func readRunner(ctx context.Context, r runner) runner {
+	sess, ok := r.(*Session)
+	if !ok || sess.Replica == nil || !prefersReplica(ctx) {
+		return r
+	}
+	return sess.Replica.DB
+}
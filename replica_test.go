@@ -0,0 +1,106 @@
+package dbr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mailru/dbr/dialect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplicaRouting(t *testing.T) {
+	primaryDB, primary, err := sqlmock.New()
+	assert.NoError(t, err)
+	replicaDB, replica, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	conn := &Connection{DB: primaryDB, Dialect: dialect.PostgreSQL, EventReceiver: nullReceiver}
+	sess := conn.NewSession(nil)
+	ctx := WithReplica(context.Background())
+
+	// falls back to primary when no replica is set
+	primary.ExpectQuery("SELECT id FROM users").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	var id int
+	assert.NoError(t, sess.Select("id").From("users").LoadValueContext(ctx, &id))
+	assert.Equal(t, 1, id)
+
+	sess.Replica = &Connection{DB: replicaDB, Dialect: dialect.PostgreSQL, EventReceiver: nullReceiver}
+	sess = sess.NewSession(nil)
+
+	replica.ExpectQuery("SELECT id FROM users").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	assert.NoError(t, sess.Select("id").From("users").LoadValueContext(ctx, &id))
+	assert.Equal(t, 2, id)
+
+	// selects without the context use primary
+	primary.ExpectQuery("SELECT id FROM users").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	assert.NoError(t, sess.Select("id").From("users").LoadValueContext(context.Background(), &id))
+	assert.Equal(t, 3, id)
+
+	// writes always go to primary
+	primary.ExpectExec(`UPDATE "users"`).WillReturnResult(sqlmock.NewResult(0, 1))
+	_, err = sess.Update("users").Set("name", "a").ExecContext(ctx)
+	assert.NoError(t, err)
+	primary.ExpectQuery(`INSERT INTO "users"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	var user struct{ ID int }
+	err = sess.InsertInto("users").Columns("name").Values("b").Returning("id").LoadStructContext(ctx, &user)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, user.ID)
+
+	// so do transactions
+	primary.ExpectBegin()
+	primary.ExpectQuery("SELECT id FROM users").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	tx, err := sess.Begin()
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Select("id").From("users").LoadValueContext(ctx, &id))
+	assert.Equal(t, 5, id)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+	assert.NoError(t, replica.ExpectationsWereMet())
+}
+
+func TestTransactionOnPrimary(t *testing.T) {
+	primaryDB, primary, err := sqlmock.New()
+	assert.NoError(t, err)
+	replicaDB, replica, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	conn := &Connection{DB: primaryDB, Dialect: dialect.PostgreSQL, EventReceiver: nullReceiver}
+	replicaConn := &Connection{DB: replicaDB, Dialect: dialect.PostgreSQL, EventReceiver: nullReceiver}
+	// the session prefers replica for all its queries
+	sess := conn.NewSessionContext(WithReplica(context.Background()), nil)
+	sess.Replica = replicaConn
+
+	replica.ExpectQuery("SELECT id FROM users").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	var id int
+	assert.NoError(t, sess.Select("id").From("users").LoadValueContext(WithReplica(context.Background()), &id))
+	assert.Equal(t, 1, id)
+
+	primary.ExpectBegin()
+	primary.ExpectQuery("SELECT id FROM users").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	primary.ExpectCommit()
+	err = sess.Transact(WithReplica(context.Background()), func(tx *Tx) error {
+		return tx.Select("id").From("users").LoadValue(&id)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, id)
+
+	primary.ExpectBegin()
+	primary.ExpectRollback()
+	tx, err := sess.Begin()
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Rollback())
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+	assert.NoError(t, replica.ExpectationsWereMet())
+
+	// transactions of a read-only session, e.g. of the replica itself, fail before they begin
+	_, err = replicaConn.NewReadOnlySession(nil).Begin()
+	assert.Equal(t, ErrReadOnlySession, err)
+	err = replicaConn.NewReadOnlySession(nil).Transact(context.Background(), func(tx *Tx) error {
+		t.Fatal("transaction of read-only session")
+		return nil
+	})
+	assert.Equal(t, ErrReadOnlySession, err)
+	assert.NoError(t, replica.ExpectationsWereMet())
+}
@@ -0,0 +1,33 @@
+package dbr
+
+import "database/sql"
+
+// SumResults combines results of statements run in chunks, e.g. a bulk insert split into
+// several inserts, into one sql.Result. RowsAffected returns the sum of rows affected
+// by each statement and LastInsertId returns the id of the last one
+func SumResults(result ...sql.Result) sql.Result {
+	return sumResult(result)
+}
+
+type sumResult []sql.Result
+
+// LastInsertId returns the id of the last statement, the ids of earlier chunks are not meaningful
+func (r sumResult) LastInsertId() (int64, error) {
+	if len(r) == 0 {
+		return 0, nil
+	}
+	return r[len(r)-1].LastInsertId()
+}
+
+// RowsAffected returns the total number of rows affected by all statements
+func (r sumResult) RowsAffected() (int64, error) {
+	var total int64
+	for _, result := range r {
+		n, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		total += n
+	}
+	return total, nil
+}
@@ -0,0 +1,39 @@
+package dbr
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSumResults(t *testing.T) {
+	sess, dbmock := newSessionMock()
+
+	var results []sql.Result
+	for i, chunk := range [][]int{{1, 2}, {3, 4}, {5}} {
+		dbmock.ExpectExec("INSERT INTO `t`").WillReturnResult(sqlmock.NewResult(int64(10+i), int64(len(chunk))))
+		stmt := sess.InsertInto("t").Columns("a")
+		for _, v := range chunk {
+			stmt.Values(v)
+		}
+		result, err := stmt.Exec()
+		assert.NoError(t, err)
+		results = append(results, result)
+	}
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+
+	result := SumResults(results...)
+	n, err := result.RowsAffected()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 5, n)
+	id, err := result.LastInsertId()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 12, id)
+
+	result = SumResults(results[0], sqlmock.NewErrorResult(errors.New("no rows affected")))
+	_, err = result.RowsAffected()
+	assert.EqualError(t, err, "no rows affected")
+}
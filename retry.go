@@ -0,0 +1,63 @@
+package dbr
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"strconv"
+)
+
+// maxRetries bounds RetryPolicy.MaxRetries
+const maxRetries = 5
+
+// RetryPolicy configures retries of session queries failed because of a broken connection,
+// each retry is made on a connection re-acquired from the pool and emits "dbr.exec.retry"
+// or "dbr.select.retry" event. Queries in transactions are never retried.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries, at most 5; zero disables retries
+	MaxRetries int
+	// Writes enables retries of insert, update and delete, which may be not idempotent
+	Writes bool
+	// IsRetryable reports whether query should be retried after err,
+	// by default driver.ErrBadConn and io.ErrUnexpectedEOF are retried
+	IsRetryable func(err error) bool
+}
+
+func (p RetryPolicy) retries(write bool) int {
+	if write && !p.Writes {
+		return 0
+	}
+	if p.MaxRetries > maxRetries {
+		return maxRetries
+	}
+	return p.MaxRetries
+}
+
+func (p RetryPolicy) retryable(err error) bool {
+	if p.IsRetryable != nil {
+		return p.IsRetryable(err)
+	}
+	return err == driver.ErrBadConn || err == io.ErrUnexpectedEOF
+}
+
+// withRetry calls f and retries it according to the retry policy of session runner
+func withRetry(ctx context.Context, runner runner, log EventReceiver, eventName, query string, write bool, f func() error) error {
+	var policy RetryPolicy
+	if sess, ok := runner.(*Session); ok {
+		policy = sess.Retry
+	}
+
+	err := f()
+	for attempt := 1; err != nil && attempt <= policy.retries(write) && policy.retryable(err); attempt++ {
+		if ctx.Err() != nil {
+			break
+		}
+		log.EventKv(eventName, kvs{
+			"sql":     query,
+			"attempt": strconv.Itoa(attempt),
+			"error":   err.Error(),
+		})
+		err = f()
+	}
+	return err
+}
@@ -0,0 +1,85 @@
+package dbr
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	assert.Equal(t, 0, RetryPolicy{}.retries(false))
+	assert.Equal(t, 2, RetryPolicy{MaxRetries: 2}.retries(false))
+	assert.Equal(t, 0, RetryPolicy{MaxRetries: 2}.retries(true))
+	assert.Equal(t, 2, RetryPolicy{MaxRetries: 2, Writes: true}.retries(true))
+	assert.Equal(t, maxRetries, RetryPolicy{MaxRetries: 100}.retries(false))
+
+	assert.True(t, RetryPolicy{}.retryable(io.ErrUnexpectedEOF))
+	assert.False(t, RetryPolicy{}.retryable(errors.New("syntax error")))
+	custom := RetryPolicy{IsRetryable: func(err error) bool { return err.Error() == "invalid connection" }}
+	assert.True(t, custom.retryable(errors.New("invalid connection")))
+	assert.False(t, custom.retryable(io.ErrUnexpectedEOF))
+}
+
+func TestRetrySelect(t *testing.T) {
+	sess, dbmock, recv := newRecordingSessionMock()
+	sess.Retry = RetryPolicy{MaxRetries: 2}
+	dbmock.ExpectQuery("SELECT a FROM table").WillReturnError(io.ErrUnexpectedEOF)
+	dbmock.ExpectQuery("SELECT a FROM table").WillReturnRows(sqlmock.NewRows([]string{"a"}).AddRow(1))
+
+	var a int
+	_, err := sess.Select("a").From("table").Load(&a)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, a)
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+	assert.Equal(t, testEvent{
+		name: "dbr.select.retry",
+		kvs:  map[string]string{"sql": "SELECT a FROM table", "attempt": "1", "error": io.ErrUnexpectedEOF.Error()},
+	}, recv.events[0])
+
+	// retries are bounded
+	dbmock.ExpectQuery("SELECT a FROM table").WillReturnError(io.ErrUnexpectedEOF)
+	dbmock.ExpectQuery("SELECT a FROM table").WillReturnError(io.ErrUnexpectedEOF)
+	dbmock.ExpectQuery("SELECT a FROM table").WillReturnError(io.ErrUnexpectedEOF)
+	_, err = sess.Select("a").From("table").Load(&a)
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+}
+
+func TestRetryExec(t *testing.T) {
+	sess, dbmock, recv := newRecordingSessionMock()
+	sess.Retry = RetryPolicy{MaxRetries: 1}
+	dbmock.ExpectExec("DELETE FROM `table`").WillReturnError(io.ErrUnexpectedEOF)
+
+	// writes are not retried by default
+	_, err := sess.DeleteFrom("table").Exec()
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+	for _, e := range recv.events {
+		assert.NotEqual(t, "dbr.exec.retry", e.name)
+	}
+
+	sess.Retry.Writes = true
+	dbmock.ExpectExec("DELETE FROM `table`").WillReturnError(io.ErrUnexpectedEOF)
+	dbmock.ExpectExec("DELETE FROM `table`").WillReturnResult(sqlmock.NewResult(0, 1))
+	_, err = sess.DeleteFrom("table").Exec()
+	assert.NoError(t, err)
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+}
+
+func TestRetryNotInTransaction(t *testing.T) {
+	sess, dbmock, _ := newRecordingSessionMock()
+	sess.Retry = RetryPolicy{MaxRetries: 1}
+	dbmock.ExpectBegin()
+	dbmock.ExpectQuery("SELECT a FROM table").WillReturnError(io.ErrUnexpectedEOF)
+	dbmock.ExpectRollback()
+
+	tx, err := sess.Begin()
+	assert.NoError(t, err)
+	defer tx.RollbackUnlessCommitted()
+	var a int
+	_, err = tx.Select("a").From("table").Load(&a)
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+}
@@ -0,0 +1,62 @@
+package dbr
+
+import "sync"
+
+// Router holds named connections, e.g. "oltp" for MySQL and "olap" for ClickHouse,
+// so that application code picks the backend by name
+type Router struct {
+	mu       sync.RWMutex
+	primary  map[string]*Connection
+	replicas map[string]*Connection
+}
+
+// NewRouter creates an empty Router
+func NewRouter() *Router {
+	return &Router{
+		primary:  make(map[string]*Connection),
+		replicas: make(map[string]*Connection),
+	}
+}
+
+// Add registers primary connection, which is used for writes, by name
+func (r *Router) Add(name string, conn *Connection) *Router {
+	r.mu.Lock()
+	r.primary[name] = conn
+	r.mu.Unlock()
+	return r
+}
+
+// AddReplica registers read-only replica connection for name, it is returned by RouteRead
+func (r *Router) AddReplica(name string, conn *Connection) *Router {
+	r.mu.Lock()
+	r.replicas[name] = conn
+	r.mu.Unlock()
+	return r
+}
+
+// Route returns primary connection by name, returns ErrRouteNotFound if there is no such name
+func (r *Router) Route(name string) (*Connection, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	conn, ok := r.primary[name]
+	if !ok {
+		return nil, ErrRouteNotFound
+	}
+	return conn, nil
+}
+
+// RouteRead returns connection for reads by name: replica if registered, otherwise primary
+func (r *Router) RouteRead(name string) (*Connection, error) {
+	r.mu.RLock()
+	conn, ok := r.replicas[name]
+	r.mu.RUnlock()
+	if ok {
+		return conn, nil
+	}
+	return r.Route(name)
+}
+
+// RouteWrite returns connection for writes by name, it is the same as Route
+func (r *Router) RouteWrite(name string) (*Connection, error) {
+	return r.Route(name)
+}
@@ -0,0 +1,42 @@
+package dbr
+
+import (
+	"testing"
+
+	"github.com/mailru/dbr/dialect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter(t *testing.T) {
+	oltp := &Connection{Dialect: dialect.MySQL, EventReceiver: nullReceiver}
+	oltpReplica := &Connection{Dialect: dialect.MySQL, EventReceiver: nullReceiver}
+	olap := &Connection{Dialect: dialect.ClickHouse, EventReceiver: nullReceiver}
+
+	r := NewRouter().
+		Add("oltp", oltp).
+		AddReplica("oltp", oltpReplica).
+		Add("olap", olap)
+
+	conn, err := r.Route("olap")
+	assert.NoError(t, err)
+	assert.True(t, conn == olap)
+	assert.Equal(t, dialect.ClickHouse, conn.NewSession(nil).Dialect)
+
+	conn, err = r.RouteWrite("oltp")
+	assert.NoError(t, err)
+	assert.True(t, conn == oltp)
+
+	conn, err = r.RouteRead("oltp")
+	assert.NoError(t, err)
+	assert.True(t, conn == oltpReplica)
+
+	// falls back to primary when there is no replica
+	conn, err = r.RouteRead("olap")
+	assert.NoError(t, err)
+	assert.True(t, conn == olap)
+
+	_, err = r.Route("unknown")
+	assert.Equal(t, ErrRouteNotFound, err)
+	_, err = r.RouteRead("unknown")
+	assert.Equal(t, ErrRouteNotFound, err)
+}
@@ -1,53 +1,111 @@
 package dbr
 
+import (
+	"reflect"
+	"strings"
+)
+
 // SelectStmt builds `SELECT ...`
 type SelectStmt interface {
 	Builder
 
+	Columns(column ...interface{}) SelectStmt
 	From(table interface{}) SelectStmt
 	Distinct() SelectStmt
+	DistinctOn(column ...string) SelectStmt
+	Only() SelectStmt
+	QualifyColumns(alias string) SelectStmt
 	Prewhere(query interface{}, value ...interface{}) SelectStmt
 	Where(query interface{}, value ...interface{}) SelectStmt
+	WhereGroup(fn func(*Conditions)) SelectStmt
 	Having(query interface{}, value ...interface{}) SelectStmt
 	GroupBy(col ...string) SelectStmt
+	WithTotals() SelectStmt
 	OrderAsc(col string) SelectStmt
 	OrderDesc(col string) SelectStmt
+	OrderByAlias(alias string, isAsc bool) SelectStmt
+	OrderBy(query interface{}, value ...interface{}) SelectStmt
 	Limit(n uint64) SelectStmt
 	Offset(n uint64) SelectStmt
+	ClearWhere() SelectStmt
+	ClearOrderBy() SelectStmt
+	ClearLimit() SelectStmt
 	ForUpdate() SelectStmt
+	ForNoKeyUpdate() SelectStmt
+	ForKeyShare() SelectStmt
 	SkipLocked() SelectStmt
+	NoWait() SelectStmt
+	UseIndex(index ...string) SelectStmt
+	ForceIndex(index ...string) SelectStmt
+	TableSample(method string, percent float64) SelectStmt
+	AfterCursor(column []string, cursor string) SelectStmt
 	Join(table, on interface{}) SelectStmt
 	LeftJoin(table, on interface{}) SelectStmt
 	RightJoin(table, on interface{}) SelectStmt
 	FullJoin(table, on interface{}) SelectStmt
+	JoinUsing(table interface{}, column ...string) SelectStmt
+	LeftJoinUsing(table interface{}, column ...string) SelectStmt
+	RightJoinUsing(table interface{}, column ...string) SelectStmt
+	FullJoinUsing(table interface{}, column ...string) SelectStmt
+	LeftJoinLateral(subquery Builder, alias string, on interface{}) SelectStmt
+	CrossJoinLateral(subquery Builder, alias string) SelectStmt
 	AddComment(text string) SelectStmt
+	PlanHint(hint string) SelectStmt
 	As(alias string) Builder
 }
 
 type selectStmt struct {
 	raw
 
-	IsDistinct bool
+	IsDistinct     bool
+	IsOnly         bool
+	DistinctColumn []string
 
 	Column    []interface{}
 	Table     interface{}
+	Sample    *tableSample
+	IndexHint []indexHint
 	JoinTable []Builder
 
+	PlanHints    []string
 	Comment      []Builder
 	PrewhereCond []Builder
 	WhereCond    []Builder
 	Group        []Builder
+	IsWithTotals bool
 	HavingCond   []Builder
 	Order        []Builder
+	// NullsOrdering applies to orderings added by OrderAsc, OrderDesc and OrderByAlias
+	NullsOrdering NullsOrdering
+	// QualifyAlias qualifies bare columns of select and conditions of where
+	QualifyAlias string
+	// StandardLimit writes limit as `OFFSET n ROWS FETCH NEXT m ROWS ONLY` if the dialect supports it
+	StandardLimit bool
 
 	LimitCount   int64
 	OffsetCount  int64
 	IsForUpdate  bool
+	KeyLock      string
 	IsSkipLocked bool
+	IsNoWait     bool
 }
 
 // Build builds `SELECT ...` in dialect
 func (b *selectStmt) Build(d Dialect, buf Buffer) error {
+	if qd, ok := d.(qualifyingDialect); ok {
+		// a subquery of a condition does not qualify its columns with alias of the outer query
+		d = qd.Dialect
+	}
+	if len(b.PlanHints) > 0 {
+		if !d.SupportsPlanHint() {
+			return ErrPlanHintNotSupported
+		}
+		// pg_hint_plan reads hints only from the comment at the head of the query
+		buf.WriteString("/*+ ")
+		buf.WriteString(strings.Join(b.PlanHints, " "))
+		buf.WriteString(" */ ")
+	}
+
 	if b.raw.Query != "" {
 		return b.raw.Build(d, buf)
 	}
@@ -69,7 +127,17 @@ func (b *selectStmt) Build(d Dialect, buf Buffer) error {
 
 	buf.WriteString("SELECT ")
 
-	if b.IsDistinct {
+	if len(b.DistinctColumn) > 0 {
+		if !d.SupportsDistinctOn() {
+			return ErrDistinctOnNotSupported
+		}
+		if err := checkDistinctOn(b.DistinctColumn, b.Order); err != nil {
+			return err
+		}
+		buf.WriteString("DISTINCT ON (")
+		buf.WriteString(strings.Join(b.DistinctColumn, ", "))
+		buf.WriteString(") ")
+	} else if b.IsDistinct {
 		buf.WriteString("DISTINCT ")
 	}
 
@@ -79,6 +147,9 @@ func (b *selectStmt) Build(d Dialect, buf Buffer) error {
 		}
 		switch col := col.(type) {
 		case string:
+			if b.QualifyAlias != "" {
+				col = qualify(b.QualifyAlias, col)
+			}
 			buf.WriteString(col)
 		default:
 			buf.WriteString(placeholder)
@@ -88,13 +159,43 @@ func (b *selectStmt) Build(d Dialect, buf Buffer) error {
 
 	if b.Table != nil {
 		buf.WriteString(" FROM ")
+		if b.IsOnly {
+			if !d.SupportsOnly() {
+				return ErrOnlyNotSupported
+			}
+			buf.WriteString("ONLY ")
+		}
 		switch table := b.Table.(type) {
 		case string:
 			buf.WriteString(table)
+		case *function:
+			if !d.SupportsTableFunction() {
+				return ErrTableFunctionNotSupported
+			}
+			buf.WriteString(placeholder)
+			buf.WriteValue(table)
 		default:
 			buf.WriteString(placeholder)
 			buf.WriteValue(table)
 		}
+		if b.Sample != nil {
+			err := b.Sample.Build(d, buf)
+			if err != nil {
+				return err
+			}
+		}
+		if len(b.IndexHint) > 0 {
+			if !d.SupportsIndexHint() {
+				return ErrIndexHintNotSupported
+			}
+			for _, hint := range b.IndexHint {
+				buf.WriteString(" ")
+				buf.WriteString(hint.Keyword)
+				buf.WriteString(" (")
+				buf.WriteString(quoteIdentList(d, hint.Index))
+				buf.WriteString(")")
+			}
+		}
 		if len(b.JoinTable) > 0 {
 			for _, join := range b.JoinTable {
 				err := join.Build(d, buf)
@@ -122,7 +223,11 @@ func (b *selectStmt) Build(d Dialect, buf Buffer) error {
 
 	if len(b.WhereCond) > 0 {
 		buf.WriteString(" WHERE ")
-		err := And(b.WhereCond...).Build(d, buf)
+		var wd Dialect = d
+		if b.QualifyAlias != "" {
+			wd = qualifyingDialect{Dialect: d, alias: b.QualifyAlias}
+		}
+		err := And(b.WhereCond...).Build(wd, buf)
 		if err != nil {
 			return err
 		}
@@ -139,6 +244,12 @@ func (b *selectStmt) Build(d Dialect, buf Buffer) error {
 				return err
 			}
 		}
+		if b.IsWithTotals {
+			if !d.SupportsWithTotals() {
+				return ErrWithTotalsNotSupported
+			}
+			buf.WriteString(" WITH TOTALS")
+		}
 	}
 
 	if len(b.HavingCond) > 0 {
@@ -155,7 +266,12 @@ func (b *selectStmt) Build(d Dialect, buf Buffer) error {
 			if i > 0 {
 				buf.WriteString(", ")
 			}
-			err := order.Build(d, buf)
+			var err error
+			if o, ok := order.(*orderBy); ok {
+				err = o.build(d, buf, b.NullsOrdering)
+			} else {
+				err = order.Build(d, buf)
+			}
 			if err != nil {
 				return err
 			}
@@ -163,16 +279,31 @@ func (b *selectStmt) Build(d Dialect, buf Buffer) error {
 	}
 
 	if b.LimitCount >= 0 {
+		limit := ""
+		if b.StandardLimit {
+			limit = d.StandardLimit(b.OffsetCount, b.LimitCount)
+		}
+		if limit == "" {
+			limit = d.Limit(b.OffsetCount, b.LimitCount)
+		}
 		buf.WriteString(" ")
-		buf.WriteString(d.Limit(b.OffsetCount, b.LimitCount))
+		buf.WriteString(limit)
 	}
 
 	if b.IsForUpdate {
 		buf.WriteString(" FOR UPDATE")
+	} else if b.KeyLock != "" {
+		if !d.SupportsKeyLock() {
+			return ErrKeyLockNotSupported
+		}
+		buf.WriteString(" FOR ")
+		buf.WriteString(b.KeyLock)
 	}
 
 	if b.IsSkipLocked {
 		buf.WriteString(" SKIP LOCKED")
+	} else if b.IsNoWait {
+		buf.WriteString(" NOWAIT")
 	}
 
 	return nil
@@ -191,6 +322,31 @@ func createSelectStmt(column []interface{}) *selectStmt {
 	}
 }
 
+// SelectStruct creates a SelectStmt selecting columns of struct fields, e.g. `SELECT "u"."id", "u"."name"`,
+// columns are qualified by alias unless it is empty
+func SelectStruct(value interface{}, alias string) SelectStmt {
+	return createSelectStmt(structSelectColumns(value, alias))
+}
+
+func structSelectColumns(value interface{}, alias string) []interface{} {
+	column := structColumns(reflect.TypeOf(value))
+	ident := make([]interface{}, len(column))
+	for i, col := range column {
+		if alias != "" {
+			col = alias + "." + col
+		}
+		ident[i] = I(col)
+	}
+	return ident
+}
+
+// Columns appends columns to the select list, e.g. by helpers adding optional columns,
+// they may be strings and builders like Select arguments, e.g. aggregates
+func (b *selectStmt) Columns(column ...interface{}) SelectStmt {
+	b.Column = append(b.Column, column...)
+	return b
+}
+
 // From specifies table
 func (b *selectStmt) From(table interface{}) SelectStmt {
 	b.Table = table
@@ -219,6 +375,29 @@ func (b *selectStmt) Distinct() SelectStmt {
 	return b
 }
 
+// Only adds `ONLY` before the table, so rows of tables inheriting it are not selected.
+// It is supported by PostgreSQL only, other dialects return ErrOnlyNotSupported
+func (b *selectStmt) Only() SelectStmt {
+	b.IsOnly = true
+	return b
+}
+
+// QualifyColumns qualifies bare column names of select and conditions of where with alias of the primary table,
+// e.g. Select("id").From(As("users", "u")).Join(...).Where(Eq("id", 1)) builds `SELECT u.id ... WHERE ("u"."id" = 1)`,
+// so they are not ambiguous in joins. Qualified columns, expressions and raw conditions are left as is
+func (b *selectStmt) QualifyColumns(alias string) SelectStmt {
+	b.QualifyAlias = alias
+	return b
+}
+
+// DistinctOn adds `DISTINCT ON (a, b)` keeping the first row of each group of rows with equal columns,
+// the leading orderings must be by these columns, which is checked by Build. It is supported
+// by PostgreSQL and ClickHouse, other dialects return ErrDistinctOnNotSupported
+func (b *selectStmt) DistinctOn(column ...string) SelectStmt {
+	b.DistinctColumn = append(b.DistinctColumn, column...)
+	return b
+}
+
 // Prewhere adds a prewhere condition
 // For example clickhouse PREWHERE:
 // https://clickhouse.yandex/docs/en/query_language/select/#prewhere-clause
@@ -243,6 +422,16 @@ func (b *selectStmt) Where(query interface{}, value ...interface{}) SelectStmt {
 	return b
 }
 
+// WhereGroup adds a parenthesized group of where conditions built by fn, e.g.
+// `WHERE ((a = 1) OR (b > 2))` of `WhereGroup(func(w *Conditions) { w.Eq("a", 1).Or().Gt("b", 2) })`,
+// it is skipped if fn adds no conditions
+func (b *selectStmt) WhereGroup(fn func(*Conditions)) SelectStmt {
+	if c := group(fn); !c.empty() {
+		b.WhereCond = append(b.WhereCond, c)
+	}
+	return b
+}
+
 // Having adds a having condition
 func (b *selectStmt) Having(query interface{}, value ...interface{}) SelectStmt {
 	switch query := query.(type) {
@@ -262,6 +451,13 @@ func (b *selectStmt) GroupBy(col ...string) SelectStmt {
 	return b
 }
 
+// WithTotals adds ClickHouse `WITH TOTALS` after GROUP BY, the result has an extra row of totals,
+// other dialects return ErrWithTotalsNotSupported
+func (b *selectStmt) WithTotals() SelectStmt {
+	b.IsWithTotals = true
+	return b
+}
+
 // OrderAsc specifies columns for ordering in asc direction
 func (b *selectStmt) OrderAsc(col string) SelectStmt {
 	b.Order = append(b.Order, order(col, asc))
@@ -274,6 +470,29 @@ func (b *selectStmt) OrderDesc(col string) SelectStmt {
 	return b
 }
 
+// OrderBy specifies column or expression for ordering, values of expression are placed
+// after those of WHERE and HAVING, e.g. OrderBy("position(id IN ?)", ids) or OrderBy(Expr(...))
+func (b *selectStmt) OrderBy(query interface{}, value ...interface{}) SelectStmt {
+	switch query := query.(type) {
+	case string:
+		b.Order = append(b.Order, Expr(query, value...))
+	case Builder:
+		b.Order = append(b.Order, query)
+	}
+	return b
+}
+
+// OrderByAlias specifies ordering by alias of a column made by As, e.g. `count(*) AS "Total"`,
+// the alias is quoted like in As, so the case of it is kept in PostgreSQL
+func (b *selectStmt) OrderByAlias(alias string, isAsc bool) SelectStmt {
+	if isAsc {
+		b.Order = append(b.Order, orderAlias(alias, asc))
+	} else {
+		b.Order = append(b.Order, orderAlias(alias, desc))
+	}
+	return b
+}
+
 // Limit adds LIMIT
 func (b *selectStmt) Limit(n uint64) SelectStmt {
 	b.LimitCount = int64(n)
@@ -286,9 +505,45 @@ func (b *selectStmt) Offset(n uint64) SelectStmt {
 	return b
 }
 
+// ClearWhere removes all where conditions with their values, e.g. to reuse a statement with other conditions
+func (b *selectStmt) ClearWhere() SelectStmt {
+	b.WhereCond = nil
+	return b
+}
+
+// ClearOrderBy removes all orderings with their values
+func (b *selectStmt) ClearOrderBy() SelectStmt {
+	b.Order = nil
+	return b
+}
+
+// ClearLimit removes LIMIT and OFFSET, which works only with LIMIT
+func (b *selectStmt) ClearLimit() SelectStmt {
+	b.LimitCount = -1
+	b.OffsetCount = -1
+	return b
+}
+
 // ForUpdate adds `FOR UPDATE`
 func (b *selectStmt) ForUpdate() SelectStmt {
 	b.IsForUpdate = true
+	b.KeyLock = ""
+	return b
+}
+
+// ForNoKeyUpdate adds PostgreSQL `FOR NO KEY UPDATE`, which does not block inserts referencing the rows,
+// other dialects return ErrKeyLockNotSupported
+func (b *selectStmt) ForNoKeyUpdate() SelectStmt {
+	b.IsForUpdate = false
+	b.KeyLock = "NO KEY UPDATE"
+	return b
+}
+
+// ForKeyShare adds PostgreSQL `FOR KEY SHARE`, which blocks only changes of keys of the rows,
+// other dialects return ErrKeyLockNotSupported
+func (b *selectStmt) ForKeyShare() SelectStmt {
+	b.IsForUpdate = false
+	b.KeyLock = "KEY SHARE"
 	return b
 }
 
@@ -298,6 +553,69 @@ func (b *selectStmt) SkipLocked() SelectStmt {
 	return b
 }
 
+// NoWait adds `NOWAIT`, so the query fails with ErrRowLocked instead of waiting for locked rows
+func (b *selectStmt) NoWait() SelectStmt {
+	b.IsNoWait = true
+	return b
+}
+
+// AfterCursor adds `(a, b) > (?, ?)` condition of keyset pagination with values of the cursor made by EncodeCursor
+// from the last row of previous page, rows must be ordered ascending by the columns.
+// Build returns ErrInvalidCursor if the cursor is malformed or has other number of values
+func (b *selectStmt) AfterCursor(column []string, cursor string) SelectStmt {
+	b.WhereCond = append(b.WhereCond, afterCursor(column, cursor))
+	return b
+}
+
+// indexHint is MySQL `USE INDEX (...)` or `FORCE INDEX (...)` after the table
+type indexHint struct {
+	Keyword string
+	Index   []string
+}
+
+// UseIndex adds MySQL `USE INDEX (...)` hint after the table, other dialects return ErrIndexHintNotSupported
+func (b *selectStmt) UseIndex(index ...string) SelectStmt {
+	b.IndexHint = append(b.IndexHint, indexHint{Keyword: "USE INDEX", Index: index})
+	return b
+}
+
+// ForceIndex adds MySQL `FORCE INDEX (...)` hint after the table, other dialects return ErrIndexHintNotSupported
+func (b *selectStmt) ForceIndex(index ...string) SelectStmt {
+	b.IndexHint = append(b.IndexHint, indexHint{Keyword: "FORCE INDEX", Index: index})
+	return b
+}
+
+// tableSample is `TABLESAMPLE method (percent)` after the table
+type tableSample struct {
+	Method  string
+	Percent float64
+}
+
+func (s *tableSample) Build(d Dialect, buf Buffer) error {
+	method := strings.ToUpper(s.Method)
+	if method != "SYSTEM" && method != "BERNOULLI" {
+		return ErrTableSampleMethod
+	}
+	if !(s.Percent > 0 && s.Percent <= 100) {
+		return ErrTableSamplePercent
+	}
+	sample := d.TableSample(method, s.Percent)
+	if sample == "" {
+		return ErrTableSampleNotSupported
+	}
+	buf.WriteString(" ")
+	buf.WriteString(sample)
+	return nil
+}
+
+// TableSample reads only about percent of rows of the table with PostgreSQL `TABLESAMPLE`,
+// method is SYSTEM or BERNOULLI and percent is in (0, 100]. ClickHouse uses `SAMPLE` for both methods,
+// other dialects return ErrTableSampleNotSupported
+func (b *selectStmt) TableSample(method string, percent float64) SelectStmt {
+	b.Sample = &tableSample{Method: method, Percent: percent}
+	return b
+}
+
 // Join joins table on condition
 func (b *selectStmt) Join(table, on interface{}) SelectStmt {
 	b.JoinTable = append(b.JoinTable, join(inner, table, on))
@@ -322,9 +640,62 @@ func (b *selectStmt) FullJoin(table, on interface{}) SelectStmt {
 	return b
 }
 
-// AddComment adds a comment at the beginning of the query
+// JoinUsing joins table on equality of columns, e.g. `JOIN table USING (id, tenant_id)`
+func (b *selectStmt) JoinUsing(table interface{}, column ...string) SelectStmt {
+	b.JoinTable = append(b.JoinTable, joinUsing(inner, table, column))
+	return b
+}
+
+// LeftJoinUsing joins table on equality of columns via LEFT JOIN
+func (b *selectStmt) LeftJoinUsing(table interface{}, column ...string) SelectStmt {
+	b.JoinTable = append(b.JoinTable, joinUsing(left, table, column))
+	return b
+}
+
+// RightJoinUsing joins table on equality of columns via RIGHT JOIN
+func (b *selectStmt) RightJoinUsing(table interface{}, column ...string) SelectStmt {
+	b.JoinTable = append(b.JoinTable, joinUsing(right, table, column))
+	return b
+}
+
+// FullJoinUsing joins table on equality of columns via FULL JOIN
+func (b *selectStmt) FullJoinUsing(table interface{}, column ...string) SelectStmt {
+	b.JoinTable = append(b.JoinTable, joinUsing(full, table, column))
+	return b
+}
+
+// LeftJoinLateral joins subquery which may reference columns of preceding tables,
+// e.g. `LEFT JOIN LATERAL (SELECT ...) AS "alias" ON true`. Only PostgreSQL supports it,
+// other dialects return ErrLateralJoinNotSupported
+func (b *selectStmt) LeftJoinLateral(subquery Builder, alias string, on interface{}) SelectStmt {
+	b.JoinTable = append(b.JoinTable, joinLateral(left, subquery, alias, on))
+	return b
+}
+
+// CrossJoinLateral joins subquery which may reference columns of preceding tables
+// via CROSS JOIN LATERAL, so rows without a match in subquery are left out
+func (b *selectStmt) CrossJoinLateral(subquery Builder, alias string) SelectStmt {
+	b.JoinTable = append(b.JoinTable, joinLateral(cross, subquery, alias, nil))
+	return b
+}
+
+// AddComment adds a comment at the beginning of the query, it is written as is
 func (b *selectStmt) AddComment(comment string) SelectStmt {
-	b.Comment = append(b.Comment, Expr(comment))
+	b.Comment = append(b.Comment, BuildFunc(func(_ Dialect, buf Buffer) error {
+		buf.WriteString(comment)
+		return nil
+	}))
+	return b
+}
+
+// PlanHint adds a pg_hint_plan hint, e.g. "IndexScan(users users_email_idx)", hints are written
+// in `/*+ ... */` at the head of the query, so the statement must not be a subquery.
+// Comment delimiters are removed from hint, other dialects return ErrPlanHintNotSupported
+func (b *selectStmt) PlanHint(hint string) SelectStmt {
+	for strings.Contains(hint, "/*") || strings.Contains(hint, "*/") {
+		hint = strings.Replace(strings.Replace(hint, "/*", "", -1), "*/", "", -1)
+	}
+	b.PlanHints = append(b.PlanHints, hint)
 	return b
 }
 
@@ -332,3 +703,31 @@ func (b *selectStmt) AddComment(comment string) SelectStmt {
 func (b *selectStmt) As(alias string) Builder {
 	return as(b, alias)
 }
+
+// Subquery makes a scalar subquery of query, e.g. in the select list, its values are interpolated in place.
+// It is `(SELECT ...)` with parentheses for any Builder, e.g. SelectBuilder of a session, unlike placeholders
+// of interpolation, which add them only to SelectStmt
+func Subquery(query Builder) interface {
+	Builder
+	As(string) Builder
+} {
+	return &subquery{query: query}
+}
+
+type subquery struct {
+	query Builder
+}
+
+func (s *subquery) Build(d Dialect, buf Buffer) error {
+	buf.WriteString("(")
+	if err := s.query.Build(d, buf); err != nil {
+		return err
+	}
+	buf.WriteString(")")
+	return nil
+}
+
+// As creates alias for the subquery, e.g. for a column
+func (s *subquery) As(alias string) Builder {
+	return as(s, alias)
+}
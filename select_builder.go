@@ -2,7 +2,10 @@ package dbr
 
 import (
 	"context"
+	"database/sql"
+	"io"
 	"reflect"
+	"sync"
 	"time"
 )
 
@@ -13,28 +16,65 @@ type SelectBuilder interface {
 	loader
 	typesLoader
 
+	AfterCursor(column []string, cursor string) SelectBuilder
 	As(alias string) Builder
+	ClearLimit() SelectBuilder
+	ClearOrderBy() SelectBuilder
+	ClearWhere() SelectBuilder
+	Columns(column ...interface{}) SelectBuilder
 	Comment(text string) SelectBuilder
+	CrossJoinLateral(subquery Builder, alias string) SelectBuilder
 	Distinct() SelectBuilder
+	DistinctOn(column ...string) SelectBuilder
+	Explain(ctx context.Context) ([]string, error)
+	ExplainAnalyze(ctx context.Context) ([]string, error)
+	ForKeyShare() SelectBuilder
+	ForNoKeyUpdate() SelectBuilder
 	ForUpdate() SelectBuilder
+	ForceIndex(index ...string) SelectBuilder
 	From(table interface{}) SelectBuilder
 	FullJoin(table, on interface{}) SelectBuilder
+	FullJoinUsing(table interface{}, column ...string) SelectBuilder
 	GroupBy(col ...string) SelectBuilder
 	Having(query interface{}, value ...interface{}) SelectBuilder
 	InTimezone(loc *time.Location) SelectBuilder
 	Join(table, on interface{}) SelectBuilder
+	JoinUsing(table interface{}, column ...string) SelectBuilder
 	LeftJoin(table, on interface{}) SelectBuilder
+	LeftJoinLateral(subquery Builder, alias string, on interface{}) SelectBuilder
+	LeftJoinUsing(table interface{}, column ...string) SelectBuilder
 	Limit(n uint64) SelectBuilder
+	LoadEach(ctx context.Context, value interface{}, fn func() error) (int, error)
+	LoadStructsPooled(ctx context.Context, pool *sync.Pool, fn func(value interface{}) error) (int, error)
+	LoadStructStrict(ctx context.Context, value interface{}) error
+	LoadWithTotals(ctx context.Context, value interface{}, totals interface{}) (int, error)
+	LoadWithHash(ctx context.Context, value interface{}) (string, int, error)
+	CountDistinct(ctx context.Context, column ...string) (int64, error)
+	NoWait() SelectBuilder
+	NullRowsAsNil() SelectBuilder
 	Offset(n uint64) SelectBuilder
+	Only() SelectBuilder
 	OrderAsc(col string) SelectBuilder
-	OrderBy(col string) SelectBuilder
+	OrderBy(query interface{}, value ...interface{}) SelectBuilder
+	OrderByAlias(alias string, isAsc bool) SelectBuilder
 	OrderDesc(col string) SelectBuilder
 	OrderDir(col string, isAsc bool) SelectBuilder
 	Paginate(page, perPage uint64) SelectBuilder
+	PlanHint(hint string) SelectBuilder
 	Prewhere(query interface{}, value ...interface{}) SelectBuilder
+	QualifyColumns(alias string) SelectBuilder
 	RightJoin(table, on interface{}) SelectBuilder
+	RightJoinUsing(table interface{}, column ...string) SelectBuilder
 	SkipLocked() SelectBuilder
+	TableSample(method string, percent float64) SelectBuilder
+	UseIndex(index ...string) SelectBuilder
 	Where(query interface{}, value ...interface{}) SelectBuilder
+	WhereGroup(fn func(*Conditions)) SelectBuilder
+	WhereInTemp(column string, value interface{}) SelectBuilder
+	WithEventKv(key, value string) SelectBuilder
+	WithTotals() SelectBuilder
+	WriteCSV(ctx context.Context, w io.Writer) (int, error)
+	WriteCSVWithOpts(ctx context.Context, w io.Writer, opts CSVOptions) (int, error)
 }
 
 type selectBuilder struct {
@@ -44,6 +84,10 @@ type selectBuilder struct {
 	Dialect    Dialect
 	selectStmt *selectStmt
 	timezone   *time.Location
+	eventKvs   kvs
+
+	nullRowsAsNil bool
+	inTemp        []inTemp
 }
 
 func prepareSelect(a []string) []interface{} {
@@ -55,22 +99,42 @@ func prepareSelect(a []string) []interface{} {
 }
 
 // Select creates a SelectBuilder
-func (sess *Session) Select(column ...string) SelectBuilder {
+func (sess *Session) Select(column ...interface{}) SelectBuilder {
+	return &selectBuilder{
+		runner:        sess,
+		EventReceiver: sess.EventReceiver,
+		Dialect:       sess.Dialect,
+		selectStmt:    createSelectStmt(column),
+	}
+}
+
+// SelectStruct creates a SelectBuilder selecting columns of struct fields qualified by alias unless it is empty
+func (sess *Session) SelectStruct(value interface{}, alias string) SelectBuilder {
 	return &selectBuilder{
 		runner:        sess,
 		EventReceiver: sess.EventReceiver,
 		Dialect:       sess.Dialect,
-		selectStmt:    createSelectStmt(prepareSelect(column)),
+		selectStmt:    createSelectStmt(structSelectColumns(value, alias)),
+	}
+}
+
+// SelectStruct creates a SelectBuilder selecting columns of struct fields qualified by alias unless it is empty
+func (tx *Tx) SelectStruct(value interface{}, alias string) SelectBuilder {
+	return &selectBuilder{
+		runner:        tx,
+		EventReceiver: tx.EventReceiver,
+		Dialect:       tx.Dialect,
+		selectStmt:    createSelectStmt(structSelectColumns(value, alias)),
 	}
 }
 
 // Select creates a SelectBuilder
-func (tx *Tx) Select(column ...string) SelectBuilder {
+func (tx *Tx) Select(column ...interface{}) SelectBuilder {
 	return &selectBuilder{
 		runner:        tx,
 		EventReceiver: tx.EventReceiver,
 		Dialect:       tx.Dialect,
-		selectStmt:    createSelectStmt(prepareSelect(column)),
+		selectStmt:    createSelectStmt(column),
 	}
 }
 
@@ -119,6 +183,17 @@ func (b *selectBuilder) changeTimezone(value reflect.Value) {
 }
 
 func (b *selectBuilder) Build(d Dialect, buf Buffer) error {
+	nulls := b.selectStmt.NullsOrdering
+	if nulls == NullsDefault {
+		nulls = nullsOrdering(b.runner)
+	}
+	standard := b.selectStmt.StandardLimit || standardLimitSyntax(b.runner)
+	if nulls != b.selectStmt.NullsOrdering || standard != b.selectStmt.StandardLimit {
+		stmt := *b.selectStmt
+		stmt.NullsOrdering = nulls
+		stmt.StandardLimit = standard
+		return stmt.Build(d, buf)
+	}
 	return b.selectStmt.Build(d, buf)
 }
 
@@ -129,13 +204,28 @@ func (b *selectBuilder) Load(value interface{}) (int, error) {
 
 // LoadContext loads any value from query result
 func (b *selectBuilder) LoadContext(ctx context.Context, value interface{}) (int, error) {
-	c, err := query(ctx, b.runner, b.EventReceiver, b, b.Dialect, value)
+	c, err := b.load(ctx, value)
 	if err == nil && b.timezone != nil {
 		b.changeTimezone(reflect.ValueOf(value))
 	}
 	return c, err
 }
 
+// load loads value from query result
+func (b *selectBuilder) load(ctx context.Context, value interface{}) (int, error) {
+	return b.queryRows(ctx, func(rows *sql.Rows) (int, error) {
+		return load(rows, value, b.nullRowsAsNil, false)
+	})
+}
+
+// queryRows runs query and scans its rows, all methods loading rows use it to apply conditions of WhereInTemp
+func (b *selectBuilder) queryRows(ctx context.Context, scan func(rows *sql.Rows) (int, error)) (int, error) {
+	if len(b.inTemp) > 0 {
+		return b.queryInTemp(ctx, scan)
+	}
+	return queryRows(ctx, b.runner, b.EventReceiver, b, b.Dialect, b.eventKvs, scan)
+}
+
 // LoadStruct loads struct from query result with background context, returns ErrNotFound if there is no result
 func (b *selectBuilder) LoadStruct(value interface{}) error {
 	return b.LoadStructContext(context.Background(), value)
@@ -143,7 +233,25 @@ func (b *selectBuilder) LoadStruct(value interface{}) error {
 
 // LoadStructContext loads struct from query result, returns ErrNotFound if there is no result
 func (b *selectBuilder) LoadStructContext(ctx context.Context, value interface{}) error {
-	count, err := query(ctx, b.runner, b.EventReceiver, b, b.Dialect, value)
+	count, err := b.load(ctx, value)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrNotFound
+	}
+	if b.timezone != nil {
+		b.changeTimezone(reflect.ValueOf(value))
+	}
+	return nil
+}
+
+// LoadStructStrict is like LoadStructContext, but returns an error if a column of the result
+// has no field in the struct instead of skipping it, it catches typos in column names
+func (b *selectBuilder) LoadStructStrict(ctx context.Context, value interface{}) error {
+	count, err := b.queryRows(ctx, func(rows *sql.Rows) (int, error) {
+		return load(rows, value, b.nullRowsAsNil, true)
+	})
 	if err != nil {
 		return err
 	}
@@ -163,7 +271,7 @@ func (b *selectBuilder) LoadStructs(value interface{}) (int, error) {
 
 // LoadStructsContext loads structures from query result
 func (b *selectBuilder) LoadStructsContext(ctx context.Context, value interface{}) (int, error) {
-	c, err := query(ctx, b.runner, b.EventReceiver, b, b.Dialect, value)
+	c, err := b.load(ctx, value)
 	if err == nil && b.timezone != nil {
 		b.changeTimezone(reflect.ValueOf(value))
 	}
@@ -177,7 +285,7 @@ func (b *selectBuilder) LoadValue(value interface{}) error {
 
 // LoadValueContext loads any value from query result, returns ErrNotFound if there is no result
 func (b *selectBuilder) LoadValueContext(ctx context.Context, value interface{}) error {
-	count, err := query(ctx, b.runner, b.EventReceiver, b, b.Dialect, value)
+	count, err := b.load(ctx, value)
 	if err != nil {
 		return err
 	}
@@ -195,15 +303,106 @@ func (b *selectBuilder) LoadValues(value interface{}) (int, error) {
 	return b.LoadValuesContext(context.Background(), value)
 }
 
-// LoadValuesContext loads any values from query result
+// LoadValuesContext loads any values from query result. A slice is truncated first and its backing array
+// is reused while it has capacity, so loading into the same slice repeatedly, e.g. in a polling loop, does not allocate it
 func (b *selectBuilder) LoadValuesContext(ctx context.Context, value interface{}) (int, error) {
-	c, err := query(ctx, b.runner, b.EventReceiver, b, b.Dialect, value)
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Ptr && !v.IsNil() &&
+		v.Elem().Kind() == reflect.Slice && v.Elem().Type().Elem().Kind() != reflect.Uint8 {
+		v.Elem().SetLen(0)
+	}
+	c, err := b.load(ctx, value)
 	if err == nil && b.timezone != nil {
 		b.changeTimezone(reflect.ValueOf(value))
 	}
 	return c, err
 }
 
+// LoadWithTotals loads rows of query with WithTotals into slice value and its totals row into totals,
+// which must be a pointer to an element of the slice. The driver returns totals as the last row, which is
+// separated from the rest, so the count does not include it
+func (b *selectBuilder) LoadWithTotals(ctx context.Context, value interface{}, totals interface{}) (int, error) {
+	v := reflect.ValueOf(value)
+	t := reflect.ValueOf(totals)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Slice ||
+		t.Kind() != reflect.Ptr || t.IsNil() || t.Elem().Type() != v.Elem().Type().Elem() {
+		return 0, ErrInvalidPointer
+	}
+	count, err := b.load(ctx, value)
+	if err != nil || count == 0 {
+		return count, err
+	}
+	rows := v.Elem()
+	t.Elem().Set(rows.Index(rows.Len() - 1))
+	rows.SetLen(rows.Len() - 1)
+	if b.timezone != nil {
+		b.changeTimezone(v)
+		b.changeTimezone(t)
+	}
+	return count - 1, nil
+}
+
+// CountDistinct returns the number of distinct values of columns in the rows of the query,
+// e.g. `SELECT COUNT(DISTINCT a, b) FROM ...`. The query is built without its columns, ordering and limit,
+// so it should not be grouped. Several columns are counted as `COUNT(DISTINCT (a, b))` in PostgreSQL
+// and as `SELECT COUNT(*) FROM (SELECT DISTINCT a, b FROM ...)` in SQLite
+func (b *selectBuilder) CountDistinct(ctx context.Context, column ...string) (int64, error) {
+	if len(column) == 0 {
+		return 0, ErrColumnNotSpecified
+	}
+	stmt := *b.selectStmt
+	stmt.Order = nil
+	stmt.LimitCount = -1
+	stmt.OffsetCount = -1
+	count := b.Dialect.CountDistinct(column)
+	if count != "" {
+		stmt.Column = []interface{}{count}
+	} else {
+		inner := stmt
+		inner.Column = make([]interface{}, len(column))
+		for i, c := range column {
+			inner.Column[i] = c
+		}
+		inner.IsDistinct = true
+		stmt = *createSelectStmt([]interface{}{"COUNT(*)"})
+		stmt.Table = inner.As("dbr_count")
+	}
+	c := *b
+	c.selectStmt = &stmt
+	var n int64
+	err := c.LoadValueContext(ctx, &n)
+	return n, err
+}
+
+// LoadEach loads rows of query result one by one into value and calls fn after each of them,
+// rows are streamed without loading all of them. Unlike Load, value may hold sql.RawBytes
+// to avoid a copy, they are valid only until fn returns
+func (b *selectBuilder) LoadEach(ctx context.Context, value interface{}, fn func() error) (int, error) {
+	return b.queryRows(ctx, func(rows *sql.Rows) (int, error) {
+		return loadEach(rows, value, func() error {
+			if b.timezone != nil {
+				b.changeTimezone(reflect.ValueOf(value))
+			}
+			return fn()
+		})
+	})
+}
+
+// LoadStructsPooled streams rows of query result into pointers to structs obtained from pool and calls fn
+// with each of them, e.g. *User of pool with `New: func() interface{} { return new(User) }`.
+// The struct is reset to zero value before a row is scanned into it and put back into pool after fn returns,
+// so fn must not keep it or its pointer fields after return, it should copy what it needs.
+// It returns ErrInvalidPointer if pool returns nil or a value which is not a pointer to struct
+func (b *selectBuilder) LoadStructsPooled(ctx context.Context, pool *sync.Pool, fn func(value interface{}) error) (int, error) {
+	return b.queryRows(ctx, func(rows *sql.Rows) (int, error) {
+		return loadPooled(rows, pool, func(value interface{}) error {
+			if b.timezone != nil {
+				b.changeTimezone(reflect.ValueOf(value))
+			}
+			return fn(value)
+		})
+	})
+}
+
 // Join joins table on condition
 func (b *selectBuilder) Join(table, on interface{}) SelectBuilder {
 	b.selectStmt.Join(table, on)
@@ -228,12 +427,74 @@ func (b *selectBuilder) FullJoin(table, on interface{}) SelectBuilder {
 	return b
 }
 
+// JoinUsing joins table on equality of columns, e.g. `JOIN table USING (id, tenant_id)`
+func (b *selectBuilder) JoinUsing(table interface{}, column ...string) SelectBuilder {
+	b.selectStmt.JoinUsing(table, column...)
+	return b
+}
+
+// LeftJoinUsing joins table on equality of columns via LEFT JOIN
+func (b *selectBuilder) LeftJoinUsing(table interface{}, column ...string) SelectBuilder {
+	b.selectStmt.LeftJoinUsing(table, column...)
+	return b
+}
+
+// RightJoinUsing joins table on equality of columns via RIGHT JOIN
+func (b *selectBuilder) RightJoinUsing(table interface{}, column ...string) SelectBuilder {
+	b.selectStmt.RightJoinUsing(table, column...)
+	return b
+}
+
+// FullJoinUsing joins table on equality of columns via FULL JOIN
+func (b *selectBuilder) FullJoinUsing(table interface{}, column ...string) SelectBuilder {
+	b.selectStmt.FullJoinUsing(table, column...)
+	return b
+}
+
+// LeftJoinLateral joins subquery which may reference columns of preceding tables,
+// e.g. `LEFT JOIN LATERAL (SELECT ...) AS "alias" ON true`. Only PostgreSQL supports it
+func (b *selectBuilder) LeftJoinLateral(subquery Builder, alias string, on interface{}) SelectBuilder {
+	b.selectStmt.LeftJoinLateral(subquery, alias, on)
+	return b
+}
+
+// CrossJoinLateral joins subquery which may reference columns of preceding tables via CROSS JOIN LATERAL
+func (b *selectBuilder) CrossJoinLateral(subquery Builder, alias string) SelectBuilder {
+	b.selectStmt.CrossJoinLateral(subquery, alias)
+	return b
+}
+
 // Distinct adds `DISTINCT`
 func (b *selectBuilder) Distinct() SelectBuilder {
 	b.selectStmt.Distinct()
 	return b
 }
 
+// Columns appends columns to the select list, e.g. by helpers adding optional columns,
+// a column is a string or a Builder, e.g. Count("*")
+func (b *selectBuilder) Columns(column ...interface{}) SelectBuilder {
+	b.selectStmt.Columns(column...)
+	return b
+}
+
+// DistinctOn adds `DISTINCT ON (a, b)`, the leading orderings must be by these columns
+func (b *selectBuilder) DistinctOn(column ...string) SelectBuilder {
+	b.selectStmt.DistinctOn(column...)
+	return b
+}
+
+// Only adds `ONLY` before the table, so rows of tables inheriting it are not selected, PostgreSQL only
+func (b *selectBuilder) Only() SelectBuilder {
+	b.selectStmt.Only()
+	return b
+}
+
+// QualifyColumns qualifies bare column names of select and conditions of where with alias of the primary table
+func (b *selectBuilder) QualifyColumns(alias string) SelectBuilder {
+	b.selectStmt.QualifyColumns(alias)
+	return b
+}
+
 // From specifies table
 func (b *selectBuilder) From(table interface{}) SelectBuilder {
 	b.selectStmt.From(table)
@@ -246,6 +507,12 @@ func (b *selectBuilder) GroupBy(col ...string) SelectBuilder {
 	return b
 }
 
+// WithTotals adds ClickHouse WITH TOTALS after GROUP BY, use LoadWithTotals to load the totals row
+func (b *selectBuilder) WithTotals() SelectBuilder {
+	b.selectStmt.WithTotals()
+	return b
+}
+
 // Having adds a having condition
 func (b *selectBuilder) Having(query interface{}, value ...interface{}) SelectBuilder {
 	b.selectStmt.Having(query, value...)
@@ -264,6 +531,24 @@ func (b *selectBuilder) Offset(n uint64) SelectBuilder {
 	return b
 }
 
+// ClearWhere removes all where conditions with their values
+func (b *selectBuilder) ClearWhere() SelectBuilder {
+	b.selectStmt.ClearWhere()
+	return b
+}
+
+// ClearOrderBy removes all orderings with their values
+func (b *selectBuilder) ClearOrderBy() SelectBuilder {
+	b.selectStmt.ClearOrderBy()
+	return b
+}
+
+// ClearLimit removes LIMIT and OFFSET
+func (b *selectBuilder) ClearLimit() SelectBuilder {
+	b.selectStmt.ClearLimit()
+	return b
+}
+
 // OrderDir specifies columns for ordering in direction
 func (b *selectBuilder) OrderDir(col string, isAsc bool) SelectBuilder {
 	if isAsc {
@@ -281,9 +566,15 @@ func (b *selectBuilder) Paginate(page, perPage uint64) SelectBuilder {
 	return b
 }
 
-// OrderBy specifies column for ordering
-func (b *selectBuilder) OrderBy(col string) SelectBuilder {
-	b.selectStmt.Order = append(b.selectStmt.Order, Expr(col))
+// OrderBy specifies column or expression for ordering, e.g. OrderBy("position(id IN ?)", ids)
+func (b *selectBuilder) OrderBy(query interface{}, value ...interface{}) SelectBuilder {
+	b.selectStmt.OrderBy(query, value...)
+	return b
+}
+
+// OrderByAlias specifies ordering by alias of a column made by As, it is quoted like in As
+func (b *selectBuilder) OrderByAlias(alias string, isAsc bool) SelectBuilder {
+	b.selectStmt.OrderByAlias(alias, isAsc)
 	return b
 }
 
@@ -299,18 +590,73 @@ func (b *selectBuilder) Where(query interface{}, value ...interface{}) SelectBui
 	return b
 }
 
+// WhereGroup adds a parenthesized group of where conditions built by fn
+func (b *selectBuilder) WhereGroup(fn func(*Conditions)) SelectBuilder {
+	b.selectStmt.WhereGroup(fn)
+	return b
+}
+
 // ForUpdate adds lock via FOR UPDATE
 func (b *selectBuilder) ForUpdate() SelectBuilder {
 	b.selectStmt.ForUpdate()
 	return b
 }
 
+// ForNoKeyUpdate adds weaker lock via FOR NO KEY UPDATE, only PostgreSQL supports it
+func (b *selectBuilder) ForNoKeyUpdate() SelectBuilder {
+	b.selectStmt.ForNoKeyUpdate()
+	return b
+}
+
+// ForKeyShare adds weaker lock via FOR KEY SHARE, only PostgreSQL supports it
+func (b *selectBuilder) ForKeyShare() SelectBuilder {
+	b.selectStmt.ForKeyShare()
+	return b
+}
+
 // SkipLocked skips locked rows via SKIP LOCKED
 func (b *selectBuilder) SkipLocked() SelectBuilder {
 	b.selectStmt.SkipLocked()
 	return b
 }
 
+// NoWait fails the query with ErrRowLocked via NOWAIT instead of waiting for locked rows
+func (b *selectBuilder) NoWait() SelectBuilder {
+	b.selectStmt.NoWait()
+	return b
+}
+
+// AfterCursor adds condition of keyset pagination selecting rows after the cursor made by EncodeCursor
+func (b *selectBuilder) AfterCursor(column []string, cursor string) SelectBuilder {
+	b.selectStmt.AfterCursor(column, cursor)
+	return b
+}
+
+// UseIndex adds MySQL USE INDEX hint after the table
+func (b *selectBuilder) UseIndex(index ...string) SelectBuilder {
+	b.selectStmt.UseIndex(index...)
+	return b
+}
+
+// ForceIndex adds MySQL FORCE INDEX hint after the table
+func (b *selectBuilder) ForceIndex(index ...string) SelectBuilder {
+	b.selectStmt.ForceIndex(index...)
+	return b
+}
+
+// TableSample reads only about percent of rows of the table with PostgreSQL TABLESAMPLE or ClickHouse SAMPLE
+func (b *selectBuilder) TableSample(method string, percent float64) SelectBuilder {
+	b.selectStmt.TableSample(method, percent)
+	return b
+}
+
+// NullRowsAsNil makes loading into a slice of pointers to structs append nil for rows with all columns NULL,
+// e.g. for rows of LEFT JOIN without a match
+func (b *selectBuilder) NullRowsAsNil() SelectBuilder {
+	b.nullRowsAsNil = true
+	return b
+}
+
 // InTimezone all time.Time fields in the result will be returned with the specified location.
 func (b *selectBuilder) InTimezone(loc *time.Location) SelectBuilder {
 	b.timezone = loc
@@ -337,3 +683,19 @@ func (b *selectBuilder) Comment(text string) SelectBuilder {
 	b.selectStmt.AddComment(text)
 	return b
 }
+
+// PlanHint adds a pg_hint_plan hint written in `/*+ ... */` at the head of the query
+func (b *selectBuilder) PlanHint(hint string) SelectBuilder {
+	b.selectStmt.PlanHint(hint)
+	return b
+}
+
+// WithEventKv adds a key/value pair to the events of this query,
+// it never overwrites the keys set by dbr itself, e.g. "sql"
+func (b *selectBuilder) WithEventKv(key, value string) SelectBuilder {
+	if b.eventKvs == nil {
+		b.eventKvs = make(kvs)
+	}
+	b.eventKvs[key] = value
+	return b
+}
@@ -1,10 +1,18 @@
 package dbr
 
 import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
 	"reflect"
+	"regexp"
 	"testing"
 	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/mailru/dbr/dialect"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -72,3 +80,227 @@ func TestChangeTimezone(t *testing.T) {
 		assert.Equal(t, "America/New_York", tt.InnerTime.Location().String())
 	}
 }
+
+func TestSessionNullsOrdering(t *testing.T) {
+	sess, dbmock, _ := newRecordingSessionMock()
+	sess.NullsOrdering = NullsLast
+	dbmock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM users ORDER BY deleted_at IS NULL ASC, deleted_at ASC")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	dbmock.ExpectBegin()
+	dbmock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM users ORDER BY deleted_at IS NULL ASC, deleted_at DESC")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	var ids []int
+	_, err := sess.Select("id").From("users").OrderAsc("deleted_at").Load(&ids)
+	assert.NoError(t, err)
+	tx, err := sess.Begin()
+	assert.NoError(t, err)
+	_, err = tx.Select("id").From("users").OrderDesc("deleted_at").Load(&ids)
+	assert.NoError(t, err)
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+}
+
+func TestSessionStandardLimitSyntax(t *testing.T) {
+	db, dbmock, err := sqlmock.New()
+	assert.NoError(t, err)
+	conn := &Connection{DB: db, Dialect: dialect.PostgreSQL, EventReceiver: nullReceiver}
+	sess := conn.NewSession(nil)
+	sess.StandardLimitSyntax = true
+	dbmock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM users ORDER BY id OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	dbmock.ExpectBegin()
+	dbmock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM users ORDER BY id FETCH NEXT 1 ROWS ONLY")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	var ids []int
+	_, err = sess.Select("id").From("users").OrderBy("id").Limit(10).Offset(20).Load(&ids)
+	assert.NoError(t, err)
+	tx, err := sess.Begin()
+	assert.NoError(t, err)
+	_, err = tx.Select("id").From("users").OrderBy("id").Limit(1).Load(&ids)
+	assert.NoError(t, err)
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+
+	// LIMIT is the default and it is used by dialects without the standard syntax
+	for _, test := range []struct {
+		d        Dialect
+		standard bool
+		query    string
+	}{
+		{d: dialect.PostgreSQL, query: "SELECT id FROM users LIMIT 10 OFFSET 20"},
+		{d: dialect.MySQL, standard: true, query: "SELECT id FROM users LIMIT 20,10"},
+		{d: dialect.SQLite3, standard: true, query: "SELECT id FROM users LIMIT 10 OFFSET 20"},
+	} {
+		sess := (&Connection{Dialect: test.d}).NewSession(nil)
+		sess.StandardLimitSyntax = test.standard
+		buf := NewBuffer()
+		err := sess.Select("id").From("users").Limit(10).Offset(20).Build(test.d, buf)
+		assert.NoError(t, err)
+		assert.Equal(t, test.query, buf.String())
+	}
+}
+
+func TestLoadWithTotals(t *testing.T) {
+	type hits struct {
+		Domain string
+		Count  int
+	}
+	db, dbmock, err := sqlmock.New()
+	assert.NoError(t, err)
+	conn := Connection{DB: db, Dialect: dialect.ClickHouse, EventReceiver: nullReceiver}
+	sess := conn.NewSession(nil)
+
+	// the driver returns totals after the rows
+	dbmock.ExpectQuery(regexp.QuoteMeta("SELECT domain, count() AS count FROM hits GROUP BY domain WITH TOTALS")).
+		WillReturnRows(sqlmock.NewRows([]string{"domain", "count"}).AddRow("a.ru", 2).AddRow("b.ru", 3).AddRow("", 5))
+	var rows []hits
+	var totals hits
+	n, err := sess.Select("domain", "count() AS count").From("hits").GroupBy("domain").WithTotals().
+		LoadWithTotals(context.Background(), &rows, &totals)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []hits{{"a.ru", 2}, {"b.ru", 3}}, rows)
+	assert.Equal(t, hits{Count: 5}, totals)
+
+	dbmock.ExpectQuery("SELECT .+").WillReturnRows(sqlmock.NewRows([]string{"domain", "count"}))
+	rows, totals = nil, hits{}
+	n, err = sess.Select("domain", "count() AS count").From("hits").GroupBy("domain").WithTotals().
+		LoadWithTotals(context.Background(), &rows, &totals)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+	assert.Empty(t, rows)
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+
+	var count int
+	_, err = sess.Select("count()").From("hits").WithTotals().LoadWithTotals(context.Background(), &rows, &count)
+	assert.Equal(t, ErrInvalidPointer, err)
+}
+
+// cancelTestRows returns values until the limit and records whether the query
+// was cancelled when the rows were closed
+type cancelTestRows struct {
+	value     string
+	limit     int
+	n         int
+	ctx       context.Context
+	closed    bool
+	closedErr error
+}
+
+func (r *cancelTestRows) Columns() []string { return []string{"n"} }
+
+func (r *cancelTestRows) Close() error {
+	r.closed = true
+	r.closedErr = r.ctx.Err()
+	return nil
+}
+
+func (r *cancelTestRows) Next(dest []driver.Value) error {
+	if r.n >= r.limit {
+		return io.EOF
+	}
+	r.n++
+	dest[0] = r.value
+	return nil
+}
+
+type cancelTestConn struct {
+	rows *cancelTestRows
+}
+
+func (c cancelTestConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+func (c cancelTestConn) Close() error              { return nil }
+func (c cancelTestConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+func (c cancelTestConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.rows.ctx = ctx
+	return c.rows, nil
+}
+
+type cancelTestConnector struct {
+	rows *cancelTestRows
+}
+
+func (c cancelTestConnector) Connect(context.Context) (driver.Conn, error) {
+	return cancelTestConn(c), nil
+}
+func (c cancelTestConnector) Driver() driver.Driver { return nil }
+
+func TestLoadCancelOnError(t *testing.T) {
+	newSession := func(rows *cancelTestRows) *Session {
+		conn := Connection{DB: sql.OpenDB(cancelTestConnector{rows: rows}), Dialect: dialect.MySQL, EventReceiver: nullReceiver}
+		return conn.NewSession(nil)
+	}
+
+	// "x" can not be scanned into int, the query is cancelled before rows are closed
+	rows := &cancelTestRows{value: "x", limit: 1000}
+	var values []int
+	_, err := newSession(rows).Select("n").From("t").Load(&values)
+	assert.Error(t, err)
+	assert.Equal(t, 1, rows.n)
+	assert.True(t, rows.closed)
+	assert.Equal(t, context.Canceled, rows.closedErr)
+
+	// an error of the callback of LoadEach cancels the query as well
+	rows = &cancelTestRows{value: "1", limit: 1000}
+	var value int
+	errStop := errors.New("stop")
+	_, err = newSession(rows).Select("n").From("t").LoadEach(context.Background(), &value, func() error {
+		return errStop
+	})
+	assert.Equal(t, errStop, err)
+	assert.Equal(t, 1, rows.n)
+	assert.True(t, rows.closed)
+	assert.Equal(t, context.Canceled, rows.closedErr)
+
+	// rows are closed before the query is done otherwise
+	rows = &cancelTestRows{value: "1", limit: 3}
+	n, err := newSession(rows).Select("n").From("t").Load(&values)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.True(t, rows.closed)
+	assert.NoError(t, rows.closedErr)
+}
+
+func TestCountDistinct(t *testing.T) {
+	for _, test := range []struct {
+		d     Dialect
+		query string
+	}{
+		{d: dialect.MySQL, query: "SELECT COUNT(DISTINCT user_id, day) FROM visits WHERE (`site` = 1)"},
+		{d: dialect.PostgreSQL, query: `SELECT COUNT(DISTINCT (user_id, day)) FROM visits WHERE ("site" = 1)`},
+		{d: dialect.SQLite3, query: `SELECT COUNT(*) FROM (SELECT DISTINCT user_id, day FROM visits WHERE ("site" = 1)) AS "dbr_count"`},
+		{d: dialect.ClickHouse, query: "SELECT COUNT(DISTINCT user_id, day) FROM visits WHERE (`site` = 1)"},
+	} {
+		db, m, err := sqlmock.New()
+		assert.NoError(t, err)
+		sess := (&Connection{DB: db, Dialect: test.d, EventReceiver: nullReceiver}).NewSession(nil)
+		m.ExpectQuery(regexp.QuoteMeta(test.query) + "$").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+		// columns, ordering and limit of the query are not used
+		n, err := sess.Select("*").From("visits").Where(Eq("site", 1)).OrderBy("day").Limit(10).
+			CountDistinct(context.Background(), "user_id", "day")
+		assert.NoError(t, err, test.d)
+		assert.Equal(t, int64(3), n)
+		assert.NoError(t, m.ExpectationsWereMet())
+	}
+
+	sess, m := newSessionMock()
+	m.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(DISTINCT user_id) FROM visits")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	builder := sess.Select("*").From("visits")
+	n, err := builder.CountDistinct(context.Background(), "user_id")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	assert.NoError(t, m.ExpectationsWereMet())
+	// the builder is not changed
+	query, err := InterpolateForDialect("?", []interface{}{builder}, dialect.MySQL)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM visits", query)
+
+	_, err = builder.CountDistinct(context.Background())
+	assert.Equal(t, ErrColumnNotSpecified, err)
+}
@@ -1,5 +1,7 @@
 package dbr
 
+import "context"
+
 //
 // These are a set of helpers that just call LoadValue and return the value.
 // They return (_, ErrNotFound) if nothing was found.
@@ -25,17 +27,29 @@ package dbr
 
 type typesLoader interface {
 	ReturnInt64() (int64, error)
+	Int64(ctx context.Context) (int64, error)
 	ReturnInt64s() ([]int64, error)
 	ReturnUint64() (uint64, error)
 	ReturnUint64s() ([]uint64, error)
+	ReturnFloat64() (float64, error)
+	Float64(ctx context.Context) (float64, error)
 	ReturnString() (string, error)
+	String(ctx context.Context) (string, error)
 	ReturnStrings() ([]string, error)
+	ReturnBool() (bool, error)
+	Bool(ctx context.Context) (bool, error)
 }
 
 // ReturnInt64 executes the SelectStmt and returns the value as an int64
 func (b *selectBuilder) ReturnInt64() (int64, error) {
+	return b.Int64(context.Background())
+}
+
+// Int64 executes the SelectStmt with context and returns the value as an int64,
+// e.g. of Count("*")
+func (b *selectBuilder) Int64(ctx context.Context) (int64, error) {
 	var v int64
-	err := b.LoadValue(&v)
+	err := b.LoadValueContext(ctx, &v)
 	return v, err
 }
 
@@ -60,10 +74,28 @@ func (b *selectBuilder) ReturnUint64s() ([]uint64, error) {
 	return v, err
 }
 
+// ReturnFloat64 executes the SelectStmt and returns the value as a float64
+func (b *selectBuilder) ReturnFloat64() (float64, error) {
+	return b.Float64(context.Background())
+}
+
+// Float64 executes the SelectStmt with context and returns the value as a float64,
+// e.g. of Avg("price")
+func (b *selectBuilder) Float64(ctx context.Context) (float64, error) {
+	var v float64
+	err := b.LoadValueContext(ctx, &v)
+	return v, err
+}
+
 // ReturnString executes the SelectStmt and returns the value as a string
 func (b *selectBuilder) ReturnString() (string, error) {
+	return b.String(context.Background())
+}
+
+// String executes the SelectStmt with context and returns the value as a string
+func (b *selectBuilder) String(ctx context.Context) (string, error) {
 	var v string
-	err := b.LoadValue(&v)
+	err := b.LoadValueContext(ctx, &v)
 	return v, err
 }
 
@@ -73,3 +105,16 @@ func (b *selectBuilder) ReturnStrings() ([]string, error) {
 	_, err := b.LoadValues(&v)
 	return v, err
 }
+
+// ReturnBool executes the SelectStmt and returns the value as a bool
+func (b *selectBuilder) ReturnBool() (bool, error) {
+	return b.Bool(context.Background())
+}
+
+// Bool executes the SelectStmt with context and returns the value as a bool,
+// e.g. of EXISTS (...)
+func (b *selectBuilder) Bool(ctx context.Context) (bool, error) {
+	var v bool
+	err := b.LoadValueContext(ctx, &v)
+	return v, err
+}
@@ -0,0 +1,67 @@
+package dbr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReturnScalarContext(t *testing.T) {
+	ctx := context.Background()
+	sess, dbmock := newSessionMock()
+
+	dbmock.ExpectQuery("SELECT count\\(\\*\\) FROM users").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(int64(3)))
+	count, err := sess.Select("count(*)").From("users").Int64(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	dbmock.ExpectQuery("SELECT COUNT\\(\\*\\) FROM t").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(int64(7)))
+	count, err = sess.Select(Count("*")).From("t").Int64(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), count)
+
+	dbmock.ExpectQuery("SELECT avg\\(price\\) FROM items").
+		WillReturnRows(sqlmock.NewRows([]string{"avg"}).AddRow(2.5))
+	avg, err := sess.Select("avg(price)").From("items").Float64(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2.5, avg)
+
+	dbmock.ExpectQuery("SELECT max\\(name\\) FROM users").
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow("zoe"))
+	name, err := sess.Select("max(name)").From("users").String(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "zoe", name)
+
+	dbmock.ExpectQuery("SELECT active FROM users").
+		WillReturnRows(sqlmock.NewRows([]string{"active"}).AddRow(int64(1)))
+	active, err := sess.Select("active").From("users").Bool(ctx)
+	assert.NoError(t, err)
+	assert.True(t, active)
+
+	dbmock.ExpectQuery("SELECT price FROM items").
+		WillReturnRows(sqlmock.NewRows([]string{"price"}).AddRow("1.25"))
+	price, err := sess.Select("price").From("items").ReturnFloat64()
+	assert.NoError(t, err)
+	assert.Equal(t, 1.25, price)
+
+	dbmock.ExpectQuery("SELECT active FROM users").
+		WillReturnRows(sqlmock.NewRows([]string{"active"}).AddRow(false))
+	active, err = sess.Select("active").From("users").ReturnBool()
+	assert.NoError(t, err)
+	assert.False(t, active)
+
+	for _, fn := range []func(stmt SelectBuilder) error{
+		func(stmt SelectBuilder) error { _, err := stmt.Int64(ctx); return err },
+		func(stmt SelectBuilder) error { _, err := stmt.Float64(ctx); return err },
+		func(stmt SelectBuilder) error { _, err := stmt.String(ctx); return err },
+		func(stmt SelectBuilder) error { _, err := stmt.Bool(ctx); return err },
+	} {
+		dbmock.ExpectQuery("SELECT a FROM t").WillReturnRows(sqlmock.NewRows([]string{"a"}))
+		assert.Equal(t, ErrNotFound, fn(sess.Select("a").From("t")))
+	}
+	assert.NoError(t, dbmock.ExpectationsWereMet())
+}
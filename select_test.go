@@ -1,8 +1,10 @@
 package dbr
 
 import (
+	"regexp"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/mailru/dbr/dialect"
 	"github.com/stretchr/testify/assert"
 )
@@ -34,6 +36,520 @@ func TestSelectStmt(t *testing.T) {
 	assert.EqualError(t, err, ErrPrewhereNotSupported.Error()) // handle PREWHERE statement error
 }
 
+func TestSelectJoinUsing(t *testing.T) {
+	for _, test := range []struct {
+		stmt  SelectStmt
+		d     Dialect
+		query string
+	}{
+		{
+			stmt:  Select("*").From("a").JoinUsing("b", "id", "tenant_id"),
+			d:     dialect.MySQL,
+			query: "SELECT * FROM a JOIN `b` USING (`id`, `tenant_id`)",
+		},
+		{
+			stmt:  Select("*").From("a").LeftJoinUsing("b", "id"),
+			d:     dialect.PostgreSQL,
+			query: `SELECT * FROM a LEFT JOIN "b" USING ("id")`,
+		},
+		{
+			stmt:  Select("*").From("a").RightJoinUsing(I("b").As("c"), "id"),
+			d:     dialect.SQLite3,
+			query: `SELECT * FROM a RIGHT JOIN "b" AS "c" USING ("id")`,
+		},
+		{
+			stmt:  Select("*").From("a").FullJoinUsing("b", "id"),
+			d:     dialect.ClickHouse,
+			query: "SELECT * FROM a FULL JOIN `b` USING (`id`)",
+		},
+	} {
+		buf := NewBuffer()
+		err := test.stmt.Build(test.d, buf)
+		assert.NoError(t, err)
+		query, err := InterpolateForDialect(buf.String(), buf.Value(), test.d)
+		assert.NoError(t, err)
+		assert.Equal(t, test.query, query)
+	}
+
+	err := Select("*").From("a").JoinUsing("b").Build(dialect.MySQL, NewBuffer())
+	assert.Equal(t, ErrColumnNotSpecified, err)
+	err = Select("*").From("a").JoinUsing("b", "id", "").Build(dialect.MySQL, NewBuffer())
+	assert.Equal(t, ErrColumnNotSpecified, err)
+}
+
+func TestSelectJoinLateral(t *testing.T) {
+	last := Select("o.total").From(As("orders", "o")).
+		Where("o.user_id = u.id AND o.status = ?", "paid").
+		OrderDesc("o.created_at").
+		Limit(1)
+
+	buf := NewBuffer()
+	err := Select("u.name", "l.total").From(As("users", "u")).
+		LeftJoinLateral(last, "l", "true").
+		Where(Eq("u.active", true)).
+		Build(dialect.PostgreSQL, buf)
+	assert.NoError(t, err)
+	query, err := InterpolateForDialect(buf.String(), buf.Value(), dialect.PostgreSQL)
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT u.name, l.total FROM "users" AS "u" LEFT JOIN LATERAL `+
+		`(SELECT o.total FROM "orders" AS "o" WHERE (o.user_id = u.id AND o.status = 'paid') ORDER BY o.created_at DESC LIMIT 1) AS "l" ON true `+
+		`WHERE ("u"."active" = TRUE)`, query)
+
+	buf = NewBuffer()
+	err = Select("u.name", "l.total").From(As("users", "u")).
+		CrossJoinLateral(last, "l").
+		Build(dialect.PostgreSQL, buf)
+	assert.NoError(t, err)
+	query, err = InterpolateForDialect(buf.String(), buf.Value(), dialect.PostgreSQL)
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT u.name, l.total FROM "users" AS "u" CROSS JOIN LATERAL `+
+		`(SELECT o.total FROM "orders" AS "o" WHERE (o.user_id = u.id AND o.status = 'paid') ORDER BY o.created_at DESC LIMIT 1) AS "l"`, query)
+
+	for _, d := range []Dialect{dialect.MySQL, dialect.SQLite3, dialect.ClickHouse} {
+		err := Select("*").From("users").CrossJoinLateral(last, "l").Build(d, NewBuffer())
+		assert.Equal(t, ErrLateralJoinNotSupported, err)
+	}
+}
+
+func TestSelectDistinctOn(t *testing.T) {
+	for _, test := range []struct {
+		stmt  SelectStmt
+		d     Dialect
+		query string
+	}{
+		{
+			stmt:  Select("*").From("events").DistinctOn("user_id", "kind").OrderAsc("kind").OrderAsc("user_id").OrderDesc("created_at"),
+			d:     dialect.PostgreSQL,
+			query: `SELECT DISTINCT ON (user_id, kind) * FROM events ORDER BY kind ASC, user_id ASC, created_at DESC`,
+		},
+		{
+			stmt:  Select("*").From("events").DistinctOn("user_id").Distinct(),
+			d:     dialect.ClickHouse,
+			query: "SELECT DISTINCT ON (user_id) * FROM events",
+		},
+	} {
+		buf := NewBuffer()
+		err := test.stmt.Build(test.d, buf)
+		assert.NoError(t, err)
+		query, err := InterpolateForDialect(buf.String(), buf.Value(), test.d)
+		assert.NoError(t, err)
+		assert.Equal(t, test.query, query)
+	}
+
+	// orderings added by OrderBy are checked without direction
+	sb := &selectBuilder{selectStmt: createSelectStmt([]interface{}{"*"})}
+	err := sb.From("events").DistinctOn("user_id").OrderBy("user_id DESC NULLS LAST").OrderBy("id").Build(dialect.PostgreSQL, NewBuffer())
+	assert.NoError(t, err)
+
+	err = Select("*").From("events").DistinctOn("user_id").OrderDesc("created_at").OrderAsc("user_id").Build(dialect.PostgreSQL, NewBuffer())
+	assert.EqualError(t, err, "dbr: ORDER BY created_at does not match DISTINCT ON (user_id), leading orderings must be by its columns")
+
+	for _, d := range []Dialect{dialect.MySQL, dialect.SQLite3} {
+		err := Select("*").From("events").DistinctOn("user_id").Build(d, NewBuffer())
+		assert.Equal(t, ErrDistinctOnNotSupported, err)
+	}
+}
+
+func TestSelectQualifyColumns(t *testing.T) {
+	for _, test := range []struct {
+		d     Dialect
+		query string
+	}{
+		{
+			d: dialect.MySQL,
+			query: "SELECT u.id, u.name, o.id, count(*) FROM `users` AS `u` JOIN `orders` AS `o` ON `u`.`id` = `o`.`user_id` " +
+				"WHERE (`u`.`id` IN (1,2)) AND (`o`.`status` = 'paid') AND (id > 0)",
+		},
+		{
+			d: dialect.PostgreSQL,
+			query: `SELECT u.id, u.name, o.id, count(*) FROM "users" AS "u" JOIN "orders" AS "o" ON "u"."id" = "o"."user_id" ` +
+				`WHERE ("u"."id" IN (1,2)) AND ("o"."status" = 'paid') AND (id > 0)`,
+		},
+	} {
+		stmt := Select("id", "name", "o.id", "count(*)").From(As("users", "u")).
+			Join(As("orders", "o"), Eq("u.id", I("o.user_id"))).
+			Where(Eq("id", []int{1, 2})).
+			Where(Eq("o.status", "paid")).
+			Where("id > ?", 0).
+			QualifyColumns("u")
+		buf := NewBuffer()
+		err := stmt.Build(test.d, buf)
+		assert.NoError(t, err)
+		query, err := InterpolateForDialect(buf.String(), buf.Value(), test.d)
+		assert.NoError(t, err)
+		assert.Equal(t, test.query, query)
+	}
+}
+
+func TestSelectOrderByExpr(t *testing.T) {
+	stmt := Select("*").From("items").
+		Where("kind = ?", "book").
+		GroupBy("id").
+		Having("count(*) > ?", 1).
+		OrderBy("CASE WHEN id = ? THEN 0 ELSE 1 END", 7).
+		OrderBy(Expr("title = ?", "Go")).
+		OrderBy("id").
+		Limit(10)
+	buf := NewBuffer()
+	err := stmt.Build(dialect.PostgreSQL, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM items WHERE (kind = ?) GROUP BY id HAVING (count(*) > ?) "+
+		"ORDER BY CASE WHEN id = ? THEN 0 ELSE 1 END, title = ?, id LIMIT 10", buf.String())
+	assert.Equal(t, []interface{}{"book", 1, 7, "Go"}, buf.Value())
+
+	query, err := InterpolateForDialect(buf.String(), buf.Value(), dialect.PostgreSQL)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM items WHERE (kind = 'book') GROUP BY id HAVING (count(*) > 1) "+
+		"ORDER BY CASE WHEN id = 7 THEN 0 ELSE 1 END, title = 'Go', id LIMIT 10", query)
+}
+
+func TestSelectClear(t *testing.T) {
+	stmt := Select("*").From("items").
+		Where("kind = ?", "book").
+		Where(Eq("author_id", 1)).
+		OrderBy("CASE WHEN id = ? THEN 0 ELSE 1 END", 7).
+		OrderDesc("id").
+		Limit(10).
+		Offset(20)
+	stmt.ClearWhere().ClearOrderBy().ClearLimit()
+	buf := NewBuffer()
+	err := stmt.Build(dialect.MySQL, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM items", buf.String())
+	assert.Empty(t, buf.Value())
+
+	stmt.Where("kind = ?", "film").OrderAsc("id").Limit(5)
+	buf = NewBuffer()
+	err = stmt.Build(dialect.MySQL, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM items WHERE (kind = ?) ORDER BY id ASC LIMIT 5", buf.String())
+	assert.Equal(t, []interface{}{"film"}, buf.Value())
+}
+
+func TestSelectColumns(t *testing.T) {
+	withEmail := func(stmt SelectStmt, email bool) SelectStmt {
+		if email {
+			stmt.Columns("email")
+		}
+		return stmt
+	}
+
+	buf := NewBuffer()
+	stmt := withEmail(Select().Distinct().Columns("name"), true).Columns("city")
+	err := withEmail(stmt, false).From("users").Build(dialect.MySQL, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT DISTINCT name, email, city FROM users", buf.String())
+
+	buf = NewBuffer()
+	err = Select("city").Columns(Count("*").As("total"), Max("age")).From("users").GroupBy("city").Build(dialect.PostgreSQL, buf)
+	assert.NoError(t, err)
+	query, err := InterpolateForDialect(buf.String(), buf.Value(), dialect.PostgreSQL)
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT city, COUNT(*) AS "total", MAX(age) FROM users GROUP BY city`, query)
+
+	sess, m := newSessionMock()
+	buf = NewBuffer()
+	columns := []interface{}{"name", "email"}
+	err = sess.Select("id").Columns(columns...).Columns("count(*)").From("users").Build(dialect.MySQL, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, name, email, count(*) FROM users", buf.String())
+
+	// aggregates compose with the select of session
+	m.ExpectQuery(regexp.QuoteMeta("SELECT city, COUNT(*) AS `total` FROM users GROUP BY city")).
+		WillReturnRows(sqlmock.NewRows([]string{"city", "total"}).AddRow("Paris", 2))
+	var cities []struct {
+		City  string
+		Total int64
+	}
+	_, err = sess.Select("city").Columns(Count("*").As("total")).From("users").GroupBy("city").Load(&cities)
+	assert.NoError(t, err)
+	assert.Len(t, cities, 1)
+	assert.Equal(t, int64(2), cities[0].Total)
+	assert.NoError(t, m.ExpectationsWereMet())
+}
+
+func TestSelectSubquery(t *testing.T) {
+	orders := Select("count(*)").From(As("orders", "o")).Where("o.user_id = u.id").Where(Eq("o.state", "paid"))
+	buf := NewBuffer()
+	err := Select("u.id", Subquery(orders).As("order_count")).
+		From(As("users", "u")).
+		Where(Eq("u.active", true)).
+		Where("? > ?", Subquery(orders), 2).
+		Build(dialect.PostgreSQL, buf)
+	assert.NoError(t, err)
+	query, err := InterpolateForDialect(buf.String(), buf.Value(), dialect.PostgreSQL)
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT u.id, (SELECT count(*) FROM "orders" AS "o" WHERE (o.user_id = u.id) AND ("o"."state" = 'paid')) AS "order_count" `+
+		`FROM "users" AS "u" WHERE ("u"."active" = TRUE) AND ((SELECT count(*) FROM "orders" AS "o" WHERE (o.user_id = u.id) AND ("o"."state" = 'paid')) > 2)`, query)
+
+	// builders of a session are parenthesized too
+	sess, _ := newSessionMock()
+	buf = NewBuffer()
+	err = Select("id", Subquery(sess.Select("max(total)").From("orders").Where("user_id = users.id"))).From("users").Build(dialect.MySQL, buf)
+	assert.NoError(t, err)
+	query, err = InterpolateForDialect(buf.String(), buf.Value(), dialect.MySQL)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, (SELECT max(total) FROM orders WHERE (user_id = users.id)) FROM users", query)
+}
+
+func TestSelectOnly(t *testing.T) {
+	buf := NewBuffer()
+	err := Select("*").From(As("events", "e")).Only().Where(Eq("e.id", 1)).Build(dialect.PostgreSQL, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM ONLY ? WHERE ("e"."id" = ?)`, buf.String())
+	query, err := InterpolateForDialect(buf.String(), buf.Value(), dialect.PostgreSQL)
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM ONLY "events" AS "e" WHERE ("e"."id" = 1)`, query)
+
+	for _, d := range []Dialect{dialect.MySQL, dialect.SQLite3, dialect.ClickHouse} {
+		err := Select("*").From("events").Only().Build(d, NewBuffer())
+		assert.Equal(t, ErrOnlyNotSupported, err)
+	}
+}
+
+func TestSelectTableAlias(t *testing.T) {
+	for _, test := range []struct {
+		d     Dialect
+		query string
+	}{
+		{
+			d:     dialect.MySQL,
+			query: "SELECT * FROM `user` AS `u` LEFT JOIN `order` AS `o` ON `u`.`id` = `o`.`user_id` JOIN (SELECT id FROM t) AS `s` USING (`id`)",
+		},
+		{
+			d:     dialect.PostgreSQL,
+			query: `SELECT * FROM "user" AS "u" LEFT JOIN "order" AS "o" ON "u"."id" = "o"."user_id" JOIN (SELECT id FROM t) AS "s" USING ("id")`,
+		},
+	} {
+		stmt := Select("*").From(As("user", "u")).
+			LeftJoin(As("order", "o"), Eq("u.id", I("o.user_id"))).
+			JoinUsing(As(Select("id").From("t"), "s"), "id")
+		buf := NewBuffer()
+		err := stmt.Build(test.d, buf)
+		assert.NoError(t, err)
+		query, err := InterpolateForDialect(buf.String(), buf.Value(), test.d)
+		assert.NoError(t, err)
+		assert.Equal(t, test.query, query)
+	}
+}
+
+func TestSelectIndexHint(t *testing.T) {
+	stmt := Select("*").From(As("user", "u")).
+		UseIndex("idx_name", "idx_email").
+		ForceIndex("PRIMARY").
+		LeftJoin(As("order", "o"), "u.id = o.user_id").
+		Where(Eq("u.name", "Barack"))
+	buf := NewBuffer()
+	err := stmt.Build(dialect.MySQL, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM ? USE INDEX (`idx_name`,`idx_email`) FORCE INDEX (`PRIMARY`) LEFT JOIN ? ON u.id = o.user_id WHERE (`u`.`name` = ?)", buf.String())
+
+	for _, d := range []Dialect{dialect.PostgreSQL, dialect.SQLite3, dialect.ClickHouse} {
+		err := stmt.Build(d, NewBuffer())
+		assert.Equal(t, ErrIndexHintNotSupported, err)
+	}
+}
+
+func TestSelectKeyLock(t *testing.T) {
+	buf := NewBuffer()
+	err := Select("*").From("orders").Where(Eq("id", 1)).ForNoKeyUpdate().SkipLocked().Build(dialect.PostgreSQL, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM orders WHERE ("id" = ?) FOR NO KEY UPDATE SKIP LOCKED`, buf.String())
+
+	buf = NewBuffer()
+	err = Select("*").From("users").ForKeyShare().Build(dialect.PostgreSQL, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users FOR KEY SHARE", buf.String())
+
+	// the last lock mode wins
+	buf = NewBuffer()
+	err = Select("*").From("users").ForKeyShare().ForUpdate().Build(dialect.MySQL, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users FOR UPDATE", buf.String())
+
+	buf = NewBuffer()
+	err = Select("*").From("jobs").ForUpdate().NoWait().Build(dialect.MySQL, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM jobs FOR UPDATE NOWAIT", buf.String())
+
+	// skip locked wins over nowait
+	buf = NewBuffer()
+	err = Select("*").From("jobs").ForUpdate().NoWait().SkipLocked().Build(dialect.PostgreSQL, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM jobs FOR UPDATE SKIP LOCKED", buf.String())
+
+	for _, d := range []Dialect{dialect.MySQL, dialect.SQLite3, dialect.ClickHouse} {
+		err := Select("*").From("users").ForNoKeyUpdate().Build(d, NewBuffer())
+		assert.Equal(t, ErrKeyLockNotSupported, err)
+		err = Select("*").From("users").ForUpdate().ForKeyShare().Build(d, NewBuffer())
+		assert.Equal(t, ErrKeyLockNotSupported, err)
+	}
+}
+
+func TestSelectWithTotals(t *testing.T) {
+	buf := NewBuffer()
+	err := Select("domain", "count()").From("hits").GroupBy("domain").WithTotals().Having("count() > ?", 10).Build(dialect.ClickHouse, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT domain, count() FROM hits GROUP BY domain WITH TOTALS HAVING (count() > ?)", buf.String())
+
+	for _, d := range []Dialect{dialect.MySQL, dialect.PostgreSQL, dialect.SQLite3} {
+		err := Select("domain", "count()").From("hits").GroupBy("domain").WithTotals().Build(d, NewBuffer())
+		assert.Equal(t, ErrWithTotalsNotSupported, err)
+	}
+}
+
+func TestSelectTableSample(t *testing.T) {
+	buf := NewBuffer()
+	err := Select("*").From(As("events", "e")).TableSample("bernoulli", 1.5).Where(Eq("e.kind", "click")).Build(dialect.PostgreSQL, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT * FROM ? TABLESAMPLE BERNOULLI (1.5) WHERE ("e"."kind" = ?)`, buf.String())
+
+	buf = NewBuffer()
+	err = Select("count()").From("hits").TableSample("SYSTEM", 10).Join("users", "hits.user_id = users.id").Build(dialect.ClickHouse, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT count() FROM hits SAMPLE 0.1 JOIN `users` ON hits.user_id = users.id", buf.String())
+
+	for _, d := range []Dialect{dialect.MySQL, dialect.SQLite3} {
+		err := Select("*").From("events").TableSample("SYSTEM", 10).Build(d, NewBuffer())
+		assert.Equal(t, ErrTableSampleNotSupported, err)
+	}
+	err = Select("*").From("events").TableSample("RANDOM", 10).Build(dialect.PostgreSQL, NewBuffer())
+	assert.Equal(t, ErrTableSampleMethod, err)
+	for _, percent := range []float64{0, -1, 100.5} {
+		err = Select("*").From("events").TableSample("SYSTEM", percent).Build(dialect.PostgreSQL, NewBuffer())
+		assert.Equal(t, ErrTableSamplePercent, err)
+	}
+}
+
+func TestSelectPlanHint(t *testing.T) {
+	buf := NewBuffer()
+	err := Select("id").From("users").
+		PlanHint("IndexScan(users users_email_idx)").
+		PlanHint("Rows(users #10) */ DROP TABLE users; /*").
+		AddComment("search").
+		Where(Eq("email", "a@example.com")).
+		Build(dialect.PostgreSQL, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, `/*+ IndexScan(users users_email_idx) Rows(users #10)  DROP TABLE users;  */ /* search */SELECT id FROM users WHERE ("email" = ?)`, buf.String())
+
+	// the hint precedes WITH of a raw query
+	buf = NewBuffer()
+	stmt := SelectBySql("WITH u AS (SELECT * FROM users WHERE email = ?) SELECT id FROM u", "a@example.com").
+		PlanHint("SeqScan(users)")
+	err = stmt.Build(dialect.PostgreSQL, buf)
+	assert.NoError(t, err)
+	query, err := InterpolateForDialect(buf.String(), buf.Value(), dialect.PostgreSQL)
+	assert.NoError(t, err)
+	assert.Equal(t, "/*+ SeqScan(users) */ WITH u AS (SELECT * FROM users WHERE email = 'a@example.com') SELECT id FROM u", query)
+
+	for _, d := range []Dialect{dialect.MySQL, dialect.SQLite3, dialect.ClickHouse} {
+		err := Select("id").From("users").PlanHint("SeqScan(users)").Build(d, NewBuffer())
+		assert.Equal(t, ErrPlanHintNotSupported, err)
+	}
+}
+
+func TestSelectOrderByAlias(t *testing.T) {
+	for _, test := range []struct {
+		d     Dialect
+		query string
+	}{
+		{
+			d:     dialect.MySQL,
+			query: "SELECT user_id, COUNT(*) AS `Total` FROM orders GROUP BY user_id ORDER BY `Total` DESC, user_id ASC",
+		},
+		{
+			d:     dialect.PostgreSQL,
+			query: `SELECT user_id, COUNT(*) AS "Total" FROM orders GROUP BY user_id ORDER BY "Total" DESC, user_id ASC`,
+		},
+		{
+			d:     dialect.SQLite3,
+			query: `SELECT user_id, COUNT(*) AS "Total" FROM orders GROUP BY user_id ORDER BY "Total" DESC, user_id ASC`,
+		},
+		{
+			d:     dialect.ClickHouse,
+			query: "SELECT user_id, COUNT(*) AS `Total` FROM orders GROUP BY user_id ORDER BY `Total` DESC, user_id ASC",
+		},
+	} {
+		buf := NewBuffer()
+		err := Select("user_id", Count("*").As("Total")).From("orders").GroupBy("user_id").
+			OrderByAlias("Total", false).OrderAsc("user_id").
+			Build(test.d, buf)
+		assert.NoError(t, err)
+		query, err := InterpolateForDialect(buf.String(), buf.Value(), test.d)
+		assert.NoError(t, err)
+		assert.Equal(t, test.query, query)
+	}
+}
+
+func TestSelectNullsOrdering(t *testing.T) {
+	for _, test := range []struct {
+		d     Dialect
+		nulls NullsOrdering
+		query string
+	}{
+		{
+			d:     dialect.PostgreSQL,
+			nulls: NullsLast,
+			query: `SELECT * FROM users ORDER BY name DESC NULLS LAST, "Rank" ASC NULLS LAST, id`,
+		},
+		{
+			d:     dialect.SQLite3,
+			nulls: NullsFirst,
+			query: `SELECT * FROM users ORDER BY name IS NULL DESC, name DESC, "Rank" IS NULL DESC, "Rank" ASC, id`,
+		},
+		{
+			d:     dialect.ClickHouse,
+			nulls: NullsFirst,
+			query: "SELECT * FROM users ORDER BY name DESC NULLS FIRST, `Rank` ASC NULLS FIRST, id",
+		},
+		{
+			d:     dialect.MySQL,
+			nulls: NullsLast,
+			query: "SELECT * FROM users ORDER BY name IS NULL ASC, name DESC, `Rank` IS NULL ASC, `Rank` ASC, id",
+		},
+		{
+			d:     dialect.MySQL,
+			nulls: NullsFirst,
+			query: "SELECT * FROM users ORDER BY name IS NULL DESC, name DESC, `Rank` IS NULL DESC, `Rank` ASC, id",
+		},
+		{
+			d:     dialect.MySQL,
+			nulls: NullsDefault,
+			query: "SELECT * FROM users ORDER BY name DESC, `Rank` ASC, id",
+		},
+	} {
+		stmt := Select("*").From("users").OrderDesc("name").OrderByAlias("Rank", true).(*selectStmt)
+		stmt.Order = append(stmt.Order, Expr("id"))
+		stmt.NullsOrdering = test.nulls
+		buf := NewBuffer()
+		err := stmt.Build(test.d, buf)
+		assert.NoError(t, err)
+		assert.Equal(t, test.query, buf.String())
+	}
+}
+
+func TestSelectStruct(t *testing.T) {
+	type user struct {
+		ID   int64
+		Name string `db:"full_name"`
+	}
+	buf := NewBuffer()
+	err := SelectStruct(&user{}, "u").From(As("users", "u")).
+		Join(As("orders", "o"), "u.id = o.user_id").
+		Build(dialect.PostgreSQL, buf)
+	assert.NoError(t, err)
+	query, err := InterpolateForDialect(buf.String(), buf.Value(), dialect.PostgreSQL)
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT "u"."id", "u"."full_name" FROM "users" AS "u" JOIN "orders" AS "o" ON u.id = o.user_id`, query)
+
+	buf = NewBuffer()
+	err = SelectStruct(user{}, "").From("users").Build(dialect.MySQL, buf)
+	assert.NoError(t, err)
+	query, err = InterpolateForDialect(buf.String(), buf.Value(), dialect.MySQL)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT `id`, `full_name` FROM users", query)
+}
+
 func BenchmarkSelectSQL(b *testing.B) {
 	buf := NewBuffer()
 	for i := 0; i < b.N; i++ {
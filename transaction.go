@@ -3,14 +3,37 @@ package dbr
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"sync"
+	"time"
 )
 
 // Tx is a transaction for the given Session
 type Tx struct {
 	EventReceiver
 	Dialect Dialect
+	Metrics Metrics
+	// UsePlaceholders is copied from the session
+	UsePlaceholders bool
+	// KeywordCase is copied from the session
+	KeywordCase KeywordCase
+	// NullsOrdering is copied from the session
+	NullsOrdering NullsOrdering
+	// StandardLimitSyntax is copied from the session
+	StandardLimitSyntax bool
 	*sql.Tx
 	ctx context.Context
+	tag string
+
+	savepoints int
+	// resetTimeout resets the statement timeout before the end of the transaction
+	resetTimeout string
+	// timeoutSettings are appended to SELECT queries of the transaction, e.g. in ClickHouse
+	timeoutSettings string
+
+	auditMu  sync.Mutex
+	audit    bool
+	auditLog []string
 }
 
 // Begin creates a transaction for the given session
@@ -20,22 +43,155 @@ func (sess *Session) Begin() (*Tx, error) {
 
 // BeginWithOpts creates a transaction for the given section with ability to set TxOpts
 func (sess *Session) BeginWithOpts(opts *sql.TxOptions) (*Tx, error) {
-	tx, err := sess.beginTx(opts)
+	return sess.begin(sess.ctx, opts)
+}
+
+// begin starts a transaction on the primary connection of the session, never on its Replica.
+// A read-only session, e.g. of a replica connection, returns ErrReadOnlySession
+func (sess *Session) begin(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	if sess.readOnly {
+		return nil, sess.EventErr("dbr.begin.read_only", ErrReadOnlySession)
+	}
+	tx, err := sess.beginTx(ctx, opts)
 	if err != nil {
 		return nil, sess.EventErr("dbr.begin.error", err)
 	}
 	sess.Event("dbr.begin")
 
 	return &Tx{
-		EventReceiver: sess.EventReceiver,
-		Dialect:       sess.Dialect,
-		Tx:            tx,
-		ctx:           sess.ctx,
+		EventReceiver:       sess.EventReceiver,
+		Dialect:             sess.Dialect,
+		Metrics:             sess.Metrics,
+		UsePlaceholders:     sess.UsePlaceholders,
+		KeywordCase:         sess.KeywordCase,
+		NullsOrdering:       sess.NullsOrdering,
+		StandardLimitSyntax: sess.StandardLimitSyntax,
+		Tx:                  tx,
+		ctx:                 ctx,
+		tag:                 sess.tag,
 	}, nil
 }
 
-// Commit finishes the transaction
+// Transact runs fn in a transaction, whose queries run with ctx. The transaction is committed
+// if fn returns nil and rolled back if it returns an error or panics, the panic is resumed after
+// the rollback. An error of the rollback is sent to the EventReceiver and the error of fn is returned
+func (sess *Session) Transact(ctx context.Context, fn func(*Tx) error) error {
+	tx, err := sess.begin(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.rollbackAfter(fmt.Errorf("dbr: panic: %v", p))
+			panic(p)
+		}
+	}()
+	if err := fn(tx); err != nil {
+		tx.rollbackAfter(err)
+		return err
+	}
+	return tx.Commit()
+}
+
+// Transact runs fn in a savepoint of the transaction, so nested calls behave like nested transactions.
+// The savepoint is released if fn returns nil and the transaction is rolled back to it if fn returns
+// an error or panics, the rest of the transaction is kept. It returns ErrSavepointNotSupported
+// if the dialect does not support savepoints
+func (tx *Tx) Transact(fn func(*Tx) error) error {
+	if !tx.Dialect.SupportsSavepoint() {
+		return ErrSavepointNotSupported
+	}
+	tx.savepoints++
+	name := tx.Dialect.QuoteIdent(fmt.Sprintf("dbr_savepoint_%d", tx.savepoints))
+	if _, err := tx.Tx.ExecContext(tx.ctx, "SAVEPOINT "+name); err != nil {
+		return tx.EventErr("dbr.savepoint.error", err)
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.rollbackToAfter(name, fmt.Errorf("dbr: panic: %v", p))
+			panic(p)
+		}
+	}()
+	if err := fn(tx); err != nil {
+		tx.rollbackToAfter(name, err)
+		return err
+	}
+	if _, err := tx.Tx.ExecContext(tx.ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		return tx.EventErr("dbr.release_savepoint.error", err)
+	}
+	return nil
+}
+
+// rollbackToAfter rolls back the transaction to savepoint name after cause,
+// an error of the rollback is sent to the EventReceiver
+func (tx *Tx) rollbackToAfter(name string, cause error) {
+	_, err := tx.Tx.ExecContext(tx.ctx, "ROLLBACK TO SAVEPOINT "+name)
+	if err != nil {
+		tx.EventErrKv("dbr.rollback_to_savepoint", err, kvs{"cause": cause.Error()})
+		return
+	}
+	tx.Event("dbr.rollback_to_savepoint")
+}
+
+// rollbackAfter rolls back the transaction after cause, an error of the rollback is sent to the EventReceiver
+func (tx *Tx) rollbackAfter(cause error) {
+	tx.resetStatementTimeout()
+	err := tx.Tx.Rollback()
+	if err != nil {
+		tx.EventErrKv("dbr.rollback", err, kvs{"cause": cause.Error()})
+		return
+	}
+	tx.Event("dbr.rollback")
+}
+
+// SetStatementTimeout makes the server cancel statements of the transaction running longer than timeout,
+// beyond cancel of the context, 0 disables the timeout. It is `SET LOCAL statement_timeout` in PostgreSQL,
+// `SET SESSION max_execution_time` in MySQL, which applies to SELECT only, the variable of MySQL is reset
+// on commit and rollback. ClickHouse appends `SETTINGS max_execution_time=N` in seconds to SELECT queries
+// of the transaction, so queries of SelectBySql must not end with SETTINGS or FORMAT.
+// SQLite returns ErrStmtTimeoutNotSupported
+func (tx *Tx) SetStatementTimeout(timeout time.Duration) error {
+	query := tx.Dialect.StatementTimeout(timeout)
+	if query == "" {
+		settings := tx.Dialect.StatementTimeoutSettings(timeout)
+		if settings == "" {
+			return ErrStmtTimeoutNotSupported
+		}
+		tx.timeoutSettings = settings
+		return nil
+	}
+	if _, err := tx.Tx.ExecContext(tx.ctx, query); err != nil {
+		return tx.EventErr("dbr.set_statement_timeout.error", err)
+	}
+	tx.resetTimeout = tx.Dialect.ResetStatementTimeout()
+	return nil
+}
+
+// resetStatementTimeout resets the variable set by SetStatementTimeout,
+// so it is not kept by the connection returned to the pool
+func (tx *Tx) resetStatementTimeout() error {
+	if tx.resetTimeout == "" {
+		return nil
+	}
+	query := tx.resetTimeout
+	tx.resetTimeout = ""
+	if _, err := tx.Tx.ExecContext(tx.ctx, query); err != nil {
+		return tx.EventErr("dbr.reset_statement_timeout.error", err)
+	}
+	return nil
+}
+
+// Commit finishes the transaction, it is rolled back if the statement timeout can not be reset
 func (tx *Tx) Commit() error {
+	if err := tx.resetStatementTimeout(); err != nil {
+		// the transaction must not stay open, and the connection must not keep the timeout
+		if rollbackErr := tx.Tx.Rollback(); rollbackErr != nil {
+			tx.EventErr("dbr.rollback", rollbackErr)
+		} else {
+			tx.Event("dbr.rollback")
+		}
+		return err
+	}
 	err := tx.Tx.Commit()
 	if err != nil {
 		return tx.EventErr("dbr.commit.error", err)
@@ -46,6 +202,8 @@ func (tx *Tx) Commit() error {
 
 // Rollback cancels the transaction
 func (tx *Tx) Rollback() error {
+	// an error of reset is sent to the EventReceiver, the rollback is more important
+	tx.resetStatementTimeout()
 	err := tx.Tx.Rollback()
 	if err != nil {
 		return tx.EventErr("dbr.rollback", err)
@@ -58,6 +216,7 @@ func (tx *Tx) Rollback() error {
 // Useful to defer tx.RollbackUnlessCommitted() -- so you don't have to handle N failure cases
 // Keep in mind the only way to detect an error on the rollback is via the event log.
 func (tx *Tx) RollbackUnlessCommitted() {
+	tx.resetStatementTimeout()
 	err := tx.Tx.Rollback()
 	if err == sql.ErrTxDone {
 		// ok
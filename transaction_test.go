@@ -1,10 +1,16 @@
 package dbr
 
 import (
+	"context"
+	"errors"
+	"regexp"
+	"time"
+
 	"github.com/mailru/dbr/dialect"
 
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -61,3 +67,257 @@ func TestTransactionRollback(t *testing.T) {
 		assert.Error(t, err)
 	}
 }
+
+func TestTransact(t *testing.T) {
+	sess, m, recv := newRecordingSessionMock()
+	m.ExpectBegin()
+	m.ExpectExec("DELETE FROM `users`").WillReturnResult(sqlmock.NewResult(0, 1))
+	m.ExpectCommit()
+	err := sess.Transact(context.Background(), func(tx *Tx) error {
+		_, err := tx.DeleteFrom("users").Where(Eq("id", 1)).Exec()
+		return err
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, m.ExpectationsWereMet())
+
+	// the error of fn is returned even if the rollback fails, which is sent to the receiver
+	stop := errors.New("stop")
+	m.ExpectBegin()
+	m.ExpectRollback().WillReturnError(errors.New("connection lost"))
+	err = sess.Transact(context.Background(), func(tx *Tx) error {
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.NoError(t, m.ExpectationsWereMet())
+	last := recv.events[len(recv.events)-1]
+	assert.Equal(t, "dbr.rollback", last.name)
+	assert.EqualError(t, last.err, "connection lost")
+	assert.Equal(t, "stop", last.kvs["cause"])
+
+	m.ExpectBegin()
+	m.ExpectRollback()
+	assert.PanicsWithValue(t, "boom", func() {
+		sess.Transact(context.Background(), func(tx *Tx) error {
+			panic("boom")
+		})
+	})
+	assert.NoError(t, m.ExpectationsWereMet())
+
+	m.ExpectBegin().WillReturnError(errors.New("too many connections"))
+	err = sess.Transact(context.Background(), func(tx *Tx) error {
+		t.Fatal("fn must not be called")
+		return nil
+	})
+	assert.EqualError(t, err, "too many connections")
+	assert.NoError(t, m.ExpectationsWereMet())
+}
+
+func TestTxTransact(t *testing.T) {
+	sess, m, _ := newRecordingSessionMock()
+	m.ExpectBegin()
+	m.ExpectExec("INSERT INTO `orders`").WillReturnResult(sqlmock.NewResult(1, 1))
+	m.ExpectExec("SAVEPOINT `dbr_savepoint_1`").WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectExec("INSERT INTO `payments`").WillReturnResult(sqlmock.NewResult(1, 1))
+	m.ExpectExec("SAVEPOINT `dbr_savepoint_2`").WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectExec("RELEASE SAVEPOINT `dbr_savepoint_2`").WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectExec("ROLLBACK TO SAVEPOINT `dbr_savepoint_1`").WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectCommit()
+
+	declined := errors.New("declined")
+	err := sess.Transact(context.Background(), func(tx *Tx) error {
+		_, err := tx.InsertInto("orders").Columns("id").Values(1).Exec()
+		assert.NoError(t, err)
+		err = tx.Transact(func(tx *Tx) error {
+			_, err := tx.InsertInto("payments").Columns("order_id").Values(1).Exec()
+			assert.NoError(t, err)
+			assert.NoError(t, tx.Transact(func(tx *Tx) error {
+				return nil
+			}))
+			return declined
+		})
+		assert.Equal(t, declined, err)
+		// the order is kept, only the payment is rolled back
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, m.ExpectationsWereMet())
+
+	m.ExpectBegin()
+	m.ExpectExec("SAVEPOINT `dbr_savepoint_1`").WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectExec("ROLLBACK TO SAVEPOINT `dbr_savepoint_1`").WillReturnResult(sqlmock.NewResult(0, 0))
+	tx, err := sess.Begin()
+	assert.NoError(t, err)
+	assert.PanicsWithValue(t, "boom", func() {
+		tx.Transact(func(tx *Tx) error {
+			panic("boom")
+		})
+	})
+	assert.NoError(t, m.ExpectationsWereMet())
+
+	tx.Dialect = dialect.ClickHouse
+	err = tx.Transact(func(tx *Tx) error {
+		return nil
+	})
+	assert.Equal(t, ErrSavepointNotSupported, err)
+}
+
+func TestTxTransactNestedRollback(t *testing.T) {
+	for _, sess := range testSession {
+		if sess.Dialect == dialect.ClickHouse {
+			// clickhouse does not support transactions
+			continue
+		}
+		outer, inner := nextID(), nextID()
+		err := sess.Transact(context.Background(), func(tx *Tx) error {
+			_, err := tx.InsertInto("dbr_people").Columns("id", "name", "email").Values(outer, "Outer", "outer@example.com").Exec()
+			assert.NoError(t, err)
+			err = tx.Transact(func(tx *Tx) error {
+				_, err := tx.InsertInto("dbr_people").Columns("id", "name", "email").Values(inner, "Inner", "inner@example.com").Exec()
+				assert.NoError(t, err)
+				return ErrNotFound
+			})
+			assert.Equal(t, ErrNotFound, err)
+			return nil
+		})
+		assert.NoError(t, err)
+
+		var names []string
+		_, err = sess.Select("name").From("dbr_people").Where(Eq("id", []int64{outer, inner})).Load(&names)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Outer"}, names)
+	}
+}
+
+func TestTxSetStatementTimeout(t *testing.T) {
+	for _, test := range []struct {
+		d     Dialect
+		set   string
+		reset string
+	}{
+		{d: dialect.PostgreSQL, set: "SET LOCAL statement_timeout = 1500"},
+		{d: dialect.MySQL, set: "SET SESSION max_execution_time = 1500", reset: "SET SESSION max_execution_time = DEFAULT"},
+	} {
+		db, m, err := sqlmock.New()
+		assert.NoError(t, err)
+		sess := (&Connection{DB: db, Dialect: test.d, EventReceiver: nullReceiver}).NewSession(nil)
+
+		// the variable is reset before commit and rollback, so the pooled connection does not keep it
+		for _, commit := range []bool{true, false} {
+			m.ExpectBegin()
+			m.ExpectExec(regexp.QuoteMeta(test.set) + "$").WillReturnResult(sqlmock.NewResult(0, 0))
+			if test.reset != "" {
+				m.ExpectExec(regexp.QuoteMeta(test.reset) + "$").WillReturnResult(sqlmock.NewResult(0, 0))
+			}
+			if commit {
+				m.ExpectCommit()
+			} else {
+				m.ExpectRollback()
+			}
+			tx, err := sess.Begin()
+			assert.NoError(t, err)
+			assert.NoError(t, tx.SetStatementTimeout(1500*time.Millisecond))
+			if commit {
+				assert.NoError(t, tx.Commit())
+			} else {
+				tx.RollbackUnlessCommitted()
+			}
+			assert.NoError(t, m.ExpectationsWereMet(), test.d)
+		}
+	}
+
+	// a positive timeout is at least 1ms, 0 disables the timeout
+	assert.Equal(t, "SET LOCAL statement_timeout = 1", dialect.PostgreSQL.StatementTimeout(time.Microsecond))
+	assert.Equal(t, "SET SESSION max_execution_time = 0", dialect.MySQL.StatementTimeout(0))
+
+	// the transaction is rolled back if the variable can not be reset
+	db, m, err := sqlmock.New()
+	assert.NoError(t, err)
+	m.ExpectBegin()
+	m.ExpectExec("SET SESSION max_execution_time = 1500").WillReturnResult(sqlmock.NewResult(0, 0))
+	m.ExpectExec("SET SESSION max_execution_time = DEFAULT").WillReturnError(errors.New("reset"))
+	m.ExpectRollback()
+	tx, err := (&Connection{DB: db, Dialect: dialect.MySQL, EventReceiver: nullReceiver}).NewSession(nil).Begin()
+	assert.NoError(t, err)
+	assert.NoError(t, tx.SetStatementTimeout(1500*time.Millisecond))
+	assert.EqualError(t, tx.Commit(), "reset")
+	assert.NoError(t, m.ExpectationsWereMet())
+
+	// ClickHouse sets the timeout by SETTINGS of SELECT queries, inserts are not changed
+	db, m, err = sqlmock.New()
+	assert.NoError(t, err)
+	m.ExpectBegin()
+	m.ExpectQuery(regexp.QuoteMeta("SELECT a FROM t SETTINGS max_execution_time=2") + "$").
+		WillReturnRows(sqlmock.NewRows([]string{"a"}).AddRow(1))
+	m.ExpectExec(regexp.QuoteMeta("INSERT INTO `t` (`a`) VALUES (1)") + "$").WillReturnResult(sqlmock.NewResult(0, 1))
+	m.ExpectCommit()
+	tx, err = (&Connection{DB: db, Dialect: dialect.ClickHouse, EventReceiver: nullReceiver}).NewSession(nil).Begin()
+	assert.NoError(t, err)
+	assert.NoError(t, tx.SetStatementTimeout(1500*time.Millisecond))
+	var a []int
+	_, err = tx.Select("a").From("t").Load(&a)
+	assert.NoError(t, err)
+	_, err = tx.InsertInto("t").Columns("a").Values(1).Exec()
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+	assert.NoError(t, m.ExpectationsWereMet())
+	assert.Equal(t, "SETTINGS max_execution_time=0", dialect.ClickHouse.StatementTimeoutSettings(0))
+	assert.Equal(t, "SETTINGS max_execution_time=1", dialect.ClickHouse.StatementTimeoutSettings(time.Millisecond))
+
+	db, m, err = sqlmock.New()
+	assert.NoError(t, err)
+	m.ExpectBegin()
+	tx, err = (&Connection{DB: db, Dialect: dialect.SQLite3, EventReceiver: nullReceiver}).NewSession(nil).Begin()
+	assert.NoError(t, err)
+	assert.Equal(t, ErrStmtTimeoutNotSupported, tx.SetStatementTimeout(time.Second))
+	assert.NoError(t, m.ExpectationsWereMet())
+}
+
+func TestTransactionAuditLog(t *testing.T) {
+	sess, m, _ := newRecordingSessionMock()
+	m.ExpectBegin()
+	m.ExpectExec("INSERT INTO `users`").WillReturnResult(sqlmock.NewResult(1, 1))
+	m.ExpectQuery("SELECT id FROM users").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	m.ExpectExec("UPDATE `users`").WillReturnResult(sqlmock.NewResult(0, 1))
+	m.ExpectCommit()
+
+	tx, err := sess.Begin()
+	assert.NoError(t, err)
+	tx.EnableAuditLog()
+
+	_, err = tx.InsertInto("users").Columns("name", "password").Values("alice", Sensitive("secret")).Exec()
+	assert.NoError(t, err)
+	var id int
+	err = tx.Select("id").From("users").Where(Eq("name", "alice")).LoadValue(&id)
+	assert.NoError(t, err)
+	_, err = tx.Update("users").Set("password", Sensitive([]byte("hash"))).Where(Eq("id", id)).Exec()
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+
+	assert.Equal(t, []string{
+		"INSERT INTO `users` (`name`,`password`) VALUES ('alice','***')",
+		"SELECT id FROM users WHERE (`name` = 'alice')",
+		"UPDATE `users` SET `password` = '***' WHERE (`id` = 1)",
+	}, tx.AuditLog())
+	assert.NoError(t, m.ExpectationsWereMet())
+
+	// the log is opt-in
+	m.ExpectBegin()
+	m.ExpectExec("DELETE FROM `users`").WillReturnResult(sqlmock.NewResult(0, 1))
+	tx, err = sess.Begin()
+	assert.NoError(t, err)
+	_, err = tx.DeleteFrom("users").Where(Eq("id", 1)).Exec()
+	assert.NoError(t, err)
+	assert.Empty(t, tx.AuditLog())
+}
+
+func TestSensitiveInterpolation(t *testing.T) {
+	query, err := InterpolateForDialect("SELECT ? IN ?", []interface{}{Sensitive("a"), []interface{}{1, Sensitive(2)}}, dialect.PostgreSQL)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT 'a' IN (1,2)", query)
+
+	buf := NewBuffer()
+	i := interpolator{Buffer: buf, Dialect: dialect.PostgreSQL, UsePlaceholders: true}
+	assert.NoError(t, i.interpolate("SELECT ?", []interface{}{Sensitive("a")}))
+	assert.Equal(t, "SELECT $1", buf.String())
+	assert.Equal(t, []interface{}{"a"}, buf.Value())
+}
@@ -1,6 +1,10 @@
 package dbr
 
-import "reflect"
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
 
 // UpdateStmt builds `UPDATE ...`
 type UpdateStmt interface {
@@ -10,12 +14,15 @@ type UpdateStmt interface {
 	Set(column string, value interface{}) UpdateStmt
 	SetMap(m map[string]interface{}) UpdateStmt
 	SetRecord(structValue interface{}) UpdateStmt
+	SetCase(column, keyColumn string, value interface{}) UpdateStmt
+	Only() UpdateStmt
 }
 
 type updateStmt struct {
 	raw
 
 	Table     string
+	IsOnly    bool
 	Value     map[string]interface{}
 	WhereCond []Builder
 }
@@ -35,11 +42,17 @@ func (b *updateStmt) Build(d Dialect, buf Buffer) error {
 	}
 
 	buf.WriteString("UPDATE ")
+	if b.IsOnly {
+		if !d.SupportsOnly() {
+			return ErrOnlyNotSupported
+		}
+		buf.WriteString("ONLY ")
+	}
 	buf.WriteString(d.QuoteIdent(b.Table))
 	buf.WriteString(" SET ")
 
-	i := 0
-	for col, v := range b.Value {
+	// columns are sorted, so the query is the same for the same values
+	for i, col := range sortedKeys(b.Value) {
 		if i > 0 {
 			buf.WriteString(", ")
 		}
@@ -47,8 +60,7 @@ func (b *updateStmt) Build(d Dialect, buf Buffer) error {
 		buf.WriteString(" = ")
 		buf.WriteString(placeholder)
 
-		buf.WriteValue(v)
-		i++
+		buf.WriteValue(b.Value[col])
 	}
 
 	if len(b.WhereCond) > 0 {
@@ -99,13 +111,20 @@ func (b *updateStmt) Where(query interface{}, value ...interface{}) UpdateStmt {
 	return b
 }
 
+// Only adds `ONLY` before the table, so rows of tables inheriting it are not updated.
+// It is supported by PostgreSQL only, other dialects return ErrOnlyNotSupported
+func (b *updateStmt) Only() UpdateStmt {
+	b.IsOnly = true
+	return b
+}
+
 // Set specifies a key-value pair
 func (b *updateStmt) Set(column string, value interface{}) UpdateStmt {
 	b.Value[column] = value
 	return b
 }
 
-// SetMap specifies a list of key-value pair
+// SetMap specifies a list of key-value pair, columns are written in sorted order
 func (b *updateStmt) SetMap(m map[string]interface{}) UpdateStmt {
 	for col, val := range m {
 		b.Set(col, val)
@@ -113,15 +132,55 @@ func (b *updateStmt) SetMap(m map[string]interface{}) UpdateStmt {
 	return b
 }
 
-// SetRecord specifies a record with field and values to set
+// SetCase sets column of many rows at once to the values of map value by their keys in keyColumn,
+// e.g. `col = CASE key WHEN 1 THEN 'a' WHEN 2 THEN 'b' ELSE col END`, and adds condition `key IN (1,2)`,
+// so only the rows of the keys are updated. Keys are sorted, so the query is the same for the same keys.
+// The query fails to be built if value is not a map
+func (b *updateStmt) SetCase(column, keyColumn string, value interface{}) UpdateStmt {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Map {
+		return b.Set(column, BuildFunc(func(Dialect, Buffer) error {
+			return fmt.Errorf("dbr: value of SetCase must be a map, not %T", value)
+		}))
+	}
+	keys := mapKeys(v.MapKeys())
+	sort.Sort(keys)
+	key := make([]interface{}, len(keys))
+	pairs := make([]interface{}, 0, 2*len(keys))
+	for i, k := range keys {
+		key[i] = k.Interface()
+		pairs = append(pairs, key[i], v.MapIndex(k).Interface())
+	}
+	b.Set(column, BuildFunc(func(d Dialect, buf Buffer) error {
+		if len(key) == 0 {
+			return ErrInvalidSliceLength
+		}
+		buf.WriteString("CASE ")
+		buf.WriteString(d.QuoteIdent(keyColumn))
+		for range key {
+			buf.WriteString(" WHEN ? THEN ?")
+		}
+		// rows of other keys keep their values, e.g. if the condition is changed by Where
+		buf.WriteString(" ELSE ")
+		buf.WriteString(d.QuoteIdent(column))
+		buf.WriteString(" END")
+		buf.WriteValue(pairs...)
+		return nil
+	}))
+	return b.Where(Eq(keyColumn, key))
+}
+
+// SetRecord specifies a record with field and values to set,
+// fields tagged as readonly are skipped. Time fields tagged as auto_update, e.g. `db:"updated_at,auto_update"`,
+// are set to the current time of NowFunc, fields tagged as auto_create are set as they are
 func (b *updateStmt) SetRecord(structValue interface{}) UpdateStmt {
 	v := reflect.Indirect(reflect.ValueOf(structValue))
 
 	if v.Kind() == reflect.Struct {
-		sm := structMap(v.Type())
+		sm := writableStructMap(v.Type())
 
 		for col, index := range sm {
-			b.Set(col, v.FieldByIndex(index).Interface())
+			b.Set(col, autoTimestamp(v, index, true))
 		}
 	}
 
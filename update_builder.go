@@ -15,7 +15,10 @@ type UpdateBuilder interface {
 	Where(query interface{}, value ...interface{}) UpdateBuilder
 	Set(column string, value interface{}) UpdateBuilder
 	SetMap(m map[string]interface{}) UpdateBuilder
+	SetCase(column, keyColumn string, value interface{}) UpdateBuilder
 	Limit(n uint64) UpdateBuilder
+	Only() UpdateBuilder
+	WithEventKv(key, value string) UpdateBuilder
 }
 
 type updateBuilder struct {
@@ -25,6 +28,7 @@ type updateBuilder struct {
 	Dialect    Dialect
 	updateStmt *updateStmt
 	LimitCount int64
+	eventKvs   kvs
 }
 
 // Update creates a UpdateBuilder
@@ -78,7 +82,7 @@ func (b *updateBuilder) Exec() (sql.Result, error) {
 
 // ExecContext executes the stmt
 func (b *updateBuilder) ExecContext(ctx context.Context) (sql.Result, error) {
-	return exec(ctx, b.runner, b.EventReceiver, b, b.Dialect)
+	return exec(ctx, b.runner, b.EventReceiver, b, b.Dialect, b.eventKvs)
 }
 
 // Set adds "SET column=value"
@@ -93,12 +97,25 @@ func (b *updateBuilder) SetMap(m map[string]interface{}) UpdateBuilder {
 	return b
 }
 
+// SetCase adds "SET column=CASE keyColumn WHEN key THEN value ... ELSE column END" for each key value pair in map value
+// and condition "keyColumn IN (key, ...)", so it updates many rows with different values at once
+func (b *updateBuilder) SetCase(column, keyColumn string, value interface{}) UpdateBuilder {
+	b.updateStmt.SetCase(column, keyColumn, value)
+	return b
+}
+
 // Where adds condition to the stmt
 func (b *updateBuilder) Where(query interface{}, value ...interface{}) UpdateBuilder {
 	b.updateStmt.Where(query, value...)
 	return b
 }
 
+// Only adds `ONLY` before the table, so rows of tables inheriting it are not updated, PostgreSQL only
+func (b *updateBuilder) Only() UpdateBuilder {
+	b.updateStmt.Only()
+	return b
+}
+
 // Limit adds LIMIT
 func (b *updateBuilder) Limit(n uint64) UpdateBuilder {
 	b.LimitCount = int64(n)
@@ -117,3 +134,13 @@ func (b *updateBuilder) Build(d Dialect, buf Buffer) error {
 	}
 	return nil
 }
+
+// WithEventKv adds a key/value pair to the events of this query,
+// it never overwrites the keys set by dbr itself, e.g. "sql"
+func (b *updateBuilder) WithEventKv(key, value string) UpdateBuilder {
+	if b.eventKvs == nil {
+		b.eventKvs = make(kvs)
+	}
+	b.eventKvs[key] = value
+	return b
+}
@@ -2,6 +2,7 @@ package dbr
 
 import (
 	"testing"
+	"time"
 
 	"github.com/mailru/dbr/dialect"
 	"github.com/stretchr/testify/assert"
@@ -17,6 +18,55 @@ func TestUpdateStmt(t *testing.T) {
 	assert.Equal(t, []interface{}{1, 2}, buf.Value())
 }
 
+func TestUpdateStmtOnly(t *testing.T) {
+	buf := NewBuffer()
+	err := Update("events").Only().Set("a", 1).Where(Eq("b", 2)).Build(dialect.PostgreSQL, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, `UPDATE ONLY "events" SET "a" = ? WHERE ("b" = ?)`, buf.String())
+
+	err = Update("events").Only().Set("a", 1).Build(dialect.SQLite3, NewBuffer())
+	assert.Equal(t, ErrOnlyNotSupported, err)
+}
+
+func TestUpdateStmtSetExpr(t *testing.T) {
+	buf := NewBuffer()
+	err := Update("counters").
+		Set("hits", Expr("hits + ?", 1)).
+		Set("name", "home").
+		Set("score", Expr("score * ? + ?", 2, Expr("bonus - ?", 3))).
+		Set("active", true).
+		Where(Eq("id", 4)).
+		Build(dialect.PostgreSQL, buf)
+	assert.NoError(t, err)
+
+	query, err := InterpolateForDialect(buf.String(), buf.Value(), dialect.PostgreSQL)
+	assert.NoError(t, err)
+	assert.Equal(t, `UPDATE "counters" SET "active" = TRUE, "hits" = hits + 1, "name" = 'home', "score" = score * 2 + bonus - 3 WHERE ("id" = 4)`, query)
+
+	i := interpolator{
+		Buffer:          NewBuffer(),
+		Dialect:         dialect.PostgreSQL,
+		UsePlaceholders: true,
+	}
+	err = i.interpolate(buf.String(), buf.Value())
+	assert.NoError(t, err)
+	assert.Equal(t, `UPDATE "counters" SET "active" = $1, "hits" = hits + $2, "name" = $3, "score" = score * $4 + bonus - $5 WHERE ("id" = $6)`, i.String())
+	assert.Equal(t, []interface{}{true, 1, "home", 2, 3, 4}, i.Value())
+}
+
+func TestUpdateStmtSetMapOrder(t *testing.T) {
+	m := map[string]interface{}{"d": 4, "b": 2, "a": 1, "e": 5, "c": 3}
+	// map iteration order is random, so the query is built many times
+	for n := 0; n < 20; n++ {
+		buf := NewBuffer()
+		err := Update("table").SetMap(m).Where(Eq("id", 6)).Build(dialect.MySQL, buf)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "UPDATE `table` SET `a` = ?, `b` = ?, `c` = ?, `d` = ?, `e` = ? WHERE (`id` = ?)", buf.String())
+		assert.Equal(t, []interface{}{1, 2, 3, 4, 5, 6}, buf.Value())
+	}
+}
+
 func TestUpdateStmtSetRecord(t *testing.T) {
 	record := struct{ A int }{A: 1}
 	buf := NewBuffer()
@@ -28,6 +78,76 @@ func TestUpdateStmtSetRecord(t *testing.T) {
 	assert.Equal(t, []interface{}{1, 2}, buf.Value())
 }
 
+func TestUpdateStmtSetCase(t *testing.T) {
+	status := map[int64]string{3: "done", 1: "new", 2: "failed"}
+	buf := NewBuffer()
+	err := Update("jobs").SetCase("status", "id", status).Set("updated_by", "cron").Where(Eq("queue", "mail")).Build(dialect.PostgreSQL, buf)
+	assert.NoError(t, err)
+
+	query, err := InterpolateForDialect(buf.String(), buf.Value(), dialect.PostgreSQL)
+	assert.NoError(t, err)
+	assert.Equal(t, `UPDATE "jobs" SET "status" = CASE "id" WHEN 1 THEN 'new' WHEN 2 THEN 'failed' WHEN 3 THEN 'done' ELSE "status" END, `+
+		`"updated_by" = 'cron' WHERE ("id" IN (1,2,3)) AND ("queue" = 'mail')`, query)
+
+	// values of CASE come before keys of IN
+	i := interpolator{Buffer: NewBuffer(), Dialect: dialect.PostgreSQL, UsePlaceholders: true}
+	err = i.interpolate(buf.String(), buf.Value())
+	assert.NoError(t, err)
+	assert.Equal(t, `UPDATE "jobs" SET "status" = CASE "id" WHEN $1 THEN $2 WHEN $3 THEN $4 WHEN $5 THEN $6 ELSE "status" END, `+
+		`"updated_by" = $7 WHERE ("id" IN ($8,$9,$10)) AND ("queue" = $11)`, i.String())
+	assert.Equal(t, []interface{}{
+		int64(1), "new", int64(2), "failed", int64(3), "done",
+		"cron",
+		int64(1), int64(2), int64(3),
+		"mail",
+	}, i.Value())
+
+	buf = NewBuffer()
+	err = Update("jobs").SetCase("status", "id", map[int64]string{}).Build(dialect.PostgreSQL, buf)
+	assert.NoError(t, err)
+	_, err = InterpolateForDialect(buf.String(), buf.Value(), dialect.PostgreSQL)
+	assert.Equal(t, ErrInvalidSliceLength, err)
+	buf = NewBuffer()
+	err = Update("jobs").SetCase("status", "id", []string{"done"}).Build(dialect.PostgreSQL, buf)
+	assert.NoError(t, err)
+	_, err = InterpolateForDialect(buf.String(), buf.Value(), dialect.PostgreSQL)
+	assert.EqualError(t, err, "dbr: value of SetCase must be a map, not []string")
+}
+
+func TestUpdateStmtSetRecordReadonly(t *testing.T) {
+	buf := NewBuffer()
+	builder := Update("table").SetRecord(&readonlyTest{A: 1, FullName: "one"}).Where(Eq("b", 2))
+	err := builder.Build(dialect.MySQL, buf)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "UPDATE `table` SET `a` = ? WHERE (`b` = ?)", buf.String())
+	assert.Equal(t, []interface{}{1, 2}, buf.Value())
+}
+
+func TestUpdateStmtSetRecordAutoTimestamps(t *testing.T) {
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	defer setNow(now)()
+
+	// auto_create is skipped on update, auto_update is set even if it is set already
+	created := now.Add(-time.Hour)
+	record := &timestampTest{A: 1, CreatedAt: created, UpdatedAt: &created}
+	buf := NewBuffer()
+	err := Update("table").SetRecord(record).Where(Eq("a", 1)).Build(dialect.PostgreSQL, buf)
+	assert.NoError(t, err)
+	query, err := InterpolateForDialect(buf.String(), buf.Value(), dialect.PostgreSQL)
+	assert.NoError(t, err)
+	assert.Equal(t, `UPDATE "table" SET "a" = 1, "created_at" = '2020-01-02 02:04:05.000000', "deleted_at" = NULL, "updated_at" = '2020-01-02 03:04:05.000000' WHERE ("a" = 1)`, query)
+	assert.Equal(t, created, record.CreatedAt)
+	assert.Equal(t, &now, record.UpdatedAt)
+
+	// a zero auto_create field is not set either
+	record = &timestampTest{A: 1}
+	err = Update("table").SetRecord(record).Build(dialect.PostgreSQL, NewBuffer())
+	assert.NoError(t, err)
+	assert.True(t, record.CreatedAt.IsZero())
+	assert.Equal(t, &now, record.UpdatedAt)
+}
+
 func BenchmarkUpdateValuesSQL(b *testing.B) {
 	buf := NewBuffer()
 	for i := 0; i < b.N; i++ {
@@ -4,6 +4,9 @@ import (
 	"bytes"
 	"database/sql/driver"
 	"reflect"
+	"sort"
+	"strings"
+	"sync"
 	"unicode"
 )
 
@@ -27,33 +30,131 @@ func camelCaseToSnakeCase(name string) string {
 // structMap builds index to fast lookup fields in struct
 func structMap(t reflect.Type) map[string][]int {
 	m := make(map[string][]int)
-	structTraverse(m, t, nil)
+	structTraverse(m, t, nil, false)
 	return m
 }
 
+// writableStructMap is like structMap, but omits fields tagged as readonly,
+// e.g. generated columns which must not be written by INSERT or UPDATE
+func writableStructMap(t reflect.Type) map[string][]int {
+	m := make(map[string][]int)
+	structTraverse(m, t, nil, true)
+	return m
+}
+
+// structColumnsCache caches structColumns by type
+var structColumnsCache sync.Map
+
+// structColumns returns columns of struct fields in declaration order,
+// fields of nested structs are included instead of the struct itself
+func structColumns(t reflect.Type) []string {
+	if column, ok := structColumnsCache.Load(t); ok {
+		return column.([]string)
+	}
+	m := structMap(t)
+	st := t
+	for st.Kind() == reflect.Ptr {
+		st = st.Elem()
+	}
+	column := make([]string, 0, len(m))
+	for key, index := range m {
+		field := st.FieldByIndex(index)
+		if len(structMap(field.Type)) > 0 {
+			// struct with its own columns
+			continue
+		}
+		column = append(column, key)
+	}
+	sort.Slice(column, func(i, j int) bool {
+		return indexLess(m[column[i]], m[column[j]])
+	})
+	structColumnsCache.Store(t, column)
+	return column
+}
+
+// indexLess compares field indexes, so fields are ordered as they are declared
+func indexLess(a, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
+
+// sortedKeys returns keys of m in order, so the query is the same for the same map
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// tagOptions are the comma-separated options that follow the column name
+// in a `db` tag, e.g. `db:"full_name,readonly"`
+type tagOptions string
+
+// parseTag splits a `db` tag into the column name and its options
+func parseTag(tag string) (string, tagOptions) {
+	if idx := strings.Index(tag, ","); idx != -1 {
+		return tag[:idx], tagOptions(tag[idx+1:])
+	}
+	return tag, ""
+}
+
+// Contains reports whether a comma-separated list of options contains a particular option
+func (o tagOptions) Contains(option string) bool {
+	s := string(o)
+	for s != "" {
+		var next string
+		if idx := strings.Index(s, ","); idx >= 0 {
+			s, next = s[:idx], s[idx+1:]
+		}
+		if s == option {
+			return true
+		}
+		s = next
+	}
+	return false
+}
+
 var (
 	typeValuer = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
 )
 
-func structTraverse(m map[string][]int, t reflect.Type, head []int) {
+func structTraverse(m map[string][]int, t reflect.Type, head []int, skipReadonly bool) {
 	if t.Implements(typeValuer) {
 		return
 	}
 	switch t.Kind() {
 	case reflect.Ptr:
-		structTraverse(m, t.Elem(), head)
+		structTraverse(m, t.Elem(), head, skipReadonly)
 	case reflect.Struct:
+		proto := isProtoMessage(t)
 		for i := 0; i < t.NumField(); i++ {
 			field := t.Field(i)
 			if field.PkgPath != "" && !field.Anonymous {
 				// unexported
 				continue
 			}
-			tag := field.Tag.Get("db")
+			tag, opts := parseTag(field.Tag.Get("db"))
 			if tag == "-" {
 				// ignore
 				continue
 			}
+			if skipReadonly && opts.Contains("readonly") {
+				// e.g. generated column, can be loaded but not written
+				continue
+			}
+			if tag == "" && proto {
+				tag = protoFieldName(field.Tag.Get("protobuf"))
+				if tag == "" {
+					// internal state or oneof wrapper of protobuf message
+					continue
+				}
+			}
 			if tag == "" {
 				// no tag, but we can record the field name
 				tag = camelCaseToSnakeCase(field.Name)
@@ -61,7 +162,7 @@ func structTraverse(m map[string][]int, t reflect.Type, head []int) {
 			if _, ok := m[tag]; !ok {
 				m[tag] = append(head, i)
 			}
-			structTraverse(m, field.Type, append(head, i))
+			structTraverse(m, field.Type, append(head, i), skipReadonly)
 		}
 	}
 }
@@ -87,8 +87,61 @@ func TestStructMap(t *testing.T) {
 			}{},
 			expected: map[string][]int{"test1": {0}, "test2": {0, 0}},
 		},
+		{
+			in: struct {
+				IntVal int `db:"test,readonly"`
+			}{},
+			expected: map[string][]int{"test": {0}},
+		},
 	} {
 		m := structMap(reflect.ValueOf(test.in).Type())
 		assert.Equal(t, test.expected, m)
 	}
 }
+
+func TestWritableStructMap(t *testing.T) {
+	v := struct {
+		ID       int64
+		FullName string `db:"full_name,readonly"`
+		Name     string `db:",readonly"`
+		Email    string `db:"email"`
+	}{}
+	m := writableStructMap(reflect.TypeOf(v))
+	assert.Equal(t, map[string][]int{"id": {0}, "email": {3}}, m)
+}
+
+func TestStructColumns(t *testing.T) {
+	for _, test := range []struct {
+		in       interface{}
+		expected []string
+	}{
+		{
+			in: struct {
+				Zeta      int
+				Alpha     string `db:"a"`
+				Ignored   int    `db:"-"`
+				CreatedAt time.Time
+				Name      NullString
+			}{},
+			expected: []string{"zeta", "a", "created_at", "name"},
+		},
+		{
+			in: &struct {
+				ID    int64
+				Test1 struct {
+					Test2 int
+					Test3 int
+				}
+				Test4 *struct {
+					Test5 int
+				}
+			}{},
+			expected: []string{"id", "test2", "test3", "test5"},
+		},
+	} {
+		typ := reflect.TypeOf(test.in)
+		assert.Equal(t, test.expected, structColumns(typ))
+		// cached
+		assert.Equal(t, test.expected, structColumns(typ))
+	}
+}
@@ -0,0 +1,47 @@
+package dbr
+
+import "strings"
+
+type valuesTable struct {
+	row    [][]interface{}
+	alias  string
+	column []string
+}
+
+// ValuesTable builds a derived table of rows, which can be used in FROM and joins instead of a temporary table,
+// e.g. `(VALUES (?,?),(?,?)) AS "t"("id","name")` in PostgreSQL. Values are interpolated row by row.
+// MySQL 8.0.19+ uses ROW constructors, SQLite3 selects unnamed columns of VALUES with aliases,
+// ClickHouse returns ErrValuesTableNotSupported
+func ValuesTable(row [][]interface{}, alias string, column ...string) Builder {
+	return &valuesTable{
+		row:    row,
+		alias:  alias,
+		column: column,
+	}
+}
+
+func (t *valuesTable) Build(d Dialect, buf Buffer) error {
+	if len(t.column) == 0 {
+		return ErrColumnNotSpecified
+	}
+	if len(t.row) == 0 {
+		return ErrInvalidSliceLength
+	}
+	placeholders := "(" + strings.Repeat(placeholder+",", len(t.column)-1) + placeholder + ")"
+	row := make([]string, len(t.row))
+	for i, r := range t.row {
+		if len(r) != len(t.column) {
+			return ErrPlaceholderCount
+		}
+		row[i] = placeholders
+	}
+	query := d.ValuesTable(row, t.alias, t.column)
+	if query == "" {
+		return ErrValuesTableNotSupported
+	}
+	buf.WriteString(query)
+	for _, r := range t.row {
+		buf.WriteValue(r...)
+	}
+	return nil
+}
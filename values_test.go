@@ -0,0 +1,56 @@
+package dbr
+
+import (
+	"testing"
+
+	"github.com/mailru/dbr/dialect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValuesTable(t *testing.T) {
+	rows := [][]interface{}{{1, "a"}, {2, "b"}}
+	for _, test := range []struct {
+		d     Dialect
+		query string
+	}{
+		{
+			d:     dialect.PostgreSQL,
+			query: `SELECT u.id, t.name FROM users AS u JOIN (VALUES (1,'a'),(2,'b')) AS "t"("id","name") ON u.id = t.id`,
+		},
+		{
+			d:     dialect.MySQL,
+			query: "SELECT u.id, t.name FROM users AS u JOIN (VALUES ROW(1,'a'),ROW(2,'b')) AS `t`(`id`,`name`) ON u.id = t.id",
+		},
+		{
+			d:     dialect.SQLite3,
+			query: `SELECT u.id, t.name FROM users AS u JOIN (SELECT column1 AS "id",column2 AS "name" FROM (VALUES (1,'a'),(2,'b'))) AS "t" ON u.id = t.id`,
+		},
+	} {
+		buf := NewBuffer()
+		err := Select("u.id", "t.name").From("users AS u").
+			Join(ValuesTable(rows, "t", "id", "name"), "u.id = t.id").
+			Build(test.d, buf)
+		assert.NoError(t, err)
+		query, err := InterpolateForDialect(buf.String(), buf.Value(), test.d)
+		assert.NoError(t, err)
+		assert.Equal(t, test.query, query)
+	}
+
+	buf := NewBuffer()
+	err := ValuesTable(rows, "t", "id", "name").Build(dialect.PostgreSQL, buf)
+	assert.NoError(t, err)
+	assert.Equal(t, `(VALUES (?,?),(?,?)) AS "t"("id","name")`, buf.String())
+	assert.Equal(t, []interface{}{1, "a", 2, "b"}, buf.Value())
+
+	buf = NewBuffer()
+	err = Select("*").From(ValuesTable(rows, "t", "id", "name")).Build(dialect.ClickHouse, buf)
+	assert.NoError(t, err)
+	_, err = InterpolateForDialect(buf.String(), buf.Value(), dialect.ClickHouse)
+	assert.Equal(t, ErrValuesTableNotSupported, err)
+	err = ValuesTable(rows, "t", "id").Build(dialect.PostgreSQL, NewBuffer())
+	assert.Equal(t, ErrPlaceholderCount, err)
+	err = ValuesTable(nil, "t", "id").Build(dialect.PostgreSQL, NewBuffer())
+	assert.Equal(t, ErrInvalidSliceLength, err)
+	err = ValuesTable(rows, "t").Build(dialect.PostgreSQL, NewBuffer())
+	assert.Equal(t, ErrColumnNotSpecified, err)
+}